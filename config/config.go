@@ -14,6 +14,19 @@ type Config struct {
 		ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 		SecretKey       string        `mapstructure:"secret_key"`
 		TokenDuration   time.Duration `mapstructure:"token_duration"`
+		// RefreshTokenDuration is how long a session lasts without its refresh token being exchanged
+		RefreshTokenDuration time.Duration `mapstructure:"refresh_token_duration"`
+		// SigningKeys are the RS256 or EdDSA keys of the tokens, tokens are signed with SecretKey when there are none
+		SigningKeys struct {
+			ActiveKeyID string `mapstructure:"active_key_id"`
+			Keys        []struct {
+				ID             string `mapstructure:"id"`
+				PrivateKeyFile string `mapstructure:"private_key_file"`
+				PublicKeyFile  string `mapstructure:"public_key_file"`
+			} `mapstructure:"keys"`
+		} `mapstructure:"signing_keys"`
+		// TrustedProxies are the addresses or CIDRs of the proxies whose X-Forwarded-For header tells the client IP
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
 	} `mapstructure:"app"`
 	Database struct {
 		Host     string `mapstructure:"host"`
@@ -22,6 +35,126 @@ type Config struct {
 		Password string `mapstructure:"password"`
 		Name     string `mapstructure:"name"`
 	} `mapstructure:"db"`
+	Restaurant struct {
+		SittingDuration    time.Duration `mapstructure:"sitting_duration"`
+		SlotInterval       time.Duration `mapstructure:"slot_interval"`
+		AllocationStrategy string        `mapstructure:"allocation_strategy"`
+		Currency           string        `mapstructure:"currency"`
+		// TimeZone is where the opening hours are, OpensAt and ClosesAt are times of day such as 12:00
+		TimeZone string `mapstructure:"time_zone"`
+		OpensAt  string `mapstructure:"opens_at"`
+		ClosesAt string `mapstructure:"closes_at"`
+	} `mapstructure:"restaurant"`
+	Pricing struct {
+		TimeZone            string `mapstructure:"time_zone"`
+		FullTableDiscount   bool   `mapstructure:"full_table_discount"`
+		TableSizeSeatPrices []struct {
+			SeatsCount int    `mapstructure:"seats_count"`
+			SeatPrice  string `mapstructure:"seat_price"`
+		} `mapstructure:"table_size_seat_prices"`
+		WeekdayMultiplier float64 `mapstructure:"weekday_multiplier"`
+		WeekendMultiplier float64 `mapstructure:"weekend_multiplier"`
+		PeakHours         []struct {
+			From       string  `mapstructure:"from"`
+			To         string  `mapstructure:"to"`
+			Multiplier float64 `mapstructure:"multiplier"`
+		} `mapstructure:"peak_hours"`
+		PartyMinimums []struct {
+			SeatsCount int    `mapstructure:"seats_count"`
+			Amount     string `mapstructure:"amount"`
+		} `mapstructure:"party_minimums"`
+		TaxRate           float64 `mapstructure:"tax_rate"`
+		TaxInclusive      bool    `mapstructure:"tax_inclusive"`
+		ServiceChargeRate float64 `mapstructure:"service_charge_rate"`
+	} `mapstructure:"pricing"`
+	LoginThrottling struct {
+		// Store is where failed logins are counted, "memory" for a single instance or "postgres"
+		Store   string                `mapstructure:"store"`
+		Account LoginThrottlingPolicy `mapstructure:"account"`
+		IP      LoginThrottlingPolicy `mapstructure:"ip"`
+	} `mapstructure:"login_throttling"`
+	PasswordReset struct {
+		TokenDuration time.Duration `mapstructure:"token_duration"`
+		// URL is the page of the client choosing the new password, the reset token is added as the token parameter
+		URL string `mapstructure:"url"`
+	} `mapstructure:"password_reset"`
+	EmailVerification struct {
+		TokenDuration time.Duration `mapstructure:"token_duration"`
+		// URL is the page of the client confirming the email, the verification token is added as the token parameter
+		URL string `mapstructure:"url"`
+		// ResendInterval is how long a user waits between verification emails, of which at most ResendLimit are sent
+		// within ResendWindow
+		ResendInterval time.Duration `mapstructure:"resend_interval"`
+		ResendLimit    int           `mapstructure:"resend_limit"`
+		ResendWindow   time.Duration `mapstructure:"resend_window"`
+	} `mapstructure:"email_verification"`
+	Notifications struct {
+		// Driver delivers the notifications to the users, "log" writes them to LogFile or to the standard error and
+		// "smtp" emails them through the SMTP server
+		Driver  string `mapstructure:"driver"`
+		LogFile string `mapstructure:"log_file"`
+		SMTP    struct {
+			Host     string `mapstructure:"host"`
+			Port     int    `mapstructure:"port"`
+			Username string `mapstructure:"username"`
+			Password string `mapstructure:"password"`
+			From     string `mapstructure:"from"`
+		} `mapstructure:"smtp"`
+	} `mapstructure:"notifications"`
+	TwoFactor struct {
+		// Issuer is the name authenticator apps show the codes of the restaurant under
+		Issuer string `mapstructure:"issuer"`
+		// ChallengeDuration is how long a login with a correct password has to be confirmed with a code
+		ChallengeDuration time.Duration `mapstructure:"challenge_duration"`
+		// RequiredRoles are the roles whose logins have to be confirmed with a second factor to use the staff routes
+		RequiredRoles []string `mapstructure:"required_roles"`
+	} `mapstructure:"two_factor"`
+	Password struct {
+		// Algorithm is what new passwords are hashed with, argon2id or bcrypt; hashes of the other one keep working
+		// and, like hashes of outdated parameters, are replaced on the next login
+		Algorithm string `mapstructure:"algorithm"`
+		Bcrypt    struct {
+			Cost int `mapstructure:"cost"`
+		} `mapstructure:"bcrypt"`
+		Argon2id struct {
+			// Memory is in KiB
+			Memory      uint32 `mapstructure:"memory"`
+			Iterations  uint32 `mapstructure:"iterations"`
+			Parallelism uint8  `mapstructure:"parallelism"`
+			SaltLength  uint32 `mapstructure:"salt_length"`
+			KeyLength   uint32 `mapstructure:"key_length"`
+		} `mapstructure:"argon2id"`
+		// MinLength is in characters, MaxLength in bytes and at most 72 with bcrypt
+		MinLength int `mapstructure:"min_length"`
+		MaxLength int `mapstructure:"max_length"`
+		// BreachedListFile is a list of one password or SHA-1 hash per line refused along with the built-in list of
+		// common passwords
+		BreachedListFile string `mapstructure:"breached_list_file"`
+	} `mapstructure:"password"`
+	OIDC struct {
+		// StateDuration is how long a sign-in started at a provider can be completed
+		StateDuration time.Duration `mapstructure:"state_duration"`
+		// Providers are the OpenID Connect providers users sign in with at /users/oidc/{name}, the RedirectURL
+		// registered at each is /users/oidc/{name}/callback
+		Providers []struct {
+			Name         string   `mapstructure:"name"`
+			Issuer       string   `mapstructure:"issuer"`
+			ClientID     string   `mapstructure:"client_id"`
+			ClientSecret string   `mapstructure:"client_secret"`
+			RedirectURL  string   `mapstructure:"redirect_url"`
+			Scopes       []string `mapstructure:"scopes"`
+		} `mapstructure:"providers"`
+	} `mapstructure:"oidc"`
+}
+
+// LoginThrottlingPolicy is how the failed logins of an account or IP address are throttled
+type LoginThrottlingPolicy struct {
+	FreeFailures    int           `mapstructure:"free_failures"`
+	BaseDelay       time.Duration `mapstructure:"base_delay"`
+	MaxDelay        time.Duration `mapstructure:"max_delay"`
+	MaxFailures     int           `mapstructure:"max_failures"`
+	LockoutDuration time.Duration `mapstructure:"lockout_duration"`
+	ResetAfter      time.Duration `mapstructure:"reset_after"`
 }
 
 func LoadConfig(path string, filename string) (*Config, error) {
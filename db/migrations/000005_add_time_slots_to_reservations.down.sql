@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS reservations_table_id_time_slot_idx;
+
+ALTER TABLE reservations ADD COLUMN date date;
+
+UPDATE reservations SET date = start_at::date;
+
+ALTER TABLE reservations
+    ALTER COLUMN date SET NOT NULL,
+    DROP CONSTRAINT IF EXISTS reservations_time_slot_check,
+    DROP COLUMN start_at,
+    DROP COLUMN end_at;
\ No newline at end of file
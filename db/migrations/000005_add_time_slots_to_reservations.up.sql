@@ -0,0 +1,16 @@
+ALTER TABLE reservations
+    ADD COLUMN start_at timestamptz,
+    ADD COLUMN end_at timestamptz;
+
+-- legacy day-level reservations occupy the table for the whole day
+UPDATE reservations
+SET start_at = date::timestamptz,
+    end_at = (date + 1)::timestamptz;
+
+ALTER TABLE reservations
+    ALTER COLUMN start_at SET NOT NULL,
+    ALTER COLUMN end_at SET NOT NULL,
+    ADD CONSTRAINT reservations_time_slot_check CHECK (end_at > start_at),
+    DROP COLUMN date;
+
+CREATE INDEX reservations_table_id_time_slot_idx ON reservations (table_id, start_at, end_at);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN is_staff boolean NOT NULL DEFAULT false;
\ No newline at end of file
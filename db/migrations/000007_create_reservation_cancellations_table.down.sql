@@ -0,0 +1 @@
+DROP TABLE IF EXISTS reservation_cancellations;
\ No newline at end of file
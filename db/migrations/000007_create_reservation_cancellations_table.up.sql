@@ -0,0 +1,9 @@
+CREATE TABLE reservation_cancellations(
+    id bigserial PRIMARY KEY,
+    reservation_id bigint NOT NULL,
+    reserved_by bigint REFERENCES users(id),
+    cancelled_by bigint NOT NULL REFERENCES users(id),
+    on_behalf_of_guest boolean NOT NULL DEFAULT false,
+    reason varchar,
+    cancelled_at timestamptz NOT NULL DEFAULT now()
+);
\ No newline at end of file
@@ -0,0 +1,8 @@
+DELETE FROM reservations WHERE status = 'cancelled';
+
+DROP INDEX IF EXISTS reservations_table_id_time_slot_idx;
+CREATE INDEX reservations_table_id_time_slot_idx ON reservations (table_id, start_at, end_at);
+
+ALTER TABLE reservations
+    DROP CONSTRAINT IF EXISTS reservations_status_check,
+    DROP COLUMN status;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE reservations
+    ADD COLUMN status varchar NOT NULL DEFAULT 'confirmed',
+    ADD CONSTRAINT reservations_status_check CHECK (status IN ('pending', 'confirmed', 'seated', 'completed', 'cancelled', 'no_show'));
+
+DROP INDEX IF EXISTS reservations_table_id_time_slot_idx;
+CREATE INDEX reservations_table_id_time_slot_idx ON reservations (table_id, start_at, end_at)
+    WHERE status IN ('pending', 'confirmed', 'seated');
\ No newline at end of file
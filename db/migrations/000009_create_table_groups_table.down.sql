@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS table_group_tables;
+DROP TABLE IF EXISTS table_groups;
\ No newline at end of file
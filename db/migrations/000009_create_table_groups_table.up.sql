@@ -0,0 +1,10 @@
+CREATE TABLE table_groups(
+    id bigserial PRIMARY KEY,
+    name varchar NOT NULL
+);
+
+CREATE TABLE table_group_tables(
+    table_group_id bigint NOT NULL REFERENCES table_groups(id) ON DELETE CASCADE,
+    table_id bigint NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
+    PRIMARY KEY (table_group_id, table_id)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS reservation_tables;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE reservation_tables(
+    reservation_id bigint NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    table_id bigint NOT NULL REFERENCES tables(id),
+    seats_count integer NOT NULL,
+    PRIMARY KEY (reservation_id, table_id)
+);
+
+CREATE INDEX reservation_tables_table_id_idx ON reservation_tables (table_id);
+
+INSERT INTO reservation_tables (reservation_id, table_id, seats_count)
+SELECT id, table_id, seats_count FROM reservations WHERE table_id IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE reservations DROP COLUMN price_breakdown;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE reservations ADD COLUMN price_breakdown jsonb NOT NULL DEFAULT '[]';
+
+UPDATE reservations
+SET price_breakdown = jsonb_build_array(jsonb_build_object(
+    'rule', 'legacy',
+    'description', 'price quoted before itemized pricing',
+    'amount', price
+));
\ No newline at end of file
@@ -0,0 +1,33 @@
+CREATE TEMPORARY TABLE currency_exponents (currency char(3) PRIMARY KEY, exponent int NOT NULL);
+INSERT INTO currency_exponents (currency, exponent) VALUES
+    ('AUD', 2), ('BHD', 3), ('CAD', 2), ('CHF', 2), ('EUR', 2), ('GBP', 2), ('IRR', 2), ('JPY', 0), ('KRW', 0), ('KWD', 3), ('OMR', 3), ('SEK', 2), ('TRY', 2), ('USD', 2);
+
+ALTER TABLE table_settings ADD COLUMN seat_price NUMERIC;
+UPDATE table_settings s
+SET seat_price = s.seat_price_amount / power(10::numeric, COALESCE(
+    (SELECT c.exponent FROM currency_exponents c WHERE c.currency = s.seat_price_currency), 2
+));
+ALTER TABLE table_settings
+    ALTER COLUMN seat_price SET NOT NULL,
+    DROP COLUMN seat_price_amount,
+    DROP COLUMN seat_price_currency;
+
+UPDATE reservations r
+SET price_breakdown = COALESCE((
+    SELECT jsonb_agg(l.line || jsonb_build_object('amount', (l.line->'amount'->>'minor_units')::numeric / power(10::numeric, COALESCE(
+        (SELECT c.exponent FROM currency_exponents c WHERE c.currency = r.price_currency), 2
+    ))) ORDER BY l.position)
+    FROM jsonb_array_elements(r.price_breakdown) WITH ORDINALITY AS l(line, position)
+), '[]');
+
+ALTER TABLE reservations ADD COLUMN price NUMERIC;
+UPDATE reservations r
+SET price = r.price_amount / power(10::numeric, COALESCE(
+    (SELECT c.exponent FROM currency_exponents c WHERE c.currency = r.price_currency), 2
+));
+ALTER TABLE reservations
+    ALTER COLUMN price SET NOT NULL,
+    DROP COLUMN price_amount,
+    DROP COLUMN price_currency;
+
+DROP TABLE currency_exponents;
\ No newline at end of file
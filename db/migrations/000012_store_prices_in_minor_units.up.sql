@@ -0,0 +1,60 @@
+-- prices are stored as an amount of minor units of their currency. Existing prices are in the restaurant currency,
+-- which the migration can not know, so it has to be given as the restaurant.currency setting of the session, e.g.
+-- with options=-c%20restaurant.currency%3DEUR in the database URL; a database without prices needs none
+CREATE TEMPORARY TABLE currency_exponents (currency char(3) PRIMARY KEY, exponent int NOT NULL);
+INSERT INTO currency_exponents (currency, exponent) VALUES
+    ('AUD', 2), ('BHD', 3), ('CAD', 2), ('CHF', 2), ('EUR', 2), ('GBP', 2), ('IRR', 2), ('JPY', 0), ('KRW', 0), ('KWD', 3), ('OMR', 3), ('SEK', 2), ('TRY', 2), ('USD', 2);
+
+CREATE TEMPORARY TABLE restaurant_currency AS
+SELECT currency, exponent
+FROM currency_exponents
+WHERE currency = upper(current_setting('restaurant.currency', true));
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM restaurant_currency)
+        AND (EXISTS (SELECT 1 FROM reservations) OR EXISTS (SELECT 1 FROM table_settings)) THEN
+        RAISE EXCEPTION 'the currency of the existing prices is unknown, set restaurant.currency to a supported currency of the restaurant config, e.g. options=-c%%20restaurant.currency%%3DEUR';
+    END IF;
+END $$;
+
+ALTER TABLE reservations
+    ADD COLUMN price_amount bigint,
+    ADD COLUMN price_currency char(3);
+
+UPDATE reservations
+SET price_amount = round(price * power(10::numeric, c.exponent)),
+    price_currency = c.currency
+FROM restaurant_currency c;
+
+UPDATE reservations r
+SET price_breakdown = COALESCE((
+    SELECT jsonb_agg(l.line || jsonb_build_object('amount', jsonb_build_object(
+        'amount', round((l.line->>'amount')::numeric, c.exponent)::text,
+        'minor_units', round((l.line->>'amount')::numeric * power(10::numeric, c.exponent))::bigint,
+        'currency', c.currency
+    )) ORDER BY l.position)
+    FROM jsonb_array_elements(r.price_breakdown) WITH ORDINALITY AS l(line, position)
+), '[]')
+FROM restaurant_currency c;
+
+ALTER TABLE reservations
+    ALTER COLUMN price_amount SET NOT NULL,
+    ALTER COLUMN price_currency SET NOT NULL,
+    DROP COLUMN price;
+
+ALTER TABLE table_settings
+    ADD COLUMN seat_price_amount bigint,
+    ADD COLUMN seat_price_currency char(3);
+
+UPDATE table_settings
+SET seat_price_amount = round(seat_price * power(10::numeric, c.exponent)),
+    seat_price_currency = c.currency
+FROM restaurant_currency c;
+
+ALTER TABLE table_settings
+    ALTER COLUMN seat_price_amount SET NOT NULL,
+    ALTER COLUMN seat_price_currency SET NOT NULL,
+    DROP COLUMN seat_price;
+
+DROP TABLE restaurant_currency, currency_exponents;
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE reservations
+    DROP CONSTRAINT IF EXISTS reservations_totals_check,
+    DROP COLUMN net_amount,
+    DROP COLUMN net_currency,
+    DROP COLUMN service_charge_amount,
+    DROP COLUMN service_charge_currency,
+    DROP COLUMN tax_amount,
+    DROP COLUMN tax_currency,
+    DROP COLUMN gross_amount,
+    DROP COLUMN gross_currency,
+    DROP COLUMN tax_rate,
+    DROP COLUMN tax_inclusive,
+    DROP COLUMN service_charge_rate;
\ No newline at end of file
@@ -0,0 +1,32 @@
+-- existing reservations were priced without tax or service charge, so their price is both their net and gross
+ALTER TABLE reservations
+    ADD COLUMN net_amount bigint,
+    ADD COLUMN net_currency char(3),
+    ADD COLUMN service_charge_amount bigint NOT NULL DEFAULT 0,
+    ADD COLUMN service_charge_currency char(3),
+    ADD COLUMN tax_amount bigint NOT NULL DEFAULT 0,
+    ADD COLUMN tax_currency char(3),
+    ADD COLUMN gross_amount bigint,
+    ADD COLUMN gross_currency char(3),
+    ADD COLUMN tax_rate numeric NOT NULL DEFAULT 0,
+    ADD COLUMN tax_inclusive boolean NOT NULL DEFAULT false,
+    ADD COLUMN service_charge_rate numeric NOT NULL DEFAULT 0;
+
+UPDATE reservations
+SET net_amount = price_amount,
+    net_currency = price_currency,
+    service_charge_currency = price_currency,
+    tax_currency = price_currency,
+    gross_amount = price_amount,
+    gross_currency = price_currency;
+
+ALTER TABLE reservations
+    ALTER COLUMN net_amount SET NOT NULL,
+    ALTER COLUMN net_currency SET NOT NULL,
+    ALTER COLUMN service_charge_amount DROP DEFAULT,
+    ALTER COLUMN service_charge_currency SET NOT NULL,
+    ALTER COLUMN tax_amount DROP DEFAULT,
+    ALTER COLUMN tax_currency SET NOT NULL,
+    ALTER COLUMN gross_amount SET NOT NULL,
+    ALTER COLUMN gross_currency SET NOT NULL,
+    ADD CONSTRAINT reservations_totals_check CHECK (gross_amount = net_amount + service_charge_amount + tax_amount);
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE tables
+    DROP COLUMN name,
+    DROP COLUMN zone,
+    DROP COLUMN attributes,
+    DROP COLUMN retired_at;
\ No newline at end of file
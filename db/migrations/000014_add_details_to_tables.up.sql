@@ -0,0 +1,7 @@
+ALTER TABLE tables
+    ADD COLUMN name varchar NOT NULL DEFAULT '',
+    ADD COLUMN zone varchar NOT NULL DEFAULT '',
+    ADD COLUMN attributes jsonb NOT NULL DEFAULT '[]',
+    ADD COLUMN retired_at timestamptz;
+
+UPDATE tables SET name = 'Table ' || id;
\ No newline at end of file
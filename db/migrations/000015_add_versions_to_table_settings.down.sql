@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS table_settings_effective_from_idx;
+
+ALTER TABLE table_settings
+    DROP COLUMN effective_from,
+    DROP COLUMN created_at;
\ No newline at end of file
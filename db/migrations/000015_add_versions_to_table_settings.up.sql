@@ -0,0 +1,11 @@
+-- the settings seeded so far have always been in effect, rows used to be interchangeable so they are kept in id order
+ALTER TABLE table_settings
+    ADD COLUMN effective_from timestamptz,
+    ADD COLUMN created_at timestamptz NOT NULL DEFAULT now();
+
+UPDATE table_settings
+SET effective_from = 'epoch'::timestamptz + (id - (SELECT MIN(id) FROM table_settings)) * interval '1 second';
+
+ALTER TABLE table_settings ALTER COLUMN effective_from SET NOT NULL;
+
+CREATE UNIQUE INDEX table_settings_effective_from_idx ON table_settings (effective_from);
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN is_staff boolean NOT NULL DEFAULT false;
+
+UPDATE users SET is_staff = true WHERE role <> 'guest';
+
+ALTER TABLE users DROP COLUMN role;
\ No newline at end of file
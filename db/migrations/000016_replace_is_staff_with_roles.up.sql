@@ -0,0 +1,7 @@
+-- staff members could change reservation statuses, which managers still can
+ALTER TABLE users ADD COLUMN role varchar NOT NULL DEFAULT 'guest'
+    CHECK (role IN ('guest', 'host', 'manager', 'admin'));
+
+UPDATE users SET role = 'manager' WHERE is_staff;
+
+ALTER TABLE users DROP COLUMN is_staff;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE sessions(
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_hash varchar NOT NULL UNIQUE,
+    previous_refresh_token_hash varchar NOT NULL DEFAULT '',
+    access_token_id varchar NOT NULL,
+    access_token_expires_at timestamptz NOT NULL,
+    expires_at timestamptz NOT NULL,
+    revoked_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX sessions_previous_refresh_token_hash_idx ON sessions (previous_refresh_token_hash);
+
+CREATE TABLE revoked_tokens(
+    token_id varchar PRIMARY KEY,
+    expires_at timestamptz NOT NULL
+);
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE login_attempts(
+    kind varchar NOT NULL CHECK (kind IN ('account', 'ip')),
+    subject varchar NOT NULL,
+    failures int NOT NULL DEFAULT 0,
+    last_failure_at timestamptz NOT NULL,
+    locked_until timestamptz,
+    PRIMARY KEY (kind, subject)
+);
+
+CREATE INDEX login_attempts_last_failure_at_idx ON login_attempts (last_failure_at);
\ No newline at end of file
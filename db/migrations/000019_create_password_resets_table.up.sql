@@ -0,0 +1,10 @@
+CREATE TABLE password_resets(
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash varchar NOT NULL UNIQUE,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX password_resets_user_id_idx ON password_resets (user_id);
\ No newline at end of file
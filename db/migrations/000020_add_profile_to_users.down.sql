@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS users_deleted_at_idx;
+DROP INDEX IF EXISTS users_email_key;
+DROP INDEX IF EXISTS users_username_key;
+ALTER TABLE users ADD CONSTRAINT users_username_key UNIQUE (username);
+
+ALTER TABLE users
+    DROP COLUMN deleted_at,
+    DROP COLUMN language,
+    DROP COLUMN phone,
+    DROP COLUMN email,
+    DROP COLUMN name;
\ No newline at end of file
@@ -0,0 +1,12 @@
+ALTER TABLE users
+    ADD COLUMN name varchar NOT NULL DEFAULT '',
+    ADD COLUMN email varchar NOT NULL DEFAULT '',
+    ADD COLUMN phone varchar NOT NULL DEFAULT '',
+    ADD COLUMN language varchar NOT NULL DEFAULT '',
+    ADD COLUMN deleted_at timestamptz;
+
+-- deleted accounts give up their username and email, emails are stored in lower case
+ALTER TABLE users DROP CONSTRAINT users_username_key;
+CREATE UNIQUE INDEX users_username_key ON users (username) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX users_email_key ON users (email) WHERE email <> '' AND deleted_at IS NULL;
+CREATE INDEX users_deleted_at_idx ON users (deleted_at);
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE sessions DROP COLUMN two_factor;
+
+DROP TABLE IF EXISTS backup_codes;
+
+DROP TABLE IF EXISTS two_factors;
\ No newline at end of file
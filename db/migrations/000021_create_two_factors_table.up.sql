@@ -0,0 +1,19 @@
+CREATE TABLE two_factors(
+    user_id bigint PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret varchar NOT NULL,
+    enabled_at timestamptz,
+    last_used_step bigint NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE backup_codes(
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash varchar NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (user_id, code_hash)
+);
+
+-- whether the login of the session was confirmed with a second factor
+ALTER TABLE sessions ADD COLUMN two_factor boolean NOT NULL DEFAULT false;
\ No newline at end of file
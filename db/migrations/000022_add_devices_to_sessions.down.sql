@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS sessions_user_id_idx;
+
+ALTER TABLE sessions
+    DROP COLUMN last_seen_at,
+    DROP COLUMN ip_address,
+    DROP COLUMN user_agent;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- the device a session was started on and when it was last used
+ALTER TABLE sessions
+    ADD COLUMN user_agent varchar NOT NULL DEFAULT '',
+    ADD COLUMN ip_address varchar NOT NULL DEFAULT '',
+    ADD COLUMN last_seen_at timestamptz NOT NULL DEFAULT now();
+
+UPDATE sessions SET last_seen_at = updated_at;
+
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE users ADD COLUMN email_verified_at timestamptz;
+
+-- accounts registered before emails were verified can keep booking
+UPDATE users SET email_verified_at = created_at WHERE deleted_at IS NULL;
+
+CREATE TABLE email_verifications(
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email varchar NOT NULL,
+    token_hash varchar NOT NULL UNIQUE,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX email_verifications_user_id_created_at_idx ON email_verifications (user_id, created_at);
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE user_identities(
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider varchar NOT NULL,
+    subject varchar NOT NULL,
+    email varchar NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX user_identities_provider_subject_key ON user_identities (provider, subject);
+CREATE UNIQUE INDEX user_identities_user_id_provider_key ON user_identities (user_id, provider);
+
+CREATE TABLE oidc_login_states(
+    id bigserial PRIMARY KEY,
+    state_hash varchar NOT NULL UNIQUE,
+    provider varchar NOT NULL,
+    nonce varchar NOT NULL,
+    code_verifier varchar NOT NULL,
+    user_id bigint REFERENCES users(id) ON DELETE CASCADE,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+CREATE INDEX sessions_previous_refresh_token_hash_idx ON sessions (previous_refresh_token_hash);
+
+DROP TABLE exchanged_refresh_tokens;
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- every refresh token a session exchanged is kept, presenting any of them again means the session leaked
+CREATE TABLE exchanged_refresh_tokens(
+    token_hash varchar PRIMARY KEY,
+    session_id bigint NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    exchanged_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX exchanged_refresh_tokens_session_id_idx ON exchanged_refresh_tokens (session_id);
+
+INSERT INTO exchanged_refresh_tokens (token_hash, session_id, exchanged_at)
+SELECT previous_refresh_token_hash, id, updated_at
+FROM sessions
+WHERE previous_refresh_token_hash <> '';
+
+DROP INDEX sessions_previous_refresh_token_hash_idx;
\ No newline at end of file
@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -package mockdb -destination db/mock/identity_repository_mock.go -mock_names Repository=IdentityMockRepository github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity Repository
+//
+
+// Package mockdb is a generated GoMock package.
+package mockdb
+
+import (
+	context "context"
+	reflect "reflect"
+
+	identity "github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	user "github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// IdentityMockRepository is a mock of Repository interface.
+type IdentityMockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *IdentityMockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// IdentityMockRepositoryMockRecorder is the mock recorder for IdentityMockRepository.
+type IdentityMockRepositoryMockRecorder struct {
+	mock *IdentityMockRepository
+}
+
+// NewIdentityMockRepository creates a new mock instance.
+func NewIdentityMockRepository(ctrl *gomock.Controller) *IdentityMockRepository {
+	mock := &IdentityMockRepository{ctrl: ctrl}
+	mock.recorder = &IdentityMockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *IdentityMockRepository) EXPECT() *IdentityMockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeLoginState mocks base method.
+func (m *IdentityMockRepository) ConsumeLoginState(ctx context.Context, stateHash string) (*identity.LoginState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeLoginState", ctx, stateHash)
+	ret0, _ := ret[0].(*identity.LoginState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeLoginState indicates an expected call of ConsumeLoginState.
+func (mr *IdentityMockRepositoryMockRecorder) ConsumeLoginState(ctx, stateHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeLoginState", reflect.TypeOf((*IdentityMockRepository)(nil).ConsumeLoginState), ctx, stateHash)
+}
+
+// CreateLoginState mocks base method.
+func (m *IdentityMockRepository) CreateLoginState(ctx context.Context, state *identity.LoginState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginState", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLoginState indicates an expected call of CreateLoginState.
+func (mr *IdentityMockRepositoryMockRecorder) CreateLoginState(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginState", reflect.TypeOf((*IdentityMockRepository)(nil).CreateLoginState), ctx, state)
+}
+
+// FindIdentity mocks base method.
+func (m *IdentityMockRepository) FindIdentity(ctx context.Context, provider, subject string) (*identity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(*identity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentity indicates an expected call of FindIdentity.
+func (mr *IdentityMockRepositoryMockRecorder) FindIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentity", reflect.TypeOf((*IdentityMockRepository)(nil).FindIdentity), ctx, provider, subject)
+}
+
+// LinkIdentity mocks base method.
+func (m *IdentityMockRepository) LinkIdentity(ctx context.Context, identity *identity.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkIdentity indicates an expected call of LinkIdentity.
+func (mr *IdentityMockRepositoryMockRecorder) LinkIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkIdentity", reflect.TypeOf((*IdentityMockRepository)(nil).LinkIdentity), ctx, identity)
+}
+
+// ListUserIdentities mocks base method.
+func (m *IdentityMockRepository) ListUserIdentities(ctx context.Context, userID int) ([]identity.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserIdentities", ctx, userID)
+	ret0, _ := ret[0].([]identity.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserIdentities indicates an expected call of ListUserIdentities.
+func (mr *IdentityMockRepositoryMockRecorder) ListUserIdentities(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserIdentities", reflect.TypeOf((*IdentityMockRepository)(nil).ListUserIdentities), ctx, userID)
+}
+
+// RegisterUser mocks base method.
+func (m *IdentityMockRepository) RegisterUser(ctx context.Context, u *user.User, identity *identity.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterUser", ctx, u, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterUser indicates an expected call of RegisterUser.
+func (mr *IdentityMockRepositoryMockRecorder) RegisterUser(ctx, u, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*IdentityMockRepository)(nil).RegisterUser), ctx, u, identity)
+}
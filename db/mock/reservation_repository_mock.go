@@ -43,30 +43,121 @@ func (m *ReservationMockRepository) EXPECT() *ReservationMockRepositoryMockRecor
 }
 
 // BookTable mocks base method.
-func (m *ReservationMockRepository) BookTable(ctx context.Context, userID, seatsNeeded int, date time.Time) (*reservation.Reservation, error) {
+func (m *ReservationMockRepository) BookTable(ctx context.Context, userID, seatsNeeded int, startAt time.Time, duration time.Duration) (*reservation.Reservation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BookTable", ctx, userID, seatsNeeded, date)
+	ret := m.ctrl.Call(m, "BookTable", ctx, userID, seatsNeeded, startAt, duration)
 	ret0, _ := ret[0].(*reservation.Reservation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // BookTable indicates an expected call of BookTable.
-func (mr *ReservationMockRepositoryMockRecorder) BookTable(ctx, userID, seatsNeeded, date any) *gomock.Call {
+func (mr *ReservationMockRepositoryMockRecorder) BookTable(ctx, userID, seatsNeeded, startAt, duration any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookTable", reflect.TypeOf((*ReservationMockRepository)(nil).BookTable), ctx, userID, seatsNeeded, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookTable", reflect.TypeOf((*ReservationMockRepository)(nil).BookTable), ctx, userID, seatsNeeded, startAt, duration)
 }
 
 // CancelReservation mocks base method.
-func (m *ReservationMockRepository) CancelReservation(ctx context.Context, reservationID int) error {
+func (m *ReservationMockRepository) CancelReservation(ctx context.Context, reservationID, userID int, canCancelAny bool, reason string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CancelReservation", ctx, reservationID)
+	ret := m.ctrl.Call(m, "CancelReservation", ctx, reservationID, userID, canCancelAny, reason)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CancelReservation indicates an expected call of CancelReservation.
-func (mr *ReservationMockRepositoryMockRecorder) CancelReservation(ctx, reservationID any) *gomock.Call {
+func (mr *ReservationMockRepositoryMockRecorder) CancelReservation(ctx, reservationID, userID, canCancelAny, reason any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelReservation", reflect.TypeOf((*ReservationMockRepository)(nil).CancelReservation), ctx, reservationID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelReservation", reflect.TypeOf((*ReservationMockRepository)(nil).CancelReservation), ctx, reservationID, userID, canCancelAny, reason)
+}
+
+// FindAvailability mocks base method.
+func (m *ReservationMockRepository) FindAvailability(ctx context.Context, seatsNeeded int, from, to time.Time, duration, interval time.Duration) ([]reservation.Availability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAvailability", ctx, seatsNeeded, from, to, duration, interval)
+	ret0, _ := ret[0].([]reservation.Availability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAvailability indicates an expected call of FindAvailability.
+func (mr *ReservationMockRepositoryMockRecorder) FindAvailability(ctx, seatsNeeded, from, to, duration, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAvailability", reflect.TypeOf((*ReservationMockRepository)(nil).FindAvailability), ctx, seatsNeeded, from, to, duration, interval)
+}
+
+// FindByID mocks base method.
+func (m *ReservationMockRepository) FindByID(ctx context.Context, reservationID int) (*reservation.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, reservationID)
+	ret0, _ := ret[0].(*reservation.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *ReservationMockRepositoryMockRecorder) FindByID(ctx, reservationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*ReservationMockRepository)(nil).FindByID), ctx, reservationID)
+}
+
+// ListByUser mocks base method.
+func (m *ReservationMockRepository) ListByUser(ctx context.Context, userID int, filter reservation.ListFilter) ([]reservation.Reservation, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, filter)
+	ret0, _ := ret[0].([]reservation.Reservation)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *ReservationMockRepositoryMockRecorder) ListByUser(ctx, userID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*ReservationMockRepository)(nil).ListByUser), ctx, userID, filter)
+}
+
+// ModifyReservation mocks base method.
+func (m *ReservationMockRepository) ModifyReservation(ctx context.Context, reservationID, userID int, modification reservation.Modification) (*reservation.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyReservation", ctx, reservationID, userID, modification)
+	ret0, _ := ret[0].(*reservation.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModifyReservation indicates an expected call of ModifyReservation.
+func (mr *ReservationMockRepositoryMockRecorder) ModifyReservation(ctx, reservationID, userID, modification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyReservation", reflect.TypeOf((*ReservationMockRepository)(nil).ModifyReservation), ctx, reservationID, userID, modification)
+}
+
+// ReassignTable mocks base method.
+func (m *ReservationMockRepository) ReassignTable(ctx context.Context, tableID uint) ([]reservation.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignTable", ctx, tableID)
+	ret0, _ := ret[0].([]reservation.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignTable indicates an expected call of ReassignTable.
+func (mr *ReservationMockRepositoryMockRecorder) ReassignTable(ctx, tableID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignTable", reflect.TypeOf((*ReservationMockRepository)(nil).ReassignTable), ctx, tableID)
+}
+
+// UpdateStatus mocks base method.
+func (m *ReservationMockRepository) UpdateStatus(ctx context.Context, reservationID int, status reservation.Status) (*reservation.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, reservationID, status)
+	ret0, _ := ret[0].(*reservation.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *ReservationMockRepositoryMockRecorder) UpdateStatus(ctx, reservationID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*ReservationMockRepository)(nil).UpdateStatus), ctx, reservationID, status)
 }
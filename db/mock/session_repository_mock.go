@@ -0,0 +1,173 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -package mockdb -destination db/mock/session_repository_mock.go -mock_names Repository=SessionMockRepository github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session Repository
+//
+
+// Package mockdb is a generated GoMock package.
+package mockdb
+
+import (
+	context "context"
+	reflect "reflect"
+
+	session "github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// SessionMockRepository is a mock of Repository interface.
+type SessionMockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *SessionMockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// SessionMockRepositoryMockRecorder is the mock recorder for SessionMockRepository.
+type SessionMockRepositoryMockRecorder struct {
+	mock *SessionMockRepository
+}
+
+// NewSessionMockRepository creates a new mock instance.
+func NewSessionMockRepository(ctrl *gomock.Controller) *SessionMockRepository {
+	mock := &SessionMockRepository{ctrl: ctrl}
+	mock.recorder = &SessionMockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *SessionMockRepository) EXPECT() *SessionMockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSession mocks base method.
+func (m *SessionMockRepository) CreateSession(ctx context.Context, session *session.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *SessionMockRepositoryMockRecorder) CreateSession(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*SessionMockRepository)(nil).CreateSession), ctx, session)
+}
+
+// FindByID mocks base method.
+func (m *SessionMockRepository) FindByID(ctx context.Context, sessionID int) (*session.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, sessionID)
+	ret0, _ := ret[0].(*session.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *SessionMockRepositoryMockRecorder) FindByID(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*SessionMockRepository)(nil).FindByID), ctx, sessionID)
+}
+
+// FindByRefreshToken mocks base method.
+func (m *SessionMockRepository) FindByRefreshToken(ctx context.Context, refreshTokenHash string) (*session.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByRefreshToken", ctx, refreshTokenHash)
+	ret0, _ := ret[0].(*session.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByRefreshToken indicates an expected call of FindByRefreshToken.
+func (mr *SessionMockRepositoryMockRecorder) FindByRefreshToken(ctx, refreshTokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByRefreshToken", reflect.TypeOf((*SessionMockRepository)(nil).FindByRefreshToken), ctx, refreshTokenHash)
+}
+
+// ListActiveUserSessions mocks base method.
+func (m *SessionMockRepository) ListActiveUserSessions(ctx context.Context, userID int) ([]session.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveUserSessions", ctx, userID)
+	ret0, _ := ret[0].([]session.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveUserSessions indicates an expected call of ListActiveUserSessions.
+func (mr *SessionMockRepositoryMockRecorder) ListActiveUserSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveUserSessions", reflect.TypeOf((*SessionMockRepository)(nil).ListActiveUserSessions), ctx, userID)
+}
+
+// RevokeOtherUserSessions mocks base method.
+func (m *SessionMockRepository) RevokeOtherUserSessions(ctx context.Context, userID, sessionID int) ([]session.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherUserSessions", ctx, userID, sessionID)
+	ret0, _ := ret[0].([]session.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOtherUserSessions indicates an expected call of RevokeOtherUserSessions.
+func (mr *SessionMockRepositoryMockRecorder) RevokeOtherUserSessions(ctx, userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherUserSessions", reflect.TypeOf((*SessionMockRepository)(nil).RevokeOtherUserSessions), ctx, userID, sessionID)
+}
+
+// RevokeSession mocks base method.
+func (m *SessionMockRepository) RevokeSession(ctx context.Context, sessionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *SessionMockRepositoryMockRecorder) RevokeSession(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*SessionMockRepository)(nil).RevokeSession), ctx, sessionID)
+}
+
+// RevokeUserSessions mocks base method.
+func (m *SessionMockRepository) RevokeUserSessions(ctx context.Context, userID int) ([]session.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessions", ctx, userID)
+	ret0, _ := ret[0].([]session.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeUserSessions indicates an expected call of RevokeUserSessions.
+func (mr *SessionMockRepositoryMockRecorder) RevokeUserSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessions", reflect.TypeOf((*SessionMockRepository)(nil).RevokeUserSessions), ctx, userID)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *SessionMockRepository) RotateRefreshToken(ctx context.Context, session *session.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *SessionMockRepositoryMockRecorder) RotateRefreshToken(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*SessionMockRepository)(nil).RotateRefreshToken), ctx, session)
+}
+
+// TouchSession mocks base method.
+func (m *SessionMockRepository) TouchSession(ctx context.Context, sessionID, userID int, ipAddress string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchSession", ctx, sessionID, userID, ipAddress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchSession indicates an expected call of TouchSession.
+func (mr *SessionMockRepositoryMockRecorder) TouchSession(ctx, sessionID, userID, ipAddress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchSession", reflect.TypeOf((*SessionMockRepository)(nil).TouchSession), ctx, sessionID, userID, ipAddress)
+}
@@ -0,0 +1,201 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -package mockdb -destination db/mock/table_repository_mock.go -mock_names Repository=TableMockRepository github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table Repository
+//
+
+// Package mockdb is a generated GoMock package.
+package mockdb
+
+import (
+	context "context"
+	reflect "reflect"
+
+	money "github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	table "github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// TableMockRepository is a mock of Repository interface.
+type TableMockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *TableMockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// TableMockRepositoryMockRecorder is the mock recorder for TableMockRepository.
+type TableMockRepositoryMockRecorder struct {
+	mock *TableMockRepository
+}
+
+// NewTableMockRepository creates a new mock instance.
+func NewTableMockRepository(ctrl *gomock.Controller) *TableMockRepository {
+	mock := &TableMockRepository{ctrl: ctrl}
+	mock.recorder = &TableMockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *TableMockRepository) EXPECT() *TableMockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateGroup mocks base method.
+func (m *TableMockRepository) CreateGroup(ctx context.Context, group *table.Group) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", ctx, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *TableMockRepositoryMockRecorder) CreateGroup(ctx, group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*TableMockRepository)(nil).CreateGroup), ctx, group)
+}
+
+// CreateTable mocks base method.
+func (m *TableMockRepository) CreateTable(ctx context.Context, table *table.Table) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTable", ctx, table)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTable indicates an expected call of CreateTable.
+func (mr *TableMockRepositoryMockRecorder) CreateTable(ctx, table any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTable", reflect.TypeOf((*TableMockRepository)(nil).CreateTable), ctx, table)
+}
+
+// CreateTableSettings mocks base method.
+func (m *TableMockRepository) CreateTableSettings(ctx context.Context, seatPrice money.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTableSettings", ctx, seatPrice)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTableSettings indicates an expected call of CreateTableSettings.
+func (mr *TableMockRepositoryMockRecorder) CreateTableSettings(ctx, seatPrice any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTableSettings", reflect.TypeOf((*TableMockRepository)(nil).CreateTableSettings), ctx, seatPrice)
+}
+
+// DeleteScheduledSettings mocks base method.
+func (m *TableMockRepository) DeleteScheduledSettings(ctx context.Context, settingsID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledSettings", ctx, settingsID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScheduledSettings indicates an expected call of DeleteScheduledSettings.
+func (mr *TableMockRepositoryMockRecorder) DeleteScheduledSettings(ctx, settingsID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledSettings", reflect.TypeOf((*TableMockRepository)(nil).DeleteScheduledSettings), ctx, settingsID)
+}
+
+// FindTableByID mocks base method.
+func (m *TableMockRepository) FindTableByID(ctx context.Context, tableID int) (*table.Table, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTableByID", ctx, tableID)
+	ret0, _ := ret[0].(*table.Table)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTableByID indicates an expected call of FindTableByID.
+func (mr *TableMockRepositoryMockRecorder) FindTableByID(ctx, tableID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTableByID", reflect.TypeOf((*TableMockRepository)(nil).FindTableByID), ctx, tableID)
+}
+
+// GetTotalCount mocks base method.
+func (m *TableMockRepository) GetTotalCount(ctx context.Context) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalCount", ctx)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetTotalCount indicates an expected call of GetTotalCount.
+func (mr *TableMockRepositoryMockRecorder) GetTotalCount(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalCount", reflect.TypeOf((*TableMockRepository)(nil).GetTotalCount), ctx)
+}
+
+// ListSettings mocks base method.
+func (m *TableMockRepository) ListSettings(ctx context.Context) (table.SettingsHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSettings", ctx)
+	ret0, _ := ret[0].(table.SettingsHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSettings indicates an expected call of ListSettings.
+func (mr *TableMockRepositoryMockRecorder) ListSettings(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSettings", reflect.TypeOf((*TableMockRepository)(nil).ListSettings), ctx)
+}
+
+// ListTables mocks base method.
+func (m *TableMockRepository) ListTables(ctx context.Context, includeRetired bool) ([]table.Table, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTables", ctx, includeRetired)
+	ret0, _ := ret[0].([]table.Table)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTables indicates an expected call of ListTables.
+func (mr *TableMockRepositoryMockRecorder) ListTables(ctx, includeRetired any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTables", reflect.TypeOf((*TableMockRepository)(nil).ListTables), ctx, includeRetired)
+}
+
+// RetireTable mocks base method.
+func (m *TableMockRepository) RetireTable(ctx context.Context, tableID int) (*table.Table, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireTable", ctx, tableID)
+	ret0, _ := ret[0].(*table.Table)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetireTable indicates an expected call of RetireTable.
+func (mr *TableMockRepositoryMockRecorder) RetireTable(ctx, tableID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireTable", reflect.TypeOf((*TableMockRepository)(nil).RetireTable), ctx, tableID)
+}
+
+// ScheduleSettings mocks base method.
+func (m *TableMockRepository) ScheduleSettings(ctx context.Context, settings *table.Settings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleSettings", ctx, settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleSettings indicates an expected call of ScheduleSettings.
+func (mr *TableMockRepositoryMockRecorder) ScheduleSettings(ctx, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleSettings", reflect.TypeOf((*TableMockRepository)(nil).ScheduleSettings), ctx, settings)
+}
+
+// UpdateTable mocks base method.
+func (m *TableMockRepository) UpdateTable(ctx context.Context, table *table.Table) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTable", ctx, table)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTable indicates an expected call of UpdateTable.
+func (mr *TableMockRepositoryMockRecorder) UpdateTable(ctx, table any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTable", reflect.TypeOf((*TableMockRepository)(nil).UpdateTable), ctx, table)
+}
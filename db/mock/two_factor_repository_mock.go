@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor (interfaces: Repository)
+//
+// Generated by this command:
+//
+//	mockgen -package mockdb -destination db/mock/two_factor_repository_mock.go -mock_names Repository=TwoFactorMockRepository github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor Repository
+//
+
+// Package mockdb is a generated GoMock package.
+package mockdb
+
+import (
+	context "context"
+	reflect "reflect"
+
+	twofactor "github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// TwoFactorMockRepository is a mock of Repository interface.
+type TwoFactorMockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *TwoFactorMockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// TwoFactorMockRepositoryMockRecorder is the mock recorder for TwoFactorMockRepository.
+type TwoFactorMockRepositoryMockRecorder struct {
+	mock *TwoFactorMockRepository
+}
+
+// NewTwoFactorMockRepository creates a new mock instance.
+func NewTwoFactorMockRepository(ctrl *gomock.Controller) *TwoFactorMockRepository {
+	mock := &TwoFactorMockRepository{ctrl: ctrl}
+	mock.recorder = &TwoFactorMockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *TwoFactorMockRepository) EXPECT() *TwoFactorMockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateEnrolment mocks base method.
+func (m *TwoFactorMockRepository) CreateEnrolment(ctx context.Context, twoFactor *twofactor.TwoFactor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEnrolment", ctx, twoFactor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEnrolment indicates an expected call of CreateEnrolment.
+func (mr *TwoFactorMockRepositoryMockRecorder) CreateEnrolment(ctx, twoFactor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEnrolment", reflect.TypeOf((*TwoFactorMockRepository)(nil).CreateEnrolment), ctx, twoFactor)
+}
+
+// Disable mocks base method.
+func (m *TwoFactorMockRepository) Disable(ctx context.Context, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *TwoFactorMockRepositoryMockRecorder) Disable(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*TwoFactorMockRepository)(nil).Disable), ctx, userID)
+}
+
+// Enable mocks base method.
+func (m *TwoFactorMockRepository) Enable(ctx context.Context, twoFactor *twofactor.TwoFactor, step int64, backupCodeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", ctx, twoFactor, step, backupCodeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *TwoFactorMockRepositoryMockRecorder) Enable(ctx, twoFactor, step, backupCodeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*TwoFactorMockRepository)(nil).Enable), ctx, twoFactor, step, backupCodeHashes)
+}
+
+// FindByUserID mocks base method.
+func (m *TwoFactorMockRepository) FindByUserID(ctx context.Context, userID int) (*twofactor.TwoFactor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, userID)
+	ret0, _ := ret[0].(*twofactor.TwoFactor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *TwoFactorMockRepositoryMockRecorder) FindByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*TwoFactorMockRepository)(nil).FindByUserID), ctx, userID)
+}
+
+// ReplaceBackupCodes mocks base method.
+func (m *TwoFactorMockRepository) ReplaceBackupCodes(ctx context.Context, userID int, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceBackupCodes", ctx, userID, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceBackupCodes indicates an expected call of ReplaceBackupCodes.
+func (mr *TwoFactorMockRepositoryMockRecorder) ReplaceBackupCodes(ctx, userID, codeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceBackupCodes", reflect.TypeOf((*TwoFactorMockRepository)(nil).ReplaceBackupCodes), ctx, userID, codeHashes)
+}
+
+// UseBackupCode mocks base method.
+func (m *TwoFactorMockRepository) UseBackupCode(ctx context.Context, userID int, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseBackupCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseBackupCode indicates an expected call of UseBackupCode.
+func (mr *TwoFactorMockRepositoryMockRecorder) UseBackupCode(ctx, userID, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseBackupCode", reflect.TypeOf((*TwoFactorMockRepository)(nil).UseBackupCode), ctx, userID, codeHash)
+}
+
+// UseStep mocks base method.
+func (m *TwoFactorMockRepository) UseStep(ctx context.Context, userID int, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseStep", ctx, userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseStep indicates an expected call of UseStep.
+func (mr *TwoFactorMockRepositoryMockRecorder) UseStep(ctx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseStep", reflect.TypeOf((*TwoFactorMockRepository)(nil).UseStep), ctx, userID, step)
+}
@@ -12,6 +12,7 @@ package mockdb
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	user "github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,78 @@ func (m *UserMockRepository) EXPECT() *UserMockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CreateEmailVerification mocks base method.
+func (m *UserMockRepository) CreateEmailVerification(ctx context.Context, verification *user.EmailVerification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmailVerification", ctx, verification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateEmailVerification indicates an expected call of CreateEmailVerification.
+func (mr *UserMockRepositoryMockRecorder) CreateEmailVerification(ctx, verification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailVerification", reflect.TypeOf((*UserMockRepository)(nil).CreateEmailVerification), ctx, verification)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *UserMockRepository) CreatePasswordReset(ctx context.Context, reset *user.PasswordReset) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordReset", ctx, reset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *UserMockRepositoryMockRecorder) CreatePasswordReset(ctx, reset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*UserMockRepository)(nil).CreatePasswordReset), ctx, reset)
+}
+
+// DeleteUser mocks base method.
+func (m *UserMockRepository) DeleteUser(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *UserMockRepositoryMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*UserMockRepository)(nil).DeleteUser), ctx, id)
+}
+
+// FindByEmail mocks base method.
+func (m *UserMockRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByEmail", ctx, email)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByEmail indicates an expected call of FindByEmail.
+func (mr *UserMockRepositoryMockRecorder) FindByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*UserMockRepository)(nil).FindByEmail), ctx, email)
+}
+
+// FindByID mocks base method.
+func (m *UserMockRepository) FindByID(ctx context.Context, id int) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *UserMockRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*UserMockRepository)(nil).FindByID), ctx, id)
+}
+
 // FindByUsername mocks base method.
 func (m *UserMockRepository) FindByUsername(ctx context.Context, username string) (*user.User, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +129,21 @@ func (mr *UserMockRepositoryMockRecorder) FindByUsername(ctx, username any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUsername", reflect.TypeOf((*UserMockRepository)(nil).FindByUsername), ctx, username)
 }
 
+// ListEmailVerificationsSince mocks base method.
+func (m *UserMockRepository) ListEmailVerificationsSince(ctx context.Context, userID int, since time.Time) ([]user.EmailVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEmailVerificationsSince", ctx, userID, since)
+	ret0, _ := ret[0].([]user.EmailVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEmailVerificationsSince indicates an expected call of ListEmailVerificationsSince.
+func (mr *UserMockRepositoryMockRecorder) ListEmailVerificationsSince(ctx, userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEmailVerificationsSince", reflect.TypeOf((*UserMockRepository)(nil).ListEmailVerificationsSince), ctx, userID, since)
+}
+
 // Register mocks base method.
 func (m *UserMockRepository) Register(ctx context.Context, user *user.User) error {
 	m.ctrl.T.Helper()
@@ -69,3 +157,76 @@ func (mr *UserMockRepositoryMockRecorder) Register(ctx, user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*UserMockRepository)(nil).Register), ctx, user)
 }
+
+// ResetPassword mocks base method.
+func (m *UserMockRepository) ResetPassword(ctx context.Context, tokenHash, password string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, tokenHash, password)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *UserMockRepositoryMockRecorder) ResetPassword(ctx, tokenHash, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*UserMockRepository)(nil).ResetPassword), ctx, tokenHash, password)
+}
+
+// UpdatePassword mocks base method.
+func (m *UserMockRepository) UpdatePassword(ctx context.Context, id int, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, id, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *UserMockRepositoryMockRecorder) UpdatePassword(ctx, id, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*UserMockRepository)(nil).UpdatePassword), ctx, id, password)
+}
+
+// UpdateProfile mocks base method.
+func (m *UserMockRepository) UpdateProfile(ctx context.Context, user *user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *UserMockRepositoryMockRecorder) UpdateProfile(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*UserMockRepository)(nil).UpdateProfile), ctx, user)
+}
+
+// UpdateRole mocks base method.
+func (m *UserMockRepository) UpdateRole(ctx context.Context, id int, role user.Role) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, id, role)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *UserMockRepositoryMockRecorder) UpdateRole(ctx, id, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*UserMockRepository)(nil).UpdateRole), ctx, id, role)
+}
+
+// VerifyEmail mocks base method.
+func (m *UserMockRepository) VerifyEmail(ctx context.Context, tokenHash string) (*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, tokenHash)
+	ret0, _ := ret[0].(*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *UserMockRepositoryMockRecorder) VerifyEmail(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*UserMockRepository)(nil).VerifyEmail), ctx, tokenHash)
+}
@@ -0,0 +1,175 @@
+package actions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+// maxAvailabilityRange is the longest period availability can be searched for at once
+const maxAvailabilityRange = 7 * 24 * time.Hour
+
+// AvailabilityRequest represents the query string for searching availability,
+// either date or both from and to must be given
+type AvailabilityRequest struct {
+	Date            string `form:"date"`
+	From            string `form:"from"`
+	To              string `form:"to"`
+	Seats           int    `form:"seats" binding:"required,min=1,max=30"`
+	DurationMinutes int    `form:"duration_minutes" binding:"omitempty,min=15,max=720"`
+}
+
+// AvailableTableResponse represents a table, or a group of joinable tables, that can host the party in a time slot
+type AvailableTableResponse struct {
+	TableIDs       []int       `json:"table_ids"`
+	AvailableSeats int         `json:"available_seats"`
+	Price          money.Money `json:"price"`
+}
+
+// AvailableSlotResponse represents a time slot with at least one table that can host the party,
+// its price is the one quoted for the table a booking would get
+type AvailableSlotResponse struct {
+	StartAt time.Time                `json:"start_at"`
+	EndAt   time.Time                `json:"end_at"`
+	Price   money.Money              `json:"price"`
+	Tables  []AvailableTableResponse `json:"tables"`
+}
+
+// AvailabilityResponse represents the response body for searching availability
+type AvailabilityResponse struct {
+	SeatsCount      int                     `json:"seats_count"`
+	DurationMinutes int                     `json:"duration_minutes"`
+	Slots           []AvailableSlotResponse `json:"slots"`
+}
+
+// AvailabilityAction is a function that handles searching the available time slots and tables without booking,
+// a date is searched from the opening of the restaurant to its closing on that day, a range within the opening hours
+// of every day it covers
+func AvailabilityAction(repository reservation.Repository, sittingDuration time.Duration, slotInterval time.Duration, openingHours reservation.OpeningHours) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request AvailabilityRequest
+		if err := ctx.ShouldBindQuery(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		duration := sittingDuration
+		if request.DurationMinutes > 0 {
+			duration = time.Duration(request.DurationMinutes) * time.Minute
+		}
+
+		if slotInterval <= 0 {
+			slotInterval = duration
+		}
+
+		var from, to time.Time
+		var err error
+		switch {
+		case request.Date != "":
+			date, err := time.Parse(time.DateOnly, request.Date)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, expected a calendar date (e.g. 2025-01-01)"})
+				return
+			}
+			// the slots of today that already started are left out
+			from, to = sittings(openingHours, date, duration, slotInterval, time.Now())
+		case request.From != "" && request.To != "":
+			if from, err = time.Parse(time.RFC3339, request.From); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from format, expected RFC 3339"})
+				return
+			}
+			if to, err = time.Parse(time.RFC3339, request.To); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to format, expected RFC 3339"})
+				return
+			}
+			if to.Before(from) || to.Sub(from) > maxAvailabilityRange {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid range, to should be after from and at most 7 days later"})
+				return
+			}
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Either date or both from and to are required"})
+			return
+		}
+
+		if time.Now().After(from) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, should be in the future"})
+			return
+		}
+
+		seatsCount := request.Seats
+		if seatsCount%2 != 0 {
+			seatsCount++
+		}
+
+		// the days of the restaurant covering a range may start on the day before from and end on the day after to,
+		// depending on its location and on whether it closes after midnight
+		windows := [][2]time.Time{{from, to}}
+		if request.Date == "" {
+			windows = windows[:0]
+			for date := from.AddDate(0, 0, -1); date.Before(to.AddDate(0, 0, 2)); date = date.AddDate(0, 0, 1) {
+				first, last := sittings(openingHours, date, duration, slotInterval, from)
+				if last.After(to) {
+					last = to
+				}
+				if first.Before(from) || last.Before(first) {
+					continue
+				}
+				windows = append(windows, [2]time.Time{first, last})
+			}
+		}
+
+		var availabilities []reservation.Availability
+		for _, window := range windows {
+			found, err := repository.FindAvailability(ctx, seatsCount, window[0], window[1], duration, slotInterval)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			availabilities = append(availabilities, found...)
+		}
+
+		res := AvailabilityResponse{
+			SeatsCount:      seatsCount,
+			DurationMinutes: int(duration.Minutes()),
+			Slots:           []AvailableSlotResponse{},
+		}
+		for _, availability := range availabilities {
+			table := AvailableTableResponse{
+				TableIDs:       toInts(availability.TableIDs),
+				AvailableSeats: availability.AvailableSeats,
+				Price:          availability.Price,
+			}
+
+			// availabilities are ordered by slot, the first table of a slot is the one a booking would get
+			last := len(res.Slots) - 1
+			if last >= 0 && res.Slots[last].StartAt.Equal(availability.StartAt) {
+				res.Slots[last].Tables = append(res.Slots[last].Tables, table)
+				continue
+			}
+			res.Slots = append(res.Slots, AvailableSlotResponse{
+				StartAt: availability.StartAt,
+				EndAt:   availability.EndAt,
+				Price:   availability.Price,
+				Tables:  []AvailableTableResponse{table},
+			})
+		}
+		ctx.JSON(http.StatusOK, res)
+	}
+}
+
+// sittings returns the start of the first and of the last sitting on the day of date, the last one ends when the
+// restaurant closes. Sittings every slotInterval from the opening are offered, those starting before notBefore are
+// left out unless the whole day is.
+func sittings(openingHours reservation.OpeningHours, date time.Time, duration time.Duration, slotInterval time.Duration, notBefore time.Time) (first, last time.Time) {
+	opensAt, closesAt := openingHours.Day(date)
+	first, last = opensAt, closesAt.Add(-duration)
+
+	if first.Before(notBefore) && !last.Before(notBefore) {
+		first = first.Add((notBefore.Sub(first) + slotInterval - 1) / slotInterval * slotInterval)
+	}
+
+	return first, last
+}
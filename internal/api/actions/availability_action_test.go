@@ -0,0 +1,201 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAvailabilityAction(t *testing.T) {
+	duration := c.Restaurant.SittingDuration
+	// the testing restaurant is open from 12:00 to 23:00 UTC
+	day := time.Now().AddDate(0, 0, 1).UTC()
+	opensAt := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, time.UTC)
+	lastSittingAt := time.Date(day.Year(), day.Month(), day.Day(), 23, 0, 0, 0, time.UTC).Add(-duration)
+	from := opensAt.Add(time.Hour)
+	to := from.Add(time.Hour)
+	// a range from 21:00 to 13:00 the next day only covers the sittings before closing and after opening
+	lateFrom := opensAt.Add(9 * time.Hour)
+	nextOpensAt := opensAt.AddDate(0, 0, 1)
+	earlyTo := nextOpensAt.Add(time.Hour)
+	testCases := []struct {
+		name          string
+		query         url.Values
+		buildStubs    func(repository *mockdb.ReservationMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "without seats",
+			query: url.Values{"date": {day.Format(time.DateOnly)}},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().FindAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "without date or range",
+			query: url.Values{"seats": {"4"}},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().FindAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "range too long",
+			query: url.Values{
+				"seats": {"4"},
+				"from":  {from.Format(time.RFC3339)},
+				"to":    {from.AddDate(0, 0, 8).Format(time.RFC3339)},
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().FindAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "instant instead of date",
+			query: url.Values{"seats": {"4"}, "date": {from.Format(time.RFC3339)}},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().FindAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "past date",
+			query: url.Values{"seats": {"4"}, "date": {time.Now().AddDate(0, 0, -1).UTC().Format(time.DateOnly)}},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().FindAvailability(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "single date",
+			query: url.Values{"seats": {"3"}, "date": {day.Format(time.DateOnly)}},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().
+					FindAvailability(gomock.Any(), 4, opensAt, lastSittingAt, duration, c.Restaurant.SlotInterval).
+					Return([]reservation.Availability{
+						{StartAt: opensAt, EndAt: opensAt.Add(duration), TableIDs: []uint{1}, AvailableSeats: 4, Price: money.New(3000, "USD")},
+						{StartAt: opensAt, EndAt: opensAt.Add(duration), TableIDs: []uint{5}, AvailableSeats: 6, Price: money.New(4000, "USD")},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.AvailabilityResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, 4, resp.SeatsCount)
+				require.Len(t, resp.Slots, 1)
+				require.Equal(t, money.New(3000, "USD"), resp.Slots[0].Price)
+				require.Len(t, resp.Slots[0].Tables, 2)
+				require.Equal(t, []int{1}, resp.Slots[0].Tables[0].TableIDs)
+			},
+		},
+		{
+			name: "date range",
+			query: url.Values{
+				"seats":            {"2"},
+				"from":             {from.Format(time.RFC3339)},
+				"to":               {to.Format(time.RFC3339)},
+				"duration_minutes": {"60"},
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().
+					FindAvailability(gomock.Any(), 2, from, to, time.Hour, c.Restaurant.SlotInterval).
+					Return([]reservation.Availability{
+						{StartAt: from, EndAt: from.Add(time.Hour), TableIDs: []uint{1}, AvailableSeats: 2, Price: money.New(1000, "USD")},
+						{StartAt: to, EndAt: to.Add(time.Hour), TableIDs: []uint{2}, AvailableSeats: 4, Price: money.New(2000, "USD")},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.AvailabilityResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, 60, resp.DurationMinutes)
+				require.Len(t, resp.Slots, 2)
+				require.Equal(t, money.New(2000, "USD"), resp.Slots[1].Price)
+			},
+		},
+		{
+			name: "date range across closing time",
+			query: url.Values{
+				"seats":            {"2"},
+				"from":             {lateFrom.Format(time.RFC3339)},
+				"to":               {earlyTo.Format(time.RFC3339)},
+				"duration_minutes": {"60"},
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				gomock.InOrder(
+					repository.EXPECT().
+						FindAvailability(gomock.Any(), 2, lateFrom, opensAt.Add(10*time.Hour), time.Hour, c.Restaurant.SlotInterval).
+						Return([]reservation.Availability{
+							{StartAt: lateFrom, EndAt: lateFrom.Add(time.Hour), TableIDs: []uint{1}, AvailableSeats: 2, Price: money.New(1000, "USD")},
+						}, nil),
+					repository.EXPECT().
+						FindAvailability(gomock.Any(), 2, nextOpensAt, earlyTo, time.Hour, c.Restaurant.SlotInterval).
+						Return([]reservation.Availability{
+							{StartAt: nextOpensAt, EndAt: nextOpensAt.Add(time.Hour), TableIDs: []uint{1}, AvailableSeats: 2, Price: money.New(1000, "USD")},
+						}, nil),
+				)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.AvailabilityResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Len(t, resp.Slots, 2)
+				require.True(t, lateFrom.Equal(resp.Slots[0].StartAt))
+				require.True(t, nextOpensAt.Equal(resp.Slots[1].StartAt))
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewReservationMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetReservationRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/availability?"+tc.query.Encode(), nil)
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -7,25 +7,34 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
 )
 
 // BookRequest represents the request body for booking
 type BookRequest struct {
-	SeatsCount int    `json:"seats_count" binding:"required,min=1,max=10"`
-	Date       string `json:"date" binding:"required"`
+	SeatsCount      int    `json:"seats_count" binding:"required,min=1,max=30"`
+	Date            string `json:"date" binding:"required"`
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=15,max=720"`
 }
 
 // BookResponse represents the response body for booking
 type BookResponse struct {
-	ID         int     `json:"id"`
-	TableID    int     `json:"table_id"`
-	SeatsCount int     `json:"seats_count"`
-	Price      float64 `json:"price"`
+	ID              int                     `json:"id"`
+	TableID         int                     `json:"table_id"`
+	TableIDs        []int                   `json:"table_ids"`
+	SeatsCount      int                     `json:"seats_count"`
+	Price           money.Money             `json:"price"`
+	PriceBreakdown  []reservation.PriceLine `json:"price_breakdown"`
+	Totals          TotalsResponse          `json:"totals"`
+	StartAt         time.Time               `json:"start_at"`
+	EndAt           time.Time               `json:"end_at"`
+	DurationMinutes int                     `json:"duration_minutes"`
+	Status          reservation.Status      `json:"status"`
 }
 
 // BookAction is a function that handles the book action
-func BookAction(reservationRepo reservation.Repository) gin.HandlerFunc {
+func BookAction(reservationRepo reservation.Repository, sittingDuration time.Duration) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var requestBody BookRequest
 		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
@@ -33,18 +42,23 @@ func BookAction(reservationRepo reservation.Repository) gin.HandlerFunc {
 			return
 		}
 
-		// Validate date format (YYYY-MM-DD)
-		date, err := time.Parse("2006-01-02", requestBody.Date)
+		// Validate date format (RFC 3339)
+		startAt, err := time.Parse(time.RFC3339, requestBody.Date)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, expected YYYY-MM-DD"})
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, expected RFC 3339 (e.g. 2025-01-01T19:30:00Z)"})
 			return
 		}
 
-		if time.Now().After(date) {
+		if time.Now().After(startAt) {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, should be in the future"})
 			return
 		}
 
+		duration := sittingDuration
+		if requestBody.DurationMinutes > 0 {
+			duration = time.Duration(requestBody.DurationMinutes) * time.Minute
+		}
+
 		seatsCount := requestBody.SeatsCount
 		if seatsCount%2 != 0 {
 			seatsCount++
@@ -52,7 +66,7 @@ func BookAction(reservationRepo reservation.Repository) gin.HandlerFunc {
 
 		userID := ctx.MustGet(middlewares.AuthUserIDKey).(int)
 
-		resv, err := reservationRepo.BookTable(ctx, userID, seatsCount, date)
+		resv, err := reservationRepo.BookTable(ctx, userID, seatsCount, startAt, duration)
 		if err != nil {
 			if errors.Is(err, reservation.ErrNoTablesAreAvailable) {
 				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -63,10 +77,17 @@ func BookAction(reservationRepo reservation.Repository) gin.HandlerFunc {
 		}
 
 		res := BookResponse{
-			ID:         int(resv.ID),
-			TableID:    int(resv.TableID),
-			SeatsCount: resv.SeatsCount,
-			Price:      resv.Price,
+			ID:              int(resv.ID),
+			TableID:         int(resv.TableID),
+			TableIDs:        toInts(resv.TableIDs()),
+			SeatsCount:      resv.SeatsCount,
+			Price:           resv.Price,
+			PriceBreakdown:  resv.PriceBreakdown,
+			Totals:          newTotalsResponse(resv.Totals),
+			StartAt:         resv.StartAt,
+			EndAt:           resv.EndAt,
+			DurationMinutes: int(resv.Duration().Minutes()),
+			Status:          resv.Status,
 		}
 		ctx.JSON(http.StatusOK, res)
 	}
@@ -2,6 +2,7 @@ package actions_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,7 +14,9 @@ import (
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -21,7 +24,7 @@ import (
 
 func TestBookAction(t *testing.T) {
 	userID := 1
-	seatPrice := 10
+	seatPrice := money.New(1000, "USD")
 	testCases := []struct {
 		name          string
 		requestBody   bookRequest
@@ -33,21 +36,31 @@ func TestBookAction(t *testing.T) {
 			name: "booking a complete table",
 			requestBody: bookRequest{
 				SeatsCount: 4,
-				Date:       time.Now().AddDate(0, 0, 1).Format("2006-01-02"),
+				Date:       time.Now().AddDate(0, 0, 1).Format(time.RFC3339),
 			},
 			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
-				token, err := manager.GenerateToken(userID, c.App.TokenDuration)
+				token, err := manager.GenerateToken(userID, user.RoleGuest, c.App.TokenDuration)
 				require.NoError(t, err)
 				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
 			},
 			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody bookRequest) {
 				repository.EXPECT().
-					BookTable(gomock.Any(), userID, requestBody.SeatsCount, gomock.Any()).
+					BookTable(gomock.Any(), userID, requestBody.SeatsCount, gomock.Any(), c.Restaurant.SittingDuration).
 					Return(&reservation.Reservation{
 						ID:         1,
 						TableID:    1,
 						SeatsCount: requestBody.SeatsCount,
-						Price:      float64(seatPrice * (requestBody.SeatsCount - 1)),
+						Price:      money.New(seatPrice.Amount*int64(requestBody.SeatsCount-1), "USD"),
+						PriceBreakdown: reservation.PriceBreakdown{
+							{Rule: "seats", Amount: money.New(seatPrice.Amount*int64(requestBody.SeatsCount), "USD")},
+							{Rule: "full_table_discount", Amount: seatPrice.Neg()},
+						},
+						Totals: reservation.Totals{
+							Net:           money.New(seatPrice.Amount*int64(requestBody.SeatsCount-1), "USD"),
+							ServiceCharge: money.Zero("USD"),
+							Tax:           money.Zero("USD"),
+							Gross:         money.New(seatPrice.Amount*int64(requestBody.SeatsCount-1), "USD"),
+						},
 					}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody bookRequest) {
@@ -58,7 +71,12 @@ func TestBookAction(t *testing.T) {
 				require.NoError(t, err)
 
 				require.NotEmpty(t, resp.ID)
-				require.Equal(t, float64(seatPrice*(requestBody.SeatsCount-1)), resp.Price)
+				require.Equal(t, money.New(seatPrice.Amount*int64(requestBody.SeatsCount-1), "USD"), resp.Price)
+				require.Len(t, resp.PriceBreakdown, 2)
+				require.Equal(t, "full_table_discount", resp.PriceBreakdown[1].Rule)
+				require.Equal(t, seatPrice.Neg(), resp.PriceBreakdown[1].Amount)
+				require.Equal(t, resp.Price, resp.Totals.Gross)
+				require.Equal(t, money.Zero("USD"), resp.Totals.Tax)
 				require.Equal(t, requestBody.SeatsCount, resp.SeatsCount)
 				require.NotEmpty(t, resp.TableID)
 			},
@@ -67,21 +85,21 @@ func TestBookAction(t *testing.T) {
 			name: "booking a odd number of seats",
 			requestBody: bookRequest{
 				SeatsCount: 3,
-				Date:       time.Now().AddDate(0, 0, 1).Format("2006-01-02"),
+				Date:       time.Now().AddDate(0, 0, 1).Format(time.RFC3339),
 			},
 			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
-				token, err := manager.GenerateToken(userID, c.App.TokenDuration)
+				token, err := manager.GenerateToken(userID, user.RoleGuest, c.App.TokenDuration)
 				require.NoError(t, err)
 				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
 			},
 			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody bookRequest) {
 				repository.EXPECT().
-					BookTable(gomock.Any(), userID, requestBody.SeatsCount+1, gomock.Any()).
+					BookTable(gomock.Any(), userID, requestBody.SeatsCount+1, gomock.Any(), c.Restaurant.SittingDuration).
 					Return(&reservation.Reservation{
 						ID:         1,
 						TableID:    1,
 						SeatsCount: requestBody.SeatsCount + 1,
-						Price:      float64(seatPrice * (requestBody.SeatsCount + 1)),
+						Price:      money.New(seatPrice.Amount*int64(requestBody.SeatsCount+1), "USD"),
 					}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody bookRequest) {
@@ -92,25 +110,119 @@ func TestBookAction(t *testing.T) {
 				require.NoError(t, err)
 
 				require.NotEmpty(t, resp.ID)
-				require.Equal(t, float64(seatPrice*(requestBody.SeatsCount+1)), resp.Price)
+				require.Equal(t, money.New(seatPrice.Amount*int64(requestBody.SeatsCount+1), "USD"), resp.Price)
 				require.Equal(t, requestBody.SeatsCount+1, resp.SeatsCount)
 				require.NotEmpty(t, resp.TableID)
 			},
 		},
+		{
+			name: "booking with a custom duration",
+			requestBody: bookRequest{
+				SeatsCount:      2,
+				Date:            time.Now().AddDate(0, 0, 1).Format(time.RFC3339),
+				DurationMinutes: 90,
+			},
+			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
+				token, err := manager.GenerateToken(userID, user.RoleGuest, c.App.TokenDuration)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody bookRequest) {
+				startAt, err := time.Parse(time.RFC3339, requestBody.Date)
+				require.NoError(t, err)
+				repository.EXPECT().
+					BookTable(gomock.Any(), userID, requestBody.SeatsCount, startAt, 90*time.Minute).
+					Return(&reservation.Reservation{
+						ID:         1,
+						TableID:    1,
+						SeatsCount: requestBody.SeatsCount,
+						Price:      money.New(seatPrice.Amount*int64(requestBody.SeatsCount), "USD"),
+						StartAt:    startAt,
+						EndAt:      startAt.Add(90 * time.Minute),
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody bookRequest) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.BookResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, requestBody.DurationMinutes, resp.DurationMinutes)
+				require.Equal(t, resp.StartAt.Add(90*time.Minute), resp.EndAt)
+			},
+		},
+		{
+			name: "booking a group of tables for a large party",
+			requestBody: bookRequest{
+				SeatsCount: 14,
+				Date:       time.Now().AddDate(0, 0, 1).Format(time.RFC3339),
+			},
+			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
+				token, err := manager.GenerateToken(userID, user.RoleGuest, c.App.TokenDuration)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody bookRequest) {
+				repository.EXPECT().
+					BookTable(gomock.Any(), userID, requestBody.SeatsCount, gomock.Any(), c.Restaurant.SittingDuration).
+					Return(&reservation.Reservation{
+						ID:         1,
+						TableID:    8,
+						SeatsCount: requestBody.SeatsCount,
+						Price:      money.New(seatPrice.Amount*int64(requestBody.SeatsCount), "USD"),
+						Tables: []reservation.ReservationTable{
+							{ReservationID: 1, TableID: 8, SeatsCount: 8},
+							{ReservationID: 1, TableID: 9, SeatsCount: 8},
+						},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody bookRequest) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.BookResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, 8, resp.TableID)
+				require.Equal(t, []int{8, 9}, resp.TableIDs)
+				require.Equal(t, requestBody.SeatsCount, resp.SeatsCount)
+			},
+		},
+		{
+			name: "date without time",
+			requestBody: bookRequest{
+				SeatsCount: 2,
+				Date:       time.Now().AddDate(0, 0, 1).Format("2006-01-02"),
+			},
+			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
+				token, err := manager.GenerateToken(userID, user.RoleGuest, c.App.TokenDuration)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody bookRequest) {
+				repository.EXPECT().
+					BookTable(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody bookRequest) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "no tables are available",
 			requestBody: bookRequest{
 				SeatsCount: 8,
-				Date:       time.Now().AddDate(0, 0, 1).Format("2006-01-02"),
+				Date:       time.Now().AddDate(0, 0, 1).Format(time.RFC3339),
 			},
 			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
-				token, err := manager.GenerateToken(userID, c.App.TokenDuration)
+				token, err := manager.GenerateToken(userID, user.RoleGuest, c.App.TokenDuration)
 				require.NoError(t, err)
 				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
 			},
 			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody bookRequest) {
 				repository.EXPECT().
-					BookTable(gomock.Any(), userID, requestBody.SeatsCount, gomock.Any()).
+					BookTable(gomock.Any(), userID, requestBody.SeatsCount, gomock.Any(), c.Restaurant.SittingDuration).
 					Return(nil, reservation.ErrNoTablesAreAvailable)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody bookRequest) {
@@ -126,9 +238,17 @@ func TestBookAction(t *testing.T) {
 	defer ctrl.Finish()
 
 	repository := mockdb.NewReservationMockRepository(ctrl)
+	// only users who verified their email can book
+	verifiedAt := time.Now()
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	userRepository.EXPECT().FindByID(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, id int) (*user.User, error) {
+			return &user.User{ID: id, Email: "guest@example.com", EmailVerifiedAt: &verifiedAt}, nil
+		})
 	app, err := application.New(c)
 	require.NoError(t, err)
 	app.SetReservationRepository(repository)
+	app.SetUserRepository(userRepository)
 	app.RegisterRoutes()
 
 	for _, tc := range testCases {
@@ -149,6 +269,7 @@ func TestBookAction(t *testing.T) {
 }
 
 type bookRequest struct {
-	SeatsCount int    `json:"seats_count"`
-	Date       string `json:"date"`
+	SeatsCount      int    `json:"seats_count"`
+	Date            string `json:"date"`
+	DurationMinutes int    `json:"duration_minutes,omitempty"`
 }
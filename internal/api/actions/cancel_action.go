@@ -5,16 +5,23 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
 )
 
 // CancelRequest represents the request body for canceling
 type CancelRequest struct {
-	ID int `json:"id" binding:"required"`
+	ID     int    `json:"id" binding:"required"`
+	Reason string `json:"reason" binding:"max=255"`
 }
 
-// CancelAction is a function that handles the cancel action
-func CancelAction(repository reservation.Repository) gin.HandlerFunc {
+// CancelAction is a function that handles the cancel action. Staff cancel the reservations of other users only when
+// they confirmed their login with a second factor that the policy requires of their role, otherwise they cancel their
+// own reservations like guests.
+func CancelAction(repository reservation.Repository, policy twofactor.Policy) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var requestBody CancelRequest
 		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
@@ -22,11 +29,23 @@ func CancelAction(repository reservation.Repository) gin.HandlerFunc {
 			return
 		}
 
-		if err := repository.CancelReservation(ctx, requestBody.ID); err != nil {
+		userID := ctx.MustGet(middlewares.AuthUserIDKey).(int)
+		payload := ctx.MustGet(middlewares.AuthPayloadKey).(*token.Payload)
+		canCancelAny := payload.Role.Can(user.PermissionManageReservations) && (!policy.IsRequired(payload.Role) || payload.TwoFactor)
+
+		if err := repository.CancelReservation(ctx, requestBody.ID, userID, canCancelAny, requestBody.Reason); err != nil {
 			if errors.Is(err, reservation.ErrReservationNotFound) {
 				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 				return
 			}
+			if errors.Is(err, reservation.ErrReservationNotOwned) {
+				ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, reservation.ErrInvalidStatusTransition) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
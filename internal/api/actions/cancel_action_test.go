@@ -12,13 +12,24 @@ import (
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
 func TestCancelAction(t *testing.T) {
-	userID := 1
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	staff := user.User{ID: 2, Role: user.RoleHost}
+	staffToken := func(t *testing.T, manager token.Manager, twoFactor bool) string {
+		payload, err := token.NewPayload(staff.ID, staff.Role, c.App.TokenDuration)
+		require.NoError(t, err)
+		payload.TwoFactor = twoFactor
+		accessToken, err := manager.SignToken(payload)
+		require.NoError(t, err)
+		return accessToken
+	}
 	testCases := []struct {
 		name          string
 		requestBody   cancelRequest
@@ -28,32 +39,97 @@ func TestCancelAction(t *testing.T) {
 	}{
 		{
 			name:        "reservation not found",
-			requestBody: cancelRequest{1},
+			requestBody: cancelRequest{ID: 1},
 			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
-				token, err := manager.GenerateToken(userID, c.App.TokenDuration)
+				token, err := manager.GenerateToken(guest.ID, guest.Role, c.App.TokenDuration)
 				require.NoError(t, err)
 				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
 			},
 			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody cancelRequest) {
 				repository.EXPECT().
-					CancelReservation(gomock.Any(), requestBody.ID).
+					CancelReservation(gomock.Any(), requestBody.ID, guest.ID, false, requestBody.Reason).
 					Return(reservation.ErrReservationNotFound)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
+		{
+			name:        "reservation of another user",
+			requestBody: cancelRequest{ID: 1},
+			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
+				token, err := manager.GenerateToken(guest.ID, guest.Role, c.App.TokenDuration)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody cancelRequest) {
+				repository.EXPECT().
+					CancelReservation(gomock.Any(), requestBody.ID, guest.ID, false, requestBody.Reason).
+					Return(reservation.ErrReservationNotOwned)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "reservation already seated",
+			requestBody: cancelRequest{ID: 1},
+			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
+				token, err := manager.GenerateToken(guest.ID, guest.Role, c.App.TokenDuration)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody cancelRequest) {
+				repository.EXPECT().
+					CancelReservation(gomock.Any(), requestBody.ID, guest.ID, false, requestBody.Reason).
+					Return(reservation.ErrInvalidStatusTransition)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			// the role requires a second factor, so a login with the password alone only cancels own reservations
+			name:        "host without second factor",
+			requestBody: cancelRequest{ID: 1, Reason: "guest called to cancel"},
+			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, staffToken(t, manager, false)))
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody cancelRequest) {
+				repository.EXPECT().
+					CancelReservation(gomock.Any(), requestBody.ID, staff.ID, false, requestBody.Reason).
+					Return(reservation.ErrReservationNotOwned)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "host cancels on behalf of a guest",
+			requestBody: cancelRequest{ID: 1, Reason: "guest called to cancel"},
+			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, staffToken(t, manager, true)))
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody cancelRequest) {
+				repository.EXPECT().
+					CancelReservation(gomock.Any(), requestBody.ID, staff.ID, true, requestBody.Reason).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
 		{
 			name:        "ok",
-			requestBody: cancelRequest{1},
+			requestBody: cancelRequest{ID: 1},
 			setAuthHeader: func(t *testing.T, manager token.Manager, req *http.Request) {
-				token, err := manager.GenerateToken(userID, c.App.TokenDuration)
+				token, err := manager.GenerateToken(guest.ID, guest.Role, c.App.TokenDuration)
 				require.NoError(t, err)
 				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
 			},
 			buildStubs: func(repository *mockdb.ReservationMockRepository, requestBody cancelRequest) {
 				repository.EXPECT().
-					CancelReservation(gomock.Any(), requestBody.ID).
+					CancelReservation(gomock.Any(), requestBody.ID, guest.ID, false, requestBody.Reason).
 					Return(nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -72,6 +148,7 @@ func TestCancelAction(t *testing.T) {
 	app, err := application.New(c)
 	require.NoError(t, err)
 	app.SetReservationRepository(repository)
+	app.Services.TwoFactorPolicy = twofactor.Policy{RequiredRoles: []user.Role{user.RoleHost}}
 	app.RegisterRoutes()
 
 	for _, tc := range testCases {
@@ -91,5 +168,6 @@ func TestCancelAction(t *testing.T) {
 }
 
 type cancelRequest struct {
-	ID int `json:"id"`
+	ID     int    `json:"id"`
+	Reason string `json:"reason,omitempty"`
 }
@@ -0,0 +1,81 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// ChangePasswordRequest represents the request body for changing the password of the user of the request
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// ChangePasswordAction is a function that handles changing the password of the user of the request. Every session
+// of the user is revoked and a new one is started for the request, so only this client stays logged in.
+func ChangePasswordAction(userRepo user.Repository, sessionRepo session.Repository, tokenManager token.Manager, revocations token.RevocationList, guard *lockout.Guard, hasher *password.Hasher, policy password.Policy, tokenDuration, refreshTokenDuration time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody ChangePasswordRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := policy.Validate(requestBody.NewPassword); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		u, err := userRepo.FindByID(ctx, ctx.MustGet(middlewares.AuthUserIDKey).(int))
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !confirmPassword(ctx, guard, hasher, u, requestBody.CurrentPassword) {
+			return
+		}
+
+		hashedPassword, err := hasher.Hash(requestBody.NewPassword)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := userRepo.UpdatePassword(ctx, u.ID, hashedPassword); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := revokeUserSessions(ctx, sessionRepo, revocations, u.ID); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// the new session of the request keeps the second factor its login was confirmed with
+		s := newSession(ctx)
+		s.TwoFactor = ctx.MustGet(middlewares.AuthPayloadKey).(*token.Payload).TwoFactor
+		tokens, err := issueSessionTokens(tokenManager, &s, u, tokenDuration, refreshTokenDuration, func(s *session.Session) error {
+			return sessionRepo.CreateSession(ctx, s)
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, tokens)
+	}
+}
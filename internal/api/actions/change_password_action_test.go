@@ -0,0 +1,150 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChangePasswordAction(t *testing.T) {
+	hashedPassword, err := hashPassword("old password")
+	require.NoError(t, err)
+	u := user.User{ID: 1, Username: "guest1", Password: hashedPassword, Role: user.RoleGuest}
+	passwordless := user.User{ID: 2, Username: "fake-6b86b273ff34", Role: user.RoleGuest}
+	otherSession := session.Session{ID: 2, UserID: u.ID, AccessTokenID: "other-device", AccessTokenExpiresAt: time.Now().Add(time.Minute)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.SetSessionRepository(sessionRepository)
+	app.RegisterRoutes()
+
+	testCases := []struct {
+		name          string
+		user          user.User
+		loggedInAt    time.Time
+		requestBody   string
+		buildStubs    func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "short new password",
+			user:        u,
+			requestBody: `{"current_password": "old password", "new_password": "short"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().UpdatePassword(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "wrong current password",
+			user:        u,
+			requestBody: `{"current_password": "wrong password", "new_password": "new password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				userRepository.EXPECT().UpdatePassword(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			// an account signed up through an identity provider sets its first password after logging in there again
+			name:        "first password",
+			user:        passwordless,
+			loggedInAt:  time.Now(),
+			requestBody: `{"new_password": "new password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), passwordless.ID).Return(&passwordless, nil)
+				userRepository.EXPECT().UpdatePassword(gomock.Any(), passwordless.ID, gomock.Any()).Return(nil)
+				sessionRepository.EXPECT().RevokeUserSessions(gomock.Any(), passwordless.ID).Return(nil, nil)
+				sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			user:        u,
+			requestBody: `{"current_password": "old password", "new_password": "new password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				userRepository.EXPECT().
+					UpdatePassword(gomock.Any(), u.ID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, password string) error {
+						valid, needsRehash := app.Services.PasswordHasher.Verify(password, "new password")
+						require.True(t, valid)
+						require.False(t, needsRehash)
+						return nil
+					})
+				sessionRepository.EXPECT().RevokeUserSessions(gomock.Any(), u.ID).Return([]session.Session{otherSession}, nil)
+				sessionRepository.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, s *session.Session) error {
+						require.Equal(t, u.ID, s.UserID)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response actions.TokensResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.NotEmpty(t, response.AccessToken)
+				require.NotEmpty(t, response.RefreshToken)
+
+				// the other sessions are logged out
+				revoked, err := app.Services.RevocationList.IsRevoked(context.Background(), otherSession.AccessTokenID)
+				require.NoError(t, err)
+				require.True(t, revoked)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository, sessionRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/me/password", bytes.NewBufferString(tc.requestBody))
+			payload, err := token.NewPayload(tc.user.ID, tc.user.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			if !tc.loggedInAt.IsZero() {
+				payload.AuthTime = jwt.NewNumericDate(tc.loggedInAt)
+			}
+			accessToken, err := tokenManager.SignToken(payload)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+)
+
+// ClearLockoutAction is a function that handles forgetting the failed logins of an account or IP address, which
+// lifts its lockout or delay
+func ClearLockoutAction(guard *lockout.Guard) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		kind := lockout.Kind(ctx.Param("kind"))
+		if !kind.IsValid() {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lockout kind, must be account or ip"})
+			return
+		}
+
+		// usernames may contain slashes, so the subject is the rest of the path
+		subject := strings.TrimPrefix(ctx.Param("subject"), "/")
+		if subject == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Lockout subject is required"})
+			return
+		}
+
+		if err := guard.Clear(ctx, kind, subject); err != nil {
+			if errors.Is(err, lockout.ErrAttemptsNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Lockout cleared successfully"})
+	}
+}
@@ -0,0 +1,90 @@
+package actions_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClearLockoutAction(t *testing.T) {
+	app := newLockoutTestApp(t)
+	ctx := context.Background()
+	guard := app.Services.LoginGuard
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, guard.RecordFailure(ctx, "team/alice", "2001:db8::1"))
+	}
+
+	testCases := []struct {
+		name          string
+		url           string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "invalid kind",
+			url:  "/admin/lockouts/user/alice",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "without subject",
+			url:  "/admin/lockouts/account/",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "no failed attempts",
+			url:  "/admin/lockouts/account/bob",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "account",
+			url:  "/admin/lockouts/account/team/alice",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				// the address is still locked out
+				require.Error(t, guard.Check(ctx, "team/alice", "2001:db8::1"))
+				require.NoError(t, guard.Check(ctx, "team/alice", "2001:db8::2"))
+			},
+		},
+		{
+			name: "ip",
+			url:  "/admin/lockouts/ip/2001:db8::1",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NoError(t, guard.Check(ctx, "team/alice", "2001:db8::1"))
+
+				throttled, err := guard.Throttled(ctx)
+				require.NoError(t, err)
+				require.Empty(t, throttled)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+	accessToken, err := tokenManager.GenerateToken(1, user.RoleAdmin, c.App.TokenDuration)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodDelete, tc.url, nil)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package actions
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+const (
+	// recentLoginDuration is how long after logging in users of accounts without a password can confirm changes
+	recentLoginDuration = 5 * time.Minute
+	// ErrorCodeLoginRequired is the code of the error responses asking users of accounts without a password to log in
+	// again before a change
+	ErrorCodeLoginRequired = "login_required"
+)
+
+// confirmPassword checks the password the user of an authenticated request confirms a change with. Wrong passwords
+// are throttled like failed logins, so an access token alone is not enough to guess the password. Accounts signed up
+// through an identity provider have no password, their users confirm changes by having logged in at the provider
+// within recentLoginDuration instead. Unless the change is confirmed the response is written and false is returned.
+func confirmPassword(ctx *gin.Context, guard *lockout.Guard, hasher *password.Hasher, u *user.User, plainPassword string) bool {
+	if u.Password == "" {
+		payload := ctx.MustGet(middlewares.AuthPayloadKey).(*token.Payload)
+		if payload.AuthTime == nil || time.Since(payload.AuthTime.Time) > recentLoginDuration {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"error": "log in again at your identity provider to confirm the change",
+				"code":  ErrorCodeLoginRequired,
+			})
+			return false
+		}
+		return true
+	}
+	if plainPassword == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "password is required"})
+		return false
+	}
+
+	clientIP := ctx.ClientIP()
+	if err := guard.Check(ctx, u.Username, clientIP); err != nil {
+		respondGuardError(ctx, err)
+		return false
+	}
+
+	if valid, _ := hasher.Verify(u.Password, plainPassword); !valid {
+		if err := guard.RecordFailure(ctx, u.Username, clientIP); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return false
+		}
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "password is incorrect"})
+		return false
+	}
+
+	if err := guard.RecordSuccess(ctx, u.Username); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// respondGuardError responds to a request the guard did not let through, telling throttled clients when to retry
+func respondGuardError(ctx *gin.Context, err error) {
+	var throttled *lockout.ThrottledError
+	if errors.As(err, &throttled) {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed login attempts, try again later"})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,66 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/totp"
+)
+
+// ConfirmTwoFactorRequest represents the request body for confirming a pending two-factor authentication
+type ConfirmTwoFactorRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// ConfirmTwoFactorAction is a function that handles enabling the pending two-factor authentication of the user of
+// the request with a first code of the authenticator app, the user is given the backup codes along with it
+func ConfirmTwoFactorAction(twoFactorRepo twofactor.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody ConfirmTwoFactorRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tf, err := twoFactorRepo.FindByUserID(ctx, ctx.MustGet(middlewares.AuthUserIDKey).(int))
+		if err != nil {
+			if errors.Is(err, twofactor.ErrTwoFactorNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if tf.IsEnabled() {
+			ctx.JSON(http.StatusConflict, gin.H{"error": twofactor.ErrAlreadyEnabled.Error()})
+			return
+		}
+
+		step, ok := totp.Validate(tf.Secret, requestBody.Code, time.Now(), totpSkew)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": twofactor.ErrInvalidCode.Error()})
+			return
+		}
+
+		codes, hashes, err := newBackupCodes()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := twoFactorRepo.Enable(ctx, tf, step, hashes); err != nil {
+			if errors.Is(err, twofactor.ErrTwoFactorNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, BackupCodesResponse{BackupCodes: codes})
+	}
+}
@@ -0,0 +1,135 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/totp"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestConfirmTwoFactorAction(t *testing.T) {
+	u := user.User{ID: 1, Username: "manager1", Role: user.RoleManager}
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	pending := twofactor.TwoFactor{UserID: u.ID, Secret: secret}
+	enabledAt := time.Now()
+	enabled := twofactor.TwoFactor{UserID: u.ID, Secret: secret, EnabledAt: &enabledAt}
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	wrongCode, err := totp.Code(secret, totp.Step(time.Now())+10)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		requestBody   actions.ConfirmTwoFactorRequest
+		buildStubs    func(twoFactorRepository *mockdb.TwoFactorMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "not a code",
+			requestBody: actions.ConfirmTwoFactorRequest{Code: "abcdef"},
+			buildStubs: func(twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "not enrolled",
+			requestBody: actions.ConfirmTwoFactorRequest{Code: code},
+			buildStubs: func(twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), u.ID).Return(nil, twofactor.ErrTwoFactorNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "already enabled",
+			requestBody: actions.ConfirmTwoFactorRequest{Code: code},
+			buildStubs: func(twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), u.ID).Return(&enabled, nil)
+				twoFactorRepository.EXPECT().Enable(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:        "wrong code",
+			requestBody: actions.ConfirmTwoFactorRequest{Code: wrongCode},
+			buildStubs: func(twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), u.ID).Return(&pending, nil)
+				twoFactorRepository.EXPECT().Enable(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			requestBody: actions.ConfirmTwoFactorRequest{Code: code},
+			buildStubs: func(twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), u.ID).Return(&pending, nil)
+				twoFactorRepository.EXPECT().
+					Enable(gomock.Any(), &pending, totp.Step(time.Now()), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *twofactor.TwoFactor, _ int64, hashes []string) error {
+						require.Len(t, hashes, 10)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.BackupCodesResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+				require.Len(t, resp.BackupCodes, 10)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	twoFactorRepository := mockdb.NewTwoFactorMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetTwoFactorRepository(twoFactorRepository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(twoFactorRepository)
+
+			jsonData, err := json.Marshal(tc.requestBody)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/me/2fa/confirm", bytes.NewReader(jsonData))
+			token, err := tokenManager.GenerateToken(u.ID, u.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package actions
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+)
+
+// CreateTableRequest represents the request body for creating a table
+type CreateTableRequest struct {
+	SeatsCount int      `json:"seats_count" binding:"required,min=2,max=30"`
+	Name       string   `json:"name" binding:"required,max=100"`
+	Zone       string   `json:"zone" binding:"max=100"`
+	Attributes []string `json:"attributes" binding:"max=20,dive,required,max=50"`
+}
+
+// CreateTableAction is a function that handles adding a table to the restaurant
+func CreateTableAction(repository table.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody CreateTableRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if requestBody.SeatsCount%2 != 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seats count, should be even"})
+			return
+		}
+
+		t := table.Table{
+			SeatsCount: requestBody.SeatsCount,
+			Name:       requestBody.Name,
+			Zone:       requestBody.Zone,
+			Attributes: requestBody.Attributes,
+		}
+		if err := repository.CreateTable(ctx, &t); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, newTableResponse(t))
+	}
+}
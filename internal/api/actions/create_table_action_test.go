@@ -0,0 +1,140 @@
+package actions_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateTableAction(t *testing.T) {
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	staff := user.User{ID: 2, Role: user.RoleManager}
+	testCases := []struct {
+		name          string
+		requestBody   actions.CreateTableRequest
+		authUser      user.User
+		buildStubs    func(repository *mockdb.TableMockRepository, authUser user.User)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "guest is forbidden",
+			requestBody: actions.CreateTableRequest{SeatsCount: 4, Name: "Table 11"},
+			authUser:    guest,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().CreateTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "missing name",
+			requestBody: actions.CreateTableRequest{SeatsCount: 4},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().CreateTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "too many seats",
+			requestBody: actions.CreateTableRequest{SeatsCount: 32, Name: "Table 11"},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().CreateTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "odd seats count",
+			requestBody: actions.CreateTableRequest{SeatsCount: 5, Name: "Table 11"},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().CreateTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "empty attribute",
+			requestBody: actions.CreateTableRequest{SeatsCount: 4, Name: "Table 11", Attributes: []string{""}},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().CreateTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			requestBody: actions.CreateTableRequest{SeatsCount: 4, Name: "Table 11", Zone: "patio", Attributes: []string{"window"}},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().
+					CreateTable(gomock.Any(), &table.Table{SeatsCount: 4, Name: "Table 11", Zone: "patio", Attributes: table.Attributes{"window"}}).
+					DoAndReturn(func(_ interface{}, t *table.Table) error {
+						t.ID = 11
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var resp actions.TableResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, actions.TableResponse{ID: 11, SeatsCount: 4, Name: "Table 11", Zone: "patio", Attributes: []string{"window"}}, resp)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewTableMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetTableRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository, tc.authUser)
+
+			body, err := json.Marshal(tc.requestBody)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/admin/tables", bytes.NewReader(body))
+			token, err := tokenManager.GenerateToken(tc.authUser.ID, tc.authUser.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
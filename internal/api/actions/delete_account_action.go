@@ -0,0 +1,71 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// DeleteAccountRequest represents the request body for deleting the account of the user of the request
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// DeleteAccountAction is a function that handles deleting the account of the user of the request, every session of
+// the user is revoked along with it
+func DeleteAccountAction(userRepo user.Repository, sessionRepo session.Repository, revocations token.RevocationList, guard *lockout.Guard, hasher *password.Hasher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody DeleteAccountRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		u, err := userRepo.FindByID(ctx, ctx.MustGet(middlewares.AuthUserIDKey).(int))
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// an admin deleting their account could leave the restaurant without any admin
+		if u.Role == user.RoleAdmin {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "An admin account can not be deleted, have another admin change its role first"})
+			return
+		}
+
+		if !confirmPassword(ctx, guard, hasher, u, requestBody.Password) {
+			return
+		}
+
+		if err := userRepo.DeleteUser(ctx, u.ID); err != nil {
+			if errors.Is(err, user.ErrUpcomingReservations) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := revokeUserSessions(ctx, sessionRepo, revocations, u.ID); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+	}
+}
@@ -0,0 +1,170 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeleteAccountAction(t *testing.T) {
+	hashedPassword, err := hashPassword("the password")
+	require.NoError(t, err)
+	guest := user.User{ID: 1, Username: "guest1", Password: hashedPassword, Role: user.RoleGuest}
+	admin := user.User{ID: 2, Username: "admin1", Password: hashedPassword, Role: user.RoleAdmin}
+	passwordless := user.User{ID: 3, Username: "fake-6b86b273ff34", Role: user.RoleGuest}
+	s := session.Session{ID: 1, UserID: guest.ID, AccessTokenID: "access-1", AccessTokenExpiresAt: time.Now().Add(time.Minute)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.SetSessionRepository(sessionRepository)
+	app.RegisterRoutes()
+
+	testCases := []struct {
+		name          string
+		user          user.User
+		loggedInAt    time.Time
+		requestBody   string
+		buildStubs    func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "without password",
+			user:        guest,
+			requestBody: `{}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), guest.ID).Return(&guest, nil)
+				userRepository.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			// an account signed up through an identity provider has no password to confirm the deletion with
+			name:        "without password, logged in long ago",
+			user:        passwordless,
+			loggedInAt:  time.Now().Add(-time.Hour),
+			requestBody: `{}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), passwordless.ID).Return(&passwordless, nil)
+				userRepository.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), actions.ErrorCodeLoginRequired)
+			},
+		},
+		{
+			name:        "without password, logged in just now",
+			user:        passwordless,
+			loggedInAt:  time.Now(),
+			requestBody: `{}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), passwordless.ID).Return(&passwordless, nil)
+				userRepository.EXPECT().DeleteUser(gomock.Any(), passwordless.ID).Return(nil)
+				sessionRepository.EXPECT().RevokeUserSessions(gomock.Any(), passwordless.ID).Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "wrong password",
+			user:        guest,
+			requestBody: `{"password": "wrong password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), guest.ID).Return(&guest, nil)
+				userRepository.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "admin",
+			user:        admin,
+			requestBody: `{"password": "the password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), admin.ID).Return(&admin, nil)
+				userRepository.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "upcoming reservations",
+			user:        guest,
+			requestBody: `{"password": "the password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), guest.ID).Return(&guest, nil)
+				userRepository.EXPECT().DeleteUser(gomock.Any(), guest.ID).Return(user.ErrUpcomingReservations)
+				sessionRepository.EXPECT().RevokeUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			user:        guest,
+			requestBody: `{"password": "the password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), guest.ID).Return(&guest, nil)
+				userRepository.EXPECT().DeleteUser(gomock.Any(), guest.ID).Return(nil)
+				sessionRepository.EXPECT().RevokeUserSessions(gomock.Any(), guest.ID).Return([]session.Session{s}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				revoked, err := app.Services.RevocationList.IsRevoked(context.Background(), s.AccessTokenID)
+				require.NoError(t, err)
+				require.True(t, revoked)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository, sessionRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodDelete, "/users/me", bytes.NewBufferString(tc.requestBody))
+			payload, err := token.NewPayload(tc.user.ID, tc.user.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			if !tc.loggedInAt.IsZero() {
+				payload.AuthTime = jwt.NewNumericDate(tc.loggedInAt)
+			}
+			accessToken, err := tokenManager.SignToken(payload)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+)
+
+// DeleteScheduledSettingsAction is a function that handles calling off a version of the restaurant settings
+// that has not taken effect yet
+func DeleteScheduledSettingsAction(repository table.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		settingsID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid settings id"})
+			return
+		}
+
+		if err := repository.DeleteScheduledSettings(ctx, settingsID); err != nil {
+			if errors.Is(err, table.ErrSettingsNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, table.ErrSettingsAlreadyEffective) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Scheduled settings deleted successfully"})
+	}
+}
@@ -0,0 +1,112 @@
+package actions_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeleteScheduledSettingsAction(t *testing.T) {
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	staff := user.User{ID: 2, Role: user.RoleManager}
+	testCases := []struct {
+		name          string
+		url           string
+		authUser      user.User
+		buildStubs    func(repository *mockdb.TableMockRepository, authUser user.User)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "guest is forbidden",
+			url:      "/admin/settings/2",
+			authUser: guest,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().DeleteScheduledSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "invalid settings id",
+			url:      "/admin/settings/abc",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().DeleteScheduledSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "settings not found",
+			url:      "/admin/settings/2",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().DeleteScheduledSettings(gomock.Any(), 2).Return(table.ErrSettingsNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "settings already in effect",
+			url:      "/admin/settings/1",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().DeleteScheduledSettings(gomock.Any(), 1).Return(table.ErrSettingsAlreadyEffective)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "ok",
+			url:      "/admin/settings/2",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().DeleteScheduledSettings(gomock.Any(), 2).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewTableMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetTableRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository, tc.authUser)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodDelete, tc.url, nil)
+			token, err := tokenManager.GenerateToken(tc.authUser.ID, tc.authUser.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
+)
+
+// DisableTwoFactorRequest represents the request body for disabling the two-factor authentication of the user
+type DisableTwoFactorRequest struct {
+	Password string `json:"password"`
+}
+
+// DisableTwoFactorAction is a function that handles disabling the two-factor authentication of the user of the
+// request, or dropping a pending one, unless the policy requires it of the role of the user
+func DisableTwoFactorAction(userRepo user.Repository, twoFactorRepo twofactor.Repository, guard *lockout.Guard, hasher *password.Hasher, policy twofactor.Policy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody DisableTwoFactorRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		u, err := userRepo.FindByID(ctx, ctx.MustGet(middlewares.AuthUserIDKey).(int))
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if policy.IsRequired(u.Role) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Your role requires two-factor authentication"})
+			return
+		}
+
+		if !confirmPassword(ctx, guard, hasher, u, requestBody.Password) {
+			return
+		}
+
+		if err := twoFactorRepo.Disable(ctx, u.ID); err != nil {
+			if errors.Is(err, twofactor.ErrTwoFactorNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled successfully"})
+	}
+}
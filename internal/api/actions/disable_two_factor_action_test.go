@@ -0,0 +1,114 @@
+package actions_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDisableTwoFactorAction(t *testing.T) {
+	hashedPassword, err := hashPassword("the password")
+	require.NoError(t, err)
+	guest := user.User{ID: 1, Username: "guest1", Password: hashedPassword, Role: user.RoleGuest}
+	manager := user.User{ID: 2, Username: "manager1", Password: hashedPassword, Role: user.RoleManager}
+
+	cfg := *c
+	cfg.TwoFactor.RequiredRoles = []string{string(user.RoleManager)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	twoFactorRepository := mockdb.NewTwoFactorMockRepository(ctrl)
+	app, err := application.New(&cfg)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.SetTwoFactorRepository(twoFactorRepository)
+	app.RegisterRoutes()
+
+	testCases := []struct {
+		name          string
+		user          user.User
+		requestBody   string
+		buildStubs    func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "required by the role",
+			user:        manager,
+			requestBody: `{"password": "the password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), manager.ID).Return(&manager, nil)
+				twoFactorRepository.EXPECT().Disable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "wrong password",
+			user:        guest,
+			requestBody: `{"password": "wrong password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), guest.ID).Return(&guest, nil)
+				twoFactorRepository.EXPECT().Disable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "not enrolled",
+			user:        guest,
+			requestBody: `{"password": "the password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), guest.ID).Return(&guest, nil)
+				twoFactorRepository.EXPECT().Disable(gomock.Any(), guest.ID).Return(twofactor.ErrTwoFactorNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			user:        guest,
+			requestBody: `{"password": "the password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), guest.ID).Return(&guest, nil)
+				twoFactorRepository.EXPECT().Disable(gomock.Any(), guest.ID).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository, twoFactorRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodDelete, "/users/me/2fa", bytes.NewBufferString(tc.requestBody))
+			token, err := tokenManager.GenerateToken(tc.user.ID, tc.user.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/notification"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// sendEmailVerification sends the user a token to verify their current email with through the notifier, the token
+// can be used within tokenDuration
+func sendEmailVerification(ctx context.Context, userRepo user.Repository, notifier notification.Notifier, u user.User, tokenDuration time.Duration, verifyURL string) error {
+	verifyToken, verifyTokenHash, err := token.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	verification := user.EmailVerification{
+		UserID:    u.ID,
+		Email:     u.Email,
+		TokenHash: verifyTokenHash,
+		ExpiresAt: time.Now().Add(tokenDuration),
+	}
+	if err := userRepo.CreateEmailVerification(ctx, &verification); err != nil {
+		return err
+	}
+
+	return notifier.Notify(ctx, u, notification.Message{
+		Subject: "Verify your email",
+		Body:    emailVerificationBody(verifyToken, tokenDuration, verifyURL),
+	})
+}
+
+// emailVerificationRetryAfter returns how long the user has to wait before the resend policy allows sending them
+// another verification email, zero when one can be sent now
+func emailVerificationRetryAfter(ctx context.Context, userRepo user.Repository, userID int, policy user.ResendPolicy) (time.Duration, error) {
+	now := time.Now()
+	sent, err := userRepo.ListEmailVerificationsSince(ctx, userID, now.Add(-max(policy.Window, policy.Interval)))
+	if err != nil {
+		return 0, err
+	}
+
+	sentAt := make([]time.Time, len(sent))
+	for i, verification := range sent {
+		sentAt[i] = verification.CreatedAt
+	}
+
+	return policy.RetryAfter(sentAt, now), nil
+}
+
+// respondTooManyEmailVerifications answers that no verification email can be sent before retryAfter passes
+func respondTooManyEmailVerifications(ctx *gin.Context, retryAfter time.Duration) {
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many verification emails, try again later"})
+}
+
+func emailVerificationBody(verifyToken string, tokenDuration time.Duration, verifyURL string) string {
+	if verifyURL == "" {
+		return fmt.Sprintf("Use this token to verify your email within %s: %s", tokenDuration, verifyToken)
+	}
+
+	return fmt.Sprintf("Follow this link to verify your email within %s: %s?token=%s", tokenDuration, verifyURL, url.QueryEscape(verifyToken))
+}
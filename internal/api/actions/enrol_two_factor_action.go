@@ -0,0 +1,73 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/totp"
+)
+
+// EnrolTwoFactorRequest represents the request body for starting the two-factor authentication of the user
+type EnrolTwoFactorRequest struct {
+	Password string `json:"password"`
+}
+
+// TwoFactorEnrolmentResponse is a struct that represents the secret of a pending two-factor authentication, to be
+// added to an authenticator app by hand or by scanning the otpauth URI as a QR code
+type TwoFactorEnrolmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+// EnrolTwoFactorAction is a function that handles starting the two-factor authentication of the user of the
+// request, it is enabled once it is confirmed with a first code. The password is confirmed first, so a stolen access
+// token can not put a second factor of its own on the account.
+func EnrolTwoFactorAction(userRepo user.Repository, twoFactorRepo twofactor.Repository, guard *lockout.Guard, hasher *password.Hasher, issuer string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody EnrolTwoFactorRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		u, err := userRepo.FindByID(ctx, ctx.MustGet(middlewares.AuthUserIDKey).(int))
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !confirmPassword(ctx, guard, hasher, u, requestBody.Password) {
+			return
+		}
+
+		secret, err := totp.GenerateSecret()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := twoFactorRepo.CreateEnrolment(ctx, &twofactor.TwoFactor{UserID: u.ID, Secret: secret}); err != nil {
+			if errors.Is(err, twofactor.ErrAlreadyEnabled) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, TwoFactorEnrolmentResponse{
+			Secret:     secret,
+			OtpauthURI: totp.URI(issuer, u.Username, secret),
+		})
+	}
+}
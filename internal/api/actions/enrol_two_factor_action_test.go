@@ -0,0 +1,121 @@
+package actions_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEnrolTwoFactorAction(t *testing.T) {
+	hashedPassword, err := hashPassword("the password")
+	require.NoError(t, err)
+	u := user.User{ID: 1, Username: "manager1", Password: hashedPassword, Role: user.RoleManager}
+
+	testCases := []struct {
+		name          string
+		requestBody   string
+		buildStubs    func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "without password",
+			requestBody: `{}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				twoFactorRepository.EXPECT().CreateEnrolment(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "wrong password",
+			requestBody: `{"password": "wrong password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				twoFactorRepository.EXPECT().CreateEnrolment(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "already enabled",
+			requestBody: `{"password": "the password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				twoFactorRepository.EXPECT().CreateEnrolment(gomock.Any(), gomock.Any()).Return(twofactor.ErrAlreadyEnabled)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			requestBody: `{"password": "the password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				twoFactorRepository.EXPECT().
+					CreateEnrolment(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, tf *twofactor.TwoFactor) error {
+						require.Equal(t, u.ID, tf.UserID)
+						require.NotEmpty(t, tf.Secret)
+						require.False(t, tf.IsEnabled())
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var resp actions.TwoFactorEnrolmentResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+				require.NotEmpty(t, resp.Secret)
+				require.True(t, strings.HasPrefix(resp.OtpauthURI, "otpauth://totp/"))
+				require.Contains(t, resp.OtpauthURI, "secret="+resp.Secret)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	twoFactorRepository := mockdb.NewTwoFactorMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.SetTwoFactorRepository(twoFactorRepository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository, twoFactorRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/me/2fa", strings.NewReader(tc.requestBody))
+			token, err := tokenManager.GenerateToken(u.ID, u.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/notification"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// ForgotPasswordRequest represents the request body for asking for a password reset
+type ForgotPasswordRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// ForgotPasswordAction is a function that handles asking for a password reset, the reset token is sent to the user
+// through the notifier once they verified their email. The response is the same whether or not the username exists.
+func ForgotPasswordAction(userRepo user.Repository, notifier notification.Notifier, tokenDuration time.Duration, resetURL string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody ForgotPasswordRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		u, err := userRepo.FindByUsername(ctx, requestBody.Username)
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusOK, gin.H{"message": "Password reset instructions were sent if the account exists"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// whoever set an email they do not own would take the account over with the reset link, so an unverified
+		// email answers like an account without an address
+		if !u.IsEmailVerified() {
+			ctx.JSON(http.StatusOK, gin.H{"message": "Password reset instructions were sent if the account exists"})
+			return
+		}
+
+		resetToken, resetTokenHash, err := token.NewOpaqueToken()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		reset := user.PasswordReset{
+			UserID:    u.ID,
+			TokenHash: resetTokenHash,
+			ExpiresAt: time.Now().Add(tokenDuration),
+		}
+		if err := userRepo.CreatePasswordReset(ctx, &reset); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = notifier.Notify(ctx, *u, notification.Message{
+			Subject: "Reset your password",
+			Body:    passwordResetBody(resetToken, tokenDuration, resetURL),
+		})
+		// an account without an address to send the instructions to answers like one that does not exist
+		if err != nil && !errors.Is(err, notification.ErrNoAddress) {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Password reset instructions were sent if the account exists"})
+	}
+}
+
+func passwordResetBody(resetToken string, tokenDuration time.Duration, resetURL string) string {
+	if resetURL == "" {
+		return fmt.Sprintf("Use this token to choose a new password within %s: %s", tokenDuration, resetToken)
+	}
+
+	return fmt.Sprintf("Follow this link to choose a new password within %s: %s?token=%s", tokenDuration, resetURL, url.QueryEscape(resetToken))
+}
@@ -0,0 +1,166 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/notification"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// recordingNotifier keeps the messages for the tests instead of delivering them
+type recordingNotifier struct {
+	messages map[int][]notification.Message
+	err      error
+}
+
+func newRecordingNotifier() *recordingNotifier {
+	return &recordingNotifier{messages: make(map[int][]notification.Message)}
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, u user.User, message notification.Message) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.messages[u.ID] = append(n.messages[u.ID], message)
+	return nil
+}
+
+func TestForgotPasswordAction(t *testing.T) {
+	verifiedAt := time.Now()
+	u := user.User{ID: 1, Username: "guest1", Email: "guest1@example.com", EmailVerifiedAt: &verifiedAt}
+	unverified := user.User{ID: 2, Username: "guest2", Email: "guest2@example.com"}
+	notifier := newRecordingNotifier()
+	var createdReset *user.PasswordReset
+
+	testCases := []struct {
+		name          string
+		requestBody   string
+		buildStubs    func(userRepository *mockdb.UserMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "without username",
+			requestBody: `{}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().FindByUsername(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "unknown username",
+			requestBody: `{"username": "nobody"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().FindByUsername(gomock.Any(), "nobody").Return(nil, user.ErrUserNotFound)
+				userRepository.EXPECT().CreatePasswordReset(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				// the same response as for an existing username
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, notifier.messages)
+			},
+		},
+		{
+			name:        "unverified email",
+			requestBody: `{"username": "guest2"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().FindByUsername(gomock.Any(), unverified.Username).Return(&unverified, nil)
+				userRepository.EXPECT().CreatePasswordReset(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				// the same response as for an account without an address
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, notifier.messages)
+			},
+		},
+		{
+			name:        "notifier fails",
+			requestBody: `{"username": "guest1"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				notifier.err = errors.New("mail server is down")
+				userRepository.EXPECT().FindByUsername(gomock.Any(), u.Username).Return(&u, nil)
+				userRepository.EXPECT().CreatePasswordReset(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				notifier.err = nil
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:        "no address to notify",
+			requestBody: `{"username": "guest1"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				notifier.err = notification.ErrNoAddress
+				userRepository.EXPECT().FindByUsername(gomock.Any(), u.Username).Return(&u, nil)
+				userRepository.EXPECT().CreatePasswordReset(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				notifier.err = nil
+				// the same response as for an existing username
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			requestBody: `{"username": "guest1"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().FindByUsername(gomock.Any(), u.Username).Return(&u, nil)
+				userRepository.EXPECT().
+					CreatePasswordReset(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, reset *user.PasswordReset) error {
+						require.Equal(t, u.ID, reset.UserID)
+						require.WithinDuration(t, time.Now().Add(c.PasswordReset.TokenDuration), reset.ExpiresAt, time.Second)
+						createdReset = reset
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Len(t, notifier.messages[u.ID], 1)
+
+				// only the hash of the token is stored, the token itself is sent in the link
+				body := notifier.messages[u.ID][0].Body
+				link, err := url.Parse(strings.Fields(body[strings.Index(body, c.PasswordReset.URL):])[0])
+				require.NoError(t, err)
+				require.NotEmpty(t, link.Query().Get("token"))
+				require.Equal(t, createdReset.TokenHash, token.HashOpaqueToken(link.Query().Get("token")))
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.Services.Notifier = notifier
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/password/forgot", bytes.NewBufferString(tc.requestBody))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
+
+// ProfileResponse is a struct that represents the profile of the user of the request
+type ProfileResponse struct {
+	UserResponse
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// EmailVerified is whether the user verified the email, reservations can only be booked once they have
+	EmailVerified bool   `json:"email_verified"`
+	Phone         string `json:"phone"`
+	Language      string `json:"language"`
+}
+
+func newProfileResponse(u user.User) ProfileResponse {
+	return ProfileResponse{
+		UserResponse:  newUserResponse(u),
+		Name:          u.Name,
+		Email:         u.Email,
+		EmailVerified: u.IsEmailVerified(),
+		Phone:         u.Phone,
+		Language:      u.Language,
+	}
+}
+
+// GetProfileAction is a function that handles reading the profile of the user of the request
+func GetProfileAction(userRepo user.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		u, err := userRepo.FindByID(ctx, ctx.MustGet(middlewares.AuthUserIDKey).(int))
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, newProfileResponse(*u))
+	}
+}
@@ -0,0 +1,90 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetProfileAction(t *testing.T) {
+	u := user.User{
+		ID:       1,
+		Username: "guest1",
+		Role:     user.RoleGuest,
+		Name:     "Guest One",
+		Email:    "guest1@example.com",
+		Phone:    "+4915112345678",
+		Language: "de",
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(repository *mockdb.UserMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "deleted user",
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), u.ID).Return(nil, user.ErrUserNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ok",
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response actions.ProfileResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, u.Username, response.Username)
+				require.Equal(t, u.Name, response.Name)
+				require.Equal(t, u.Email, response.Email)
+				require.Equal(t, u.Phone, response.Phone)
+				require.Equal(t, u.Language, response.Language)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewUserMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/users/me", nil)
+			token, err := tokenManager.GenerateToken(u.ID, u.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
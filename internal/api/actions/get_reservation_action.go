@@ -0,0 +1,41 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+// GetReservationAction is a function that handles fetching a reservation of the authenticated user
+func GetReservationAction(repository reservation.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reservationID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation id"})
+			return
+		}
+
+		resv, err := repository.FindByID(ctx, reservationID)
+		if err != nil {
+			if errors.Is(err, reservation.ErrReservationNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// the reservation of another user is not revealed
+		userID := ctx.MustGet(middlewares.AuthUserIDKey).(int)
+		if int(resv.UserID) != userID {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": reservation.ErrReservationNotFound.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, newReservationResponse(*resv))
+	}
+}
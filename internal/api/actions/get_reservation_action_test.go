@@ -0,0 +1,112 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetReservationAction(t *testing.T) {
+	userID := 1
+	testCases := []struct {
+		name          string
+		url           string
+		buildStubs    func(repository *mockdb.ReservationMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "invalid reservation id",
+			url:  "/reservations/abc",
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "reservation not found",
+			url:  "/reservations/1",
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), 1).Return(nil, reservation.ErrReservationNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "reservation of another user",
+			url:  "/reservations/1",
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), 1).Return(&reservation.Reservation{ID: 1, UserID: uint(userID + 1)}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), reservation.ErrReservationNotFound.Error())
+			},
+		},
+		{
+			name: "ok",
+			url:  "/reservations/1",
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), 1).Return(&reservation.Reservation{
+					ID:         1,
+					UserID:     uint(userID),
+					TableID:    3,
+					SeatsCount: 4,
+					Status:     reservation.StatusConfirmed,
+				}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.ReservationResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, 1, resp.ID)
+				require.Equal(t, 3, resp.TableID)
+				require.Equal(t, reservation.StatusConfirmed, resp.Status)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewReservationMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetReservationRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			token, err := tokenManager.GenerateToken(userID, user.RoleGuest, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
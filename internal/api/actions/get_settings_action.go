@@ -0,0 +1,46 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+)
+
+// GetSettingsResponse represents the response body for fetching the restaurant settings
+type GetSettingsResponse struct {
+	// Current is the version in effect now, nil when none has taken effect yet
+	Current  *SettingsResponse  `json:"current"`
+	Versions []SettingsResponse `json:"versions"`
+}
+
+// GetSettingsAction is a function that handles fetching the settings in effect and every version of them,
+// the past and the scheduled ones included
+func GetSettingsAction(repository table.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		history, err := repository.ListSettings(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		res := GetSettingsResponse{Versions: make([]SettingsResponse, 0, len(history))}
+		for _, settings := range history {
+			res.Versions = append(res.Versions, newSettingsResponse(settings))
+		}
+
+		current, err := history.At(time.Now())
+		if err != nil && !errors.Is(err, table.ErrSettingsNotFound) {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if err == nil {
+			currentResponse := newSettingsResponse(current)
+			res.Current = &currentResponse
+		}
+
+		ctx.JSON(http.StatusOK, res)
+	}
+}
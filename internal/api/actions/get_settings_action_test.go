@@ -0,0 +1,123 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetSettingsAction(t *testing.T) {
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	staff := user.User{ID: 2, Role: user.RoleManager}
+	history := table.SettingsHistory{
+		{ID: 1, SeatPrice: money.New(1000, "USD"), EffectiveFrom: time.Unix(0, 0).UTC()},
+		{ID: 2, SeatPrice: money.New(1500, "USD"), EffectiveFrom: time.Now().AddDate(0, 1, 0).UTC()},
+	}
+	testCases := []struct {
+		name          string
+		authUser      user.User
+		buildStubs    func(repository *mockdb.TableMockRepository, authUser user.User)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "guest is forbidden",
+			authUser: guest,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ListSettings(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "internal error",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ListSettings(gomock.Any()).Return(nil, errors.New("connection refused"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:     "no settings",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ListSettings(gomock.Any()).Return(table.SettingsHistory{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.GetSettingsResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Nil(t, resp.Current)
+				require.Empty(t, resp.Versions)
+			},
+		},
+		{
+			name:     "current and scheduled settings",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ListSettings(gomock.Any()).Return(history, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.GetSettingsResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.NotNil(t, resp.Current)
+				require.Equal(t, 1, resp.Current.ID)
+				require.Equal(t, money.New(1000, "USD"), resp.Current.SeatPrice)
+				require.Len(t, resp.Versions, 2)
+				require.Equal(t, 2, resp.Versions[1].ID)
+				require.True(t, history[1].EffectiveFrom.Equal(resp.Versions[1].EffectiveFrom))
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewTableMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetTableRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository, tc.authUser)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/admin/settings", nil)
+			token, err := tokenManager.GenerateToken(tc.authUser.ID, tc.authUser.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package actions
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// jwksCacheControl lets other services cache the keys for 5 minutes, a new key has to be published at least that
+// long before it becomes the active one
+const jwksCacheControl = "public, max-age=300"
+
+// JWKSAction is a function that handles publishing the public keys access tokens are verified with
+func JWKSAction(publisher token.KeyPublisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Cache-Control", jwksCacheControl)
+		ctx.JSON(http.StatusOK, publisher.PublicKeys())
+	}
+}
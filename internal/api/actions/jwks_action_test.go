@@ -0,0 +1,55 @@
+package actions_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSAction(t *testing.T) {
+	t.Run("not published with a shared secret", func(t *testing.T) {
+		app, err := application.New(c)
+		require.NoError(t, err)
+		app.RegisterRoutes()
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		app.Router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		key, err := token.NewKey("2024-10", privateKey)
+		require.NoError(t, err)
+		keyManager, err := token.NewKeyManager([]token.Key{key}, key.ID)
+		require.NoError(t, err)
+
+		app, err := application.New(c)
+		require.NoError(t, err)
+		app.Services.TokenManger = keyManager
+		app.RegisterRoutes()
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		app.Router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Contains(t, recorder.Header().Get("Cache-Control"), "max-age")
+
+		var resp token.JWKS
+		err = json.NewDecoder(recorder.Body).Decode(&resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Keys, 1)
+		require.Equal(t, "2024-10", resp.Keys[0].KeyID)
+		require.Equal(t, "OKP", resp.Keys[0].KeyType)
+		require.Equal(t, "sig", resp.Keys[0].Use)
+	})
+}
@@ -0,0 +1,36 @@
+package actions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc"
+)
+
+// LinkIdentityResponse is a struct that represents where the user signs in at the provider whose identity is linked
+type LinkIdentityResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// LinkIdentityAction is a function that handles starting to link an identity of an OpenID Connect provider to the
+// account of the user of the request, the identity is linked once the provider sends the user back to the callback
+func LinkIdentityAction(identityRepo identity.Repository, providers map[string]*oidc.Provider, stateDuration time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		provider, ok := findOIDCProvider(ctx, providers)
+		if !ok {
+			return
+		}
+
+		userID := ctx.MustGet(middlewares.AuthUserIDKey).(int)
+		authURL, err := startOIDCSignIn(ctx, identityRepo, provider, stateDuration, &userID)
+		if err != nil {
+			respondOIDCError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, LinkIdentityResponse{AuthorizationURL: authURL})
+	}
+}
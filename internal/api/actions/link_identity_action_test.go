@@ -0,0 +1,150 @@
+package actions_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc/oidctest"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLinkIdentityAction(t *testing.T) {
+	u := user.User{ID: 1, Role: user.RoleGuest}
+	jane := oidctest.User{Subject: "248289761001", Email: "jane@example.com", EmailVerified: true}
+
+	testCases := []struct {
+		name          string
+		provider      string
+		buildStubs    func(identityRepository *mockdb.IdentityMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "unknown provider",
+			provider: "unknown",
+			buildStubs: func(identityRepository *mockdb.IdentityMockRepository) {
+				identityRepository.EXPECT().CreateLoginState(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "ok",
+			provider: "fake",
+			buildStubs: func(identityRepository *mockdb.IdentityMockRepository) {
+				identityRepository.EXPECT().
+					CreateLoginState(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, state *identity.LoginState) error {
+						require.True(t, state.IsLinking())
+						require.Equal(t, u.ID, *state.UserID)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "authorization_url")
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+	fake, providers := newOIDCProviders(t)
+	fake.SignIn(jane)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	sessionRepository.EXPECT().TouchSession(gomock.Any(), 1, u.ID, gomock.Any()).Return(nil).AnyTimes()
+	identityRepository := mockdb.NewIdentityMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetSessionRepository(sessionRepository)
+	app.SetIdentityRepository(identityRepository)
+	app.Services.OIDCProviders = providers
+	app.RegisterRoutes()
+	authorization := fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, sessionAccessToken(t, tokenManager, u, 1))
+
+	t.Run("unauthorized", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/users/me/identities/fake", nil)
+
+		app.Router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(identityRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/me/identities/"+tc.provider, nil)
+			request.Header.Set("Authorization", authorization)
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+
+	// the identity is linked to the account of the user who started linking it once the provider sends them back, and
+	// only when they finish it themselves
+	other := user.User{ID: 2, Role: user.RoleGuest}
+	sessionRepository.EXPECT().TouchSession(gomock.Any(), 2, other.ID, gomock.Any()).Return(nil).AnyTimes()
+
+	callbackTestCases := []struct {
+		name          string
+		authorization string
+		linkError     error
+		statusCode    int
+	}{
+		{name: "callback without the access token", statusCode: http.StatusUnauthorized},
+		{
+			name:          "callback with the access token of another user",
+			authorization: fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, sessionAccessToken(t, tokenManager, other, 2)),
+			statusCode:    http.StatusForbidden,
+		},
+		{name: "linked", authorization: authorization, statusCode: http.StatusOK},
+		{name: "identity linked to another account", authorization: authorization, linkError: identity.ErrIdentityAlreadyLinked, statusCode: http.StatusConflict},
+		{name: "identity of the provider linked already", authorization: authorization, linkError: identity.ErrProviderAlreadyLinked, statusCode: http.StatusConflict},
+	}
+
+	stubLoginStates(identityRepository)
+	for _, tc := range callbackTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.authorization == authorization {
+				identityRepository.EXPECT().
+					LinkIdentity(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, i *identity.Identity) error {
+						require.Equal(t, identity.Identity{UserID: u.ID, Provider: "fake", Subject: jane.Subject, Email: jane.Email}, *i)
+						return tc.linkError
+					})
+			} else {
+				identityRepository.EXPECT().LinkIdentity(gomock.Any(), gomock.Any()).Times(0)
+			}
+			identityRepository.EXPECT().FindIdentity(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			query, stateCookie := signInAtFakeProvider(t, app, fake, "/users/me/identities/fake", authorization)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/users/oidc/fake/callback?"+query.Encode(), nil)
+			request.AddCookie(stateCookie)
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+
+			app.Router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.statusCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package actions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+)
+
+// IdentityResponse is a struct that represents an identity of a provider linked to the account of the user
+type IdentityResponse struct {
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListIdentitiesAction is a function that handles listing the identities linked to the account of the user of the
+// request
+func ListIdentitiesAction(identityRepo identity.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		identities, err := identityRepo.ListUserIdentities(ctx, ctx.MustGet(middlewares.AuthUserIDKey).(int))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]IdentityResponse, len(identities))
+		for i, linked := range identities {
+			response[i] = IdentityResponse{
+				Provider:  linked.Provider,
+				Email:     linked.Email,
+				CreatedAt: linked.CreatedAt,
+			}
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,57 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListIdentitiesAction(t *testing.T) {
+	u := user.User{ID: 1, Role: user.RoleGuest}
+	linked := identity.Identity{ID: 1, UserID: u.ID, Provider: "fake", Subject: "248289761001", Email: "jane@example.com", CreatedAt: time.Now()}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	sessionRepository.EXPECT().TouchSession(gomock.Any(), 1, u.ID, gomock.Any()).Return(nil)
+	identityRepository := mockdb.NewIdentityMockRepository(ctrl)
+	identityRepository.EXPECT().ListUserIdentities(gomock.Any(), u.ID).Return([]identity.Identity{linked}, nil)
+
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetSessionRepository(sessionRepository)
+	app.SetIdentityRepository(identityRepository)
+	app.RegisterRoutes()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/users/me/identities", nil)
+	request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, sessionAccessToken(t, tokenManager, u, 1)))
+
+	app.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var resp []actions.IdentityResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+	require.Len(t, resp, 1)
+	require.Equal(t, "fake", resp[0].Provider)
+	require.Equal(t, "jane@example.com", resp[0].Email)
+	// the subject is not told
+	require.NotContains(t, recorder.Body.String(), linked.Subject)
+}
@@ -0,0 +1,48 @@
+package actions
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+)
+
+// LockoutResponse is a struct that represents an account or IP address whose logins are throttled
+type LockoutResponse struct {
+	Kind          lockout.Kind `json:"kind"`
+	Subject       string       `json:"subject"`
+	Failures      int          `json:"failures"`
+	LastFailureAt time.Time    `json:"last_failure_at"`
+	// LockedUntil is set once the subject is locked out rather than delayed
+	LockedUntil       *time.Time `json:"locked_until"`
+	RetryAfterSeconds int        `json:"retry_after_seconds"`
+}
+
+// ListLockoutsAction is a function that handles listing the accounts and IP addresses that currently have to wait
+// before their next login attempt
+func ListLockoutsAction(guard *lockout.Guard) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		throttled, err := guard.Throttled(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		now := time.Now()
+		resp := make([]LockoutResponse, 0, len(throttled))
+		for _, a := range throttled {
+			resp = append(resp, LockoutResponse{
+				Kind:              a.Kind,
+				Subject:           a.Subject,
+				Failures:          a.Failures,
+				LastFailureAt:     a.LastFailureAt,
+				LockedUntil:       a.LockedUntil,
+				RetryAfterSeconds: int(math.Ceil(guard.RetryAfter(a, now).Seconds())),
+			})
+		}
+
+		ctx.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,69 @@
+package actions_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+)
+
+// newLockoutTestApp creates an application locking out accounts after 2 failed logins and IP addresses after 3
+func newLockoutTestApp(t *testing.T) *application.Application {
+	cfg := *c
+	cfg.LoginThrottling.Account = config.LoginThrottlingPolicy{MaxFailures: 2, LockoutDuration: time.Hour, ResetAfter: time.Hour}
+	cfg.LoginThrottling.IP = config.LoginThrottlingPolicy{MaxFailures: 3, LockoutDuration: time.Hour, ResetAfter: time.Hour}
+
+	app, err := application.New(&cfg)
+	require.NoError(t, err)
+	app.RegisterRoutes()
+	return app
+}
+
+func TestListLockoutsAction(t *testing.T) {
+	app := newLockoutTestApp(t)
+	ctx := context.Background()
+	guard := app.Services.LoginGuard
+
+	// alice is locked out, bob only failed once and the address failed three times
+	for _, username := range []string{"alice", "alice", "bob"} {
+		require.NoError(t, guard.RecordFailure(ctx, username, "192.0.2.1"))
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+	accessToken, err := tokenManager.GenerateToken(1, user.RoleAdmin, c.App.TokenDuration)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/admin/lockouts", nil)
+	request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken))
+	app.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var resp []actions.LockoutResponse
+	err = json.NewDecoder(recorder.Body).Decode(&resp)
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+
+	require.Equal(t, lockout.KindAccount, resp[0].Kind)
+	require.Equal(t, "alice", resp[0].Subject)
+	require.Equal(t, 2, resp[0].Failures)
+	require.NotNil(t, resp[0].LockedUntil)
+	require.InDelta(t, 3600, resp[0].RetryAfterSeconds, 1)
+
+	require.Equal(t, lockout.KindIP, resp[1].Kind)
+	require.Equal(t, "192.0.2.1", resp[1].Subject)
+	require.Equal(t, 3, resp[1].Failures)
+}
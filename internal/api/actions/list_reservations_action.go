@@ -0,0 +1,95 @@
+package actions
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+const defaultPerPage = 20
+
+// ListReservationsRequest represents the query string for listing reservations
+type ListReservationsRequest struct {
+	Page    int      `form:"page" binding:"omitempty,min=1"`
+	PerPage int      `form:"per_page" binding:"omitempty,min=1,max=100"`
+	From    string   `form:"from"`
+	To      string   `form:"to"`
+	Status  []string `form:"status" binding:"dive,oneof=pending confirmed seated completed cancelled no_show"`
+	Sort    string   `form:"sort" binding:"omitempty,oneof=start_at -start_at price -price seats_count -seats_count"`
+}
+
+// ListReservationsResponse represents the response body for listing reservations
+type ListReservationsResponse struct {
+	Data    []ReservationResponse `json:"data"`
+	Page    int                   `json:"page"`
+	PerPage int                   `json:"per_page"`
+	Total   int                   `json:"total"`
+}
+
+// ListReservationsAction is a function that handles listing the reservations of the authenticated user
+func ListReservationsAction(repository reservation.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request ListReservationsRequest
+		if err := ctx.ShouldBindQuery(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		filter := reservation.ListFilter{
+			Page:    request.Page,
+			PerPage: request.PerPage,
+		}
+		if filter.Page == 0 {
+			filter.Page = 1
+		}
+		if filter.PerPage == 0 {
+			filter.PerPage = defaultPerPage
+		}
+
+		var err error
+		if request.From != "" {
+			if filter.From, err = time.Parse(time.RFC3339, request.From); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from format, expected RFC 3339"})
+				return
+			}
+		}
+		if request.To != "" {
+			if filter.To, err = time.Parse(time.RFC3339, request.To); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to format, expected RFC 3339"})
+				return
+			}
+		}
+
+		for _, status := range request.Status {
+			filter.Statuses = append(filter.Statuses, reservation.Status(status))
+		}
+
+		if request.Sort != "" {
+			filter.SortDesc = strings.HasPrefix(request.Sort, "-")
+			filter.SortBy = reservation.SortField(strings.TrimPrefix(request.Sort, "-"))
+		}
+
+		userID := ctx.MustGet(middlewares.AuthUserIDKey).(int)
+
+		reservations, total, err := repository.ListByUser(ctx, userID, filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		res := ListReservationsResponse{
+			Data:    make([]ReservationResponse, 0, len(reservations)),
+			Page:    filter.Page,
+			PerPage: filter.PerPage,
+			Total:   total,
+		}
+		for _, resv := range reservations {
+			res.Data = append(res.Data, newReservationResponse(resv))
+		}
+		ctx.JSON(http.StatusOK, res)
+	}
+}
@@ -0,0 +1,148 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListReservationsAction(t *testing.T) {
+	userID := 1
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	testCases := []struct {
+		name          string
+		query         url.Values
+		buildStubs    func(repository *mockdb.ReservationMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "invalid status",
+			query: url.Values{"status": {"unknown"}},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().ListByUser(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "invalid from",
+			query: url.Values{"from": {"2025-01-01"}},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().ListByUser(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "default pagination",
+			query: url.Values{},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().
+					ListByUser(gomock.Any(), userID, reservation.ListFilter{Page: 1, PerPage: 20}).
+					Return([]reservation.Reservation{}, 0, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.ListReservationsResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Empty(t, resp.Data)
+				require.Equal(t, 1, resp.Page)
+				require.Equal(t, 20, resp.PerPage)
+			},
+		},
+		{
+			name: "ok",
+			query: url.Values{
+				"page":     {"2"},
+				"per_page": {"1"},
+				"from":     {from.Format(time.RFC3339)},
+				"to":       {to.Format(time.RFC3339)},
+				"status":   {"confirmed", "cancelled"},
+				"sort":     {"-start_at"},
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().
+					ListByUser(gomock.Any(), userID, reservation.ListFilter{
+						From:     from,
+						To:       to,
+						Statuses: []reservation.Status{reservation.StatusConfirmed, reservation.StatusCancelled},
+						SortBy:   reservation.SortByStartAt,
+						SortDesc: true,
+						Page:     2,
+						PerPage:  1,
+					}).
+					Return([]reservation.Reservation{
+						{
+							ID:         2,
+							UserID:     uint(userID),
+							TableID:    1,
+							SeatsCount: 4,
+							StartAt:    from.Add(19 * time.Hour),
+							EndAt:      from.Add(21 * time.Hour),
+							Status:     reservation.StatusConfirmed,
+						},
+					}, 2, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.ListReservationsResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Len(t, resp.Data, 1)
+				require.Equal(t, 2, resp.Data[0].ID)
+				require.Equal(t, 120, resp.Data[0].DurationMinutes)
+				require.Equal(t, 2, resp.Total)
+				require.Equal(t, 2, resp.Page)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewReservationMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetReservationRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/reservations?"+tc.query.Encode(), nil)
+			token, err := tokenManager.GenerateToken(userID, user.RoleGuest, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
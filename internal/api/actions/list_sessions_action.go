@@ -0,0 +1,56 @@
+package actions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// SessionResponse is a struct that represents a session of the user, current tells the session of the request
+type SessionResponse struct {
+	ID         int       `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	TwoFactor  bool      `json:"two_factor"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func newSessionResponse(s session.Session, currentSessionID int) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		TwoFactor:  s.TwoFactor,
+		Current:    s.ID == currentSessionID,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+	}
+}
+
+// ListSessionsAction is a function that handles listing the active sessions of the user of the request, the devices
+// the user is logged in on
+func ListSessionsAction(sessionRepo session.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(middlewares.AuthPayloadKey).(*token.Payload)
+
+		sessions, err := sessionRepo.ListActiveUserSessions(ctx, payload.UserID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]SessionResponse, 0, len(sessions))
+		for _, s := range sessions {
+			response = append(response, newSessionResponse(s, payload.SessionID))
+		}
+		ctx.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,96 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListSessionsAction(t *testing.T) {
+	u := user.User{ID: 1, Role: user.RoleGuest}
+	current := session.Session{ID: 1, UserID: u.ID, UserAgent: "firefox", IPAddress: "192.0.2.1", LastSeenAt: time.Now()}
+	other := session.Session{ID: 2, UserID: u.ID, UserAgent: "curl", IPAddress: "192.0.2.2", LastSeenAt: time.Now().Add(-time.Hour)}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(repository *mockdb.SessionMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "revoked session",
+			buildStubs: func(repository *mockdb.SessionMockRepository) {
+				repository.EXPECT().TouchSession(gomock.Any(), current.ID, u.ID, gomock.Any()).Return(session.ErrSessionNotFound)
+				repository.EXPECT().ListActiveUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ok",
+			buildStubs: func(repository *mockdb.SessionMockRepository) {
+				repository.EXPECT().TouchSession(gomock.Any(), current.ID, u.ID, gomock.Any()).Return(nil)
+				repository.EXPECT().ListActiveUserSessions(gomock.Any(), u.ID).Return([]session.Session{current, other}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp []actions.SessionResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+				require.Len(t, resp, 2)
+				require.True(t, resp[0].Current)
+				require.Equal(t, "firefox", resp[0].UserAgent)
+				require.False(t, resp[1].Current)
+				require.Equal(t, "192.0.2.2", resp[1].IPAddress)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewSessionMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetSessionRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/users/me/sessions", nil)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, sessionAccessToken(t, tokenManager, u, current.ID)))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+// sessionAccessToken signs an access token of the user issued for the session
+func sessionAccessToken(t *testing.T, tokenManager token.Manager, u user.User, sessionID int) string {
+	payload, err := token.NewPayload(u.ID, u.Role, c.App.TokenDuration)
+	require.NoError(t, err)
+	payload.SessionID = sessionID
+	accessToken, err := tokenManager.SignToken(payload)
+	require.NoError(t, err)
+	return accessToken
+}
@@ -0,0 +1,41 @@
+package actions
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+)
+
+// ListTablesRequest represents the query string for listing tables
+type ListTablesRequest struct {
+	IncludeRetired bool `form:"include_retired"`
+}
+
+// ListTablesResponse represents the response body for listing tables
+type ListTablesResponse struct {
+	Tables []TableResponse `json:"tables"`
+}
+
+// ListTablesAction is a function that handles listing the tables of the restaurant
+func ListTablesAction(repository table.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request ListTablesRequest
+		if err := ctx.ShouldBindQuery(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tables, err := repository.ListTables(ctx, request.IncludeRetired)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		res := ListTablesResponse{Tables: make([]TableResponse, 0, len(tables))}
+		for _, t := range tables {
+			res.Tables = append(res.Tables, newTableResponse(t))
+		}
+		ctx.JSON(http.StatusOK, res)
+	}
+}
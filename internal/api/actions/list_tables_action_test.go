@@ -0,0 +1,130 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListTablesAction(t *testing.T) {
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	staff := user.User{ID: 2, Role: user.RoleManager}
+	retiredAt := time.Now().Add(-time.Hour).UTC()
+	testCases := []struct {
+		name          string
+		url           string
+		authUser      user.User
+		buildStubs    func(repository *mockdb.TableMockRepository, authUser user.User)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "guest is forbidden",
+			url:      "/admin/tables",
+			authUser: guest,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ListTables(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "internal error",
+			url:      "/admin/tables",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ListTables(gomock.Any(), false).Return(nil, errors.New("connection refused"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:     "tables in service",
+			url:      "/admin/tables",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().
+					ListTables(gomock.Any(), false).
+					Return([]table.Table{{ID: 1, SeatsCount: 4, Name: "Table 1"}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.ListTablesResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, []actions.TableResponse{{ID: 1, SeatsCount: 4, Name: "Table 1", Attributes: []string{}}}, resp.Tables)
+			},
+		},
+		{
+			name:     "including retired tables",
+			url:      "/admin/tables?include_retired=true",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().
+					ListTables(gomock.Any(), true).
+					Return([]table.Table{
+						{ID: 1, SeatsCount: 4, Name: "Table 1", Zone: "patio", Attributes: table.Attributes{"window"}},
+						{ID: 2, SeatsCount: 6, Name: "Table 2", RetiredAt: &retiredAt},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.ListTablesResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Len(t, resp.Tables, 2)
+				require.Equal(t, "patio", resp.Tables[0].Zone)
+				require.Equal(t, []string{"window"}, resp.Tables[0].Attributes)
+				require.Nil(t, resp.Tables[0].RetiredAt)
+				require.NotNil(t, resp.Tables[1].RetiredAt)
+				require.True(t, retiredAt.Equal(*resp.Tables[1].RetiredAt))
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewTableMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetTableRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository, tc.authUser)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			token, err := tokenManager.GenerateToken(tc.authUser.ID, tc.authUser.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
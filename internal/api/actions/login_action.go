@@ -1,30 +1,47 @@
 package actions
 
 import (
+	"context"
 	"errors"
+	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
-	"github.com/mohammad19khodaei/restaurant_reservation/internal/utils"
 )
 
 // LoginRequest is a struct that represents the login request
 type LoginRequest struct {
 	Username string `json:"username" binding:"required,min=5"`
-	Password string `json:"password" binding:"required,min=8"`
+	Password string `json:"password" binding:"required"`
 }
 
 // UserResponse is a struct that represents the user response
 type LoginResponse struct {
-	AccessToken string `json:"access_token"`
-	User        UserResponse
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	User         UserResponse
 }
 
-// LoginAction is a function that handles the login action
-func LoginAction(userRepo user.Repository, tokenManager token.Manager, tokenDuration time.Duration) gin.HandlerFunc {
+// LoginAction is a function that handles the login action, it starts a session kept alive by its refresh token.
+// Failed logins are throttled by the guard for the username and the client IP alike. Users with two-factor
+// authentication are given a challenge instead, to be passed with a code before the session starts. A correct
+// password hashed with an outdated algorithm or parameters is hashed again.
+func LoginAction(userRepo user.Repository, sessionRepo session.Repository, twoFactorRepo twofactor.Repository, tokenManager token.Manager, guard *lockout.Guard, hasher *password.Hasher, tokenDuration, refreshTokenDuration, challengeDuration time.Duration) gin.HandlerFunc {
+	// dummyPasswordHash is checked for usernames without an account, so that they take as long as a wrong password
+	// and the response time does not tell them apart
+	dummyPasswordHash := sync.OnceValue(func() string {
+		hash, _ := hasher.Hash("password of the usernames without an account")
+		return hash
+	})
+
 	return func(ctx *gin.Context) {
 		var requestBody LoginRequest
 		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
@@ -32,34 +49,90 @@ func LoginAction(userRepo user.Repository, tokenManager token.Manager, tokenDura
 			return
 		}
 
+		clientIP := ctx.ClientIP()
+		if err := guard.Check(ctx, requestBody.Username, clientIP); err != nil {
+			respondGuardError(ctx, err)
+			return
+		}
+
 		u, err := userRepo.FindByUsername(ctx, requestBody.Username)
-		if err != nil {
-			if errors.Is(err, user.ErrUserNotFound) {
-				ctx.JSON(http.StatusNotFound, gin.H{"error": "username or password is incorrect"})
+		if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		passwordHash := dummyPasswordHash()
+		if u != nil {
+			passwordHash = u.Password
+		}
+
+		valid, needsRehash := hasher.Verify(passwordHash, requestBody.Password)
+		if !valid || u == nil {
+			if err := guard.RecordFailure(ctx, requestBody.Username, clientIP); err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
 			}
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "username or password is incorrect"})
+			return
+		}
 
+		// the password is only known in plain text now, the login goes on with the old hash if it can not be replaced
+		if needsRehash {
+			if err := rehashPassword(ctx, userRepo, hasher, u, requestBody.Password); err != nil {
+				log.Printf("could not rehash the password of user %d: %v", u.ID, err)
+			}
+		}
+
+		tf, err := findEnabledTwoFactor(ctx, twoFactorRepo, u.ID)
+		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		if !utils.IsHashPasswordValid(u.Password, requestBody.Password) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "username or password is incorrect"})
+		// the failures are only cleared once the code is confirmed too, so that the password does not let the
+		// code be guessed for ever
+		if tf != nil {
+			challenge, err := newTwoFactorChallenge(tokenManager, u, challengeDuration)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusAccepted, challenge)
 			return
 		}
 
-		token, err := tokenManager.GenerateToken(u.ID, tokenDuration)
+		if err := guard.RecordSuccess(ctx, requestBody.Username); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		s := newSession(ctx)
+		tokens, err := issueSessionTokens(tokenManager, &s, u, tokenDuration, refreshTokenDuration, func(s *session.Session) error {
+			return sessionRepo.CreateSession(ctx, s)
+		})
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
 		ctx.JSON(http.StatusOK, LoginResponse{
-			AccessToken: token,
-			User: UserResponse{
-				ID:       u.ID,
-				Username: u.Username,
-			},
+			AccessToken:  tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			User:         newUserResponse(*u),
 		})
 	}
 }
+
+// rehashPassword replaces the password hash of the user with a hash of the current algorithm and parameters
+func rehashPassword(ctx context.Context, userRepo user.Repository, hasher *password.Hasher, u *user.User, plainPassword string) error {
+	hashedPassword, err := hasher.Hash(plainPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := userRepo.UpdatePassword(ctx, u.ID, hashedPassword); err != nil {
+		return err
+	}
+	u.Password = hashedPassword
+	return nil
+}
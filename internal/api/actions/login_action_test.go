@@ -2,6 +2,7 @@ package actions_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -9,13 +10,17 @@ import (
 	"time"
 
 	"github.com/bxcodec/faker/v3"
+	"github.com/mohammad19khodaei/restaurant_reservation/config"
 	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
-	"github.com/mohammad19khodaei/restaurant_reservation/internal/utils"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type loginRequestBody struct {
@@ -27,10 +32,19 @@ func TestLoginAction(t *testing.T) {
 	password := faker.Password()
 	u := createRandomUser(password)
 
+	// a user whose password was hashed before the bcrypt cost was raised
+	outdated := createRandomUser(password)
+	outdatedHash, err := bcrypt.GenerateFromPassword([]byte(password), c.Password.Bcrypt.Cost+1)
+	require.NoError(t, err)
+	outdated.Password = string(outdatedHash)
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
 	testCases := []struct {
 		name          string
 		requestBody   loginRequestBody
-		buildStubs    func(repository *mockdb.UserMockRepository, requestBody loginRequestBody)
+		buildStubs    func(repository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository, requestBody loginRequestBody)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody loginRequestBody)
 	}{
 		{
@@ -39,7 +53,7 @@ func TestLoginAction(t *testing.T) {
 				Username: faker.Username(),
 				Password: faker.Password(),
 			},
-			buildStubs: func(repository *mockdb.UserMockRepository, requestBody loginRequestBody) {
+			buildStubs: func(repository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository, requestBody loginRequestBody) {
 				repository.EXPECT().FindByUsername(gomock.Any(), gomock.Eq(requestBody.Username)).
 					Times(1).
 					Return(nil, user.ErrUserNotFound)
@@ -54,7 +68,7 @@ func TestLoginAction(t *testing.T) {
 				Username: u.Username,
 				Password: faker.Password(),
 			},
-			buildStubs: func(repository *mockdb.UserMockRepository, requestBody loginRequestBody) {
+			buildStubs: func(repository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository, requestBody loginRequestBody) {
 				repository.EXPECT().FindByUsername(gomock.Any(), gomock.Eq(requestBody.Username)).
 					Times(1).
 					Return(&u, nil)
@@ -63,16 +77,85 @@ func TestLoginAction(t *testing.T) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
+		{
+			name: "outdated password hash",
+			requestBody: loginRequestBody{
+				Username: outdated.Username,
+				Password: password,
+			},
+			buildStubs: func(repository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository, requestBody loginRequestBody) {
+				repository.EXPECT().FindByUsername(gomock.Any(), outdated.Username).Return(&outdated, nil)
+				repository.EXPECT().
+					UpdatePassword(gomock.Any(), outdated.ID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, hashedPassword string) error {
+						cost, err := bcrypt.Cost([]byte(hashedPassword))
+						require.NoError(t, err)
+						require.Equal(t, c.Password.Bcrypt.Cost, cost)
+						require.NoError(t, bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)))
+						return nil
+					})
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), outdated.ID).Return(nil, twofactor.ErrTwoFactorNotFound)
+				sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody loginRequestBody) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "two-factor authentication enabled",
+			requestBody: loginRequestBody{
+				Username: u.Username,
+				Password: password,
+			},
+			buildStubs: func(repository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository, requestBody loginRequestBody) {
+				enabledAt := time.Now()
+				repository.EXPECT().FindByUsername(gomock.Any(), gomock.Eq(requestBody.Username)).
+					Times(1).
+					Return(&u, nil)
+				twoFactorRepository.EXPECT().
+					FindByUserID(gomock.Any(), u.ID).
+					Return(&twofactor.TwoFactor{UserID: u.ID, Secret: "JBSWY3DPEHPK3PXP", EnabledAt: &enabledAt}, nil)
+				sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody loginRequestBody) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var resp actions.TwoFactorChallengeResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+				require.WithinDuration(t, time.Now().Add(c.TwoFactor.ChallengeDuration), resp.ExpiresAt, time.Second)
+
+				// the challenge token is no access token
+				payload, err := tokenManager.VerifyToken(resp.ChallengeToken)
+				require.NoError(t, err)
+				require.False(t, payload.IsAccessToken())
+				require.Equal(t, token.PurposeTwoFactorChallenge, payload.Purpose)
+			},
+		},
 		{
 			name: "ok",
 			requestBody: loginRequestBody{
 				Username: u.Username,
 				Password: password,
 			},
-			buildStubs: func(repository *mockdb.UserMockRepository, requestBody loginRequestBody) {
+			buildStubs: func(repository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository, requestBody loginRequestBody) {
 				repository.EXPECT().FindByUsername(gomock.Any(), gomock.Eq(requestBody.Username)).
 					Times(1).
 					Return(&u, nil)
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), u.ID).Return(nil, twofactor.ErrTwoFactorNotFound)
+				sessionRepository.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, s *session.Session) error {
+						require.Equal(t, u.ID, s.UserID)
+						require.NotEmpty(t, s.RefreshTokenHash)
+						require.Empty(t, s.PreviousRefreshTokenHash)
+						require.NotEmpty(t, s.AccessTokenID)
+						require.WithinDuration(t, time.Now().Add(c.App.RefreshTokenDuration), s.ExpiresAt, time.Second)
+						require.Equal(t, "test-agent/1.0", s.UserAgent)
+						require.Equal(t, "192.0.2.1", s.IPAddress)
+						s.ID = 7
+						return nil
+					})
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody loginRequestBody) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -82,7 +165,13 @@ func TestLoginAction(t *testing.T) {
 				require.NoError(t, err)
 
 				require.NotEmpty(t, resp.AccessToken)
+				require.NotEmpty(t, resp.RefreshToken)
 				require.Equal(t, u.Username, resp.User.Username)
+
+				// the access token names the session it was issued for
+				payload, err := tokenManager.VerifyToken(resp.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, 7, payload.SessionID)
 			},
 		},
 	}
@@ -91,15 +180,19 @@ func TestLoginAction(t *testing.T) {
 	defer ctrl.Finish()
 
 	repository := mockdb.NewUserMockRepository(ctrl)
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	twoFactorRepository := mockdb.NewTwoFactorMockRepository(ctrl)
 	app, err := application.New(c)
 	require.NoError(t, err)
 
 	app.SetUserRepository(repository)
+	app.SetSessionRepository(sessionRepository)
+	app.SetTwoFactorRepository(twoFactorRepository)
 	app.RegisterRoutes()
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			tc.buildStubs(repository, tc.requestBody)
+			tc.buildStubs(repository, sessionRepository, twoFactorRepository, tc.requestBody)
 			recorder := httptest.NewRecorder()
 			requestBody := tc.requestBody
 
@@ -107,6 +200,7 @@ func TestLoginAction(t *testing.T) {
 			require.NoError(t, err)
 
 			request := httptest.NewRequest(http.MethodPost, "/users/login", bytes.NewBuffer(jsonData))
+			request.Header.Set("User-Agent", "test-agent/1.0")
 			app.Router.ServeHTTP(recorder, request)
 
 			tc.checkResponse(t, recorder, requestBody)
@@ -115,10 +209,83 @@ func TestLoginAction(t *testing.T) {
 }
 
 func createRandomUser(password string) user.User {
-	hashedPassword, _ := utils.HashPassword(password)
+	hashedPassword, _ := hashPassword(password)
 	return user.User{
 		Username:  faker.Username(),
 		Password:  hashedPassword,
 		CreatedAt: time.Now(),
 	}
 }
+
+func TestLoginActionThrottling(t *testing.T) {
+	password := faker.Password()
+	u := createRandomUser(password)
+
+	cfg := *c
+	cfg.LoginThrottling.Account = config.LoginThrottlingPolicy{MaxFailures: 2, LockoutDuration: time.Hour, ResetAfter: time.Hour}
+	cfg.LoginThrottling.IP = config.LoginThrottlingPolicy{MaxFailures: 5, LockoutDuration: time.Hour, ResetAfter: time.Hour}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewUserMockRepository(ctrl)
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	twoFactorRepository := mockdb.NewTwoFactorMockRepository(ctrl)
+	twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), gomock.Any()).Return(nil, twofactor.ErrTwoFactorNotFound).AnyTimes()
+	app, err := application.New(&cfg)
+	require.NoError(t, err)
+	app.SetUserRepository(repository)
+	app.SetSessionRepository(sessionRepository)
+	app.SetTwoFactorRepository(twoFactorRepository)
+	app.RegisterRoutes()
+
+	login := func(username, password, remoteAddr string) *httptest.ResponseRecorder {
+		jsonData, err := json.Marshal(loginRequestBody{Username: username, Password: password})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/users/login", bytes.NewBuffer(jsonData))
+		request.RemoteAddr = remoteAddr
+		// proxies are not trusted, so the header can not spread the failures over several addresses
+		request.Header.Set("X-Forwarded-For", faker.IPv4())
+		app.Router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("unknown and existing usernames are locked out alike", func(t *testing.T) {
+		unknown := faker.Username()
+		repository.EXPECT().FindByUsername(gomock.Any(), unknown).Times(2).Return(nil, user.ErrUserNotFound)
+		repository.EXPECT().FindByUsername(gomock.Any(), u.Username).Times(2).Return(&u, nil)
+
+		for _, username := range []string{unknown, u.Username} {
+			require.Equal(t, http.StatusNotFound, login(username, faker.Password(), "192.0.2.1:1234").Code)
+			require.Equal(t, http.StatusNotFound, login(username, faker.Password(), "192.0.2.2:1234").Code)
+		}
+
+		// even the right password has to wait for the lockout to end
+		for _, username := range []string{unknown, u.Username} {
+			recorder := login(username, password, "192.0.2.3:1234")
+			require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			require.Equal(t, "3600", recorder.Header().Get("Retry-After"))
+		}
+	})
+
+	t.Run("an address is locked out for every username", func(t *testing.T) {
+		repository.EXPECT().FindByUsername(gomock.Any(), gomock.Any()).Times(5).Return(nil, user.ErrUserNotFound)
+		for i := 0; i < 5; i++ {
+			require.Equal(t, http.StatusNotFound, login(faker.Username(), faker.Password(), "192.0.2.10:1234").Code)
+		}
+
+		require.Equal(t, http.StatusTooManyRequests, login(faker.Username(), faker.Password(), "192.0.2.10:4321").Code)
+	})
+
+	t.Run("success clears the failures of the account", func(t *testing.T) {
+		other := createRandomUser(password)
+		repository.EXPECT().FindByUsername(gomock.Any(), other.Username).Times(3).Return(&other, nil)
+		sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil)
+
+		require.Equal(t, http.StatusNotFound, login(other.Username, faker.Password(), "192.0.2.20:1234").Code)
+		require.Equal(t, http.StatusOK, login(other.Username, password, "192.0.2.20:1234").Code)
+		require.Equal(t, http.StatusNotFound, login(other.Username, faker.Password(), "192.0.2.21:1234").Code)
+	})
+}
@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// LogoutRequest represents the request body for logging out
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutAction is a function that handles logging out, it revokes the session of the refresh token as well as
+// the access token the request is authenticated with
+func LogoutAction(sessionRepo session.Repository, revocations token.RevocationList) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody LogoutRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		payload := ctx.MustGet(middlewares.AuthPayloadKey).(*token.Payload)
+
+		s, err := sessionRepo.FindByRefreshToken(ctx, token.HashOpaqueToken(requestBody.RefreshToken))
+		if err != nil {
+			if errors.Is(err, session.ErrSessionNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// the session of another user is not revealed
+		if s.UserID != payload.UserID {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": session.ErrSessionNotFound.Error()})
+			return
+		}
+
+		if err := revokeSession(ctx, sessionRepo, revocations, s); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := revocations.Revoke(ctx, payload.ID, payload.ExpiresAt.Time); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+	}
+}
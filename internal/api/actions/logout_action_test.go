@@ -0,0 +1,133 @@
+package actions_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLogoutAction(t *testing.T) {
+	refreshToken, refreshTokenHash, err := token.NewOpaqueToken()
+	require.NoError(t, err)
+
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	s := session.Session{
+		ID:                   1,
+		UserID:               guest.ID,
+		RefreshTokenHash:     refreshTokenHash,
+		AccessTokenID:        "access-token-id",
+		AccessTokenExpiresAt: time.Now().Add(time.Minute),
+		ExpiresAt:            time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name          string
+		requestBody   string
+		buildStubs    func(sessionRepository *mockdb.SessionMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "without refresh token",
+			requestBody: `{}`,
+			buildStubs: func(sessionRepository *mockdb.SessionMockRepository) {
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "session not found",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(sessionRepository *mockdb.SessionMockRepository) {
+				sessionRepository.EXPECT().
+					FindByRefreshToken(gomock.Any(), refreshTokenHash).
+					Return(nil, session.ErrSessionNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "session of another user",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(sessionRepository *mockdb.SessionMockRepository) {
+				other := s
+				other.UserID = 2
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), refreshTokenHash).Return(&other, nil)
+				sessionRepository.EXPECT().RevokeSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(sessionRepository *mockdb.SessionMockRepository) {
+				found := s
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), refreshTokenHash).Return(&found, nil)
+				sessionRepository.EXPECT().RevokeSession(gomock.Any(), s.ID).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetSessionRepository(sessionRepository)
+	app.RegisterRoutes()
+
+	logout := func(accessToken string, requestBody string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/users/logout", bytes.NewBufferString(requestBody))
+		request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken))
+		app.Router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(sessionRepository)
+
+			accessToken, err := tokenManager.GenerateToken(guest.ID, guest.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+
+			tc.checkResponse(t, logout(accessToken, tc.requestBody))
+		})
+	}
+
+	t.Run("access token is revoked", func(t *testing.T) {
+		found := s
+		sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), refreshTokenHash).Return(&found, nil)
+		sessionRepository.EXPECT().RevokeSession(gomock.Any(), s.ID).Return(nil)
+
+		accessToken, err := tokenManager.GenerateToken(guest.ID, guest.Role, c.App.TokenDuration)
+		require.NoError(t, err)
+		requestBody := fmt.Sprintf(`{"refresh_token": %q}`, refreshToken)
+
+		require.Equal(t, http.StatusOK, logout(accessToken, requestBody).Code)
+		require.Equal(t, http.StatusUnauthorized, logout(accessToken, requestBody).Code)
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
 )
 
 var (
@@ -23,3 +24,12 @@ func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
 }
+
+// hashPassword hashes the password with the bcrypt cost of the testing config, the current algorithm of it
+func hashPassword(plainPassword string) (string, error) {
+	b, err := password.NewBcrypt(c.Password.Bcrypt.Cost)
+	if err != nil {
+		return "", err
+	}
+	return b.Hash(plainPassword)
+}
@@ -0,0 +1,83 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+// ModifyReservationRequest represents the request body for modifying a reservation, omitted fields keep their value
+type ModifyReservationRequest struct {
+	SeatsCount      int    `json:"seats_count" binding:"omitempty,min=1,max=30"`
+	Date            string `json:"date"`
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=15,max=720"`
+}
+
+// ModifyReservationAction is a function that handles modifying a reservation of the authenticated user
+func ModifyReservationAction(repository reservation.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reservationID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation id"})
+			return
+		}
+
+		var requestBody ModifyReservationRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var modification reservation.Modification
+		if requestBody.Date != "" {
+			// Validate date format (RFC 3339)
+			modification.StartAt, err = time.Parse(time.RFC3339, requestBody.Date)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, expected RFC 3339 (e.g. 2025-01-01T19:30:00Z)"})
+				return
+			}
+
+			if time.Now().After(modification.StartAt) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, should be in the future"})
+				return
+			}
+		}
+
+		if requestBody.DurationMinutes > 0 {
+			modification.Duration = time.Duration(requestBody.DurationMinutes) * time.Minute
+		}
+
+		modification.SeatsCount = requestBody.SeatsCount
+		if modification.SeatsCount%2 != 0 {
+			modification.SeatsCount++
+		}
+
+		userID := ctx.MustGet(middlewares.AuthUserIDKey).(int)
+
+		resv, err := repository.ModifyReservation(ctx, reservationID, userID, modification)
+		if err != nil {
+			if errors.Is(err, reservation.ErrReservationNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			// the reservation of another user is not revealed
+			if errors.Is(err, reservation.ErrReservationNotOwned) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": reservation.ErrReservationNotFound.Error()})
+				return
+			}
+			if errors.Is(err, reservation.ErrReservationNotModifiable) || errors.Is(err, reservation.ErrNoTablesAreAvailable) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, newReservationResponse(*resv))
+	}
+}
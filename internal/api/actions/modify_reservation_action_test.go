@@ -0,0 +1,153 @@
+package actions_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestModifyReservationAction(t *testing.T) {
+	userID := 1
+	startAt := time.Now().AddDate(0, 0, 1).Truncate(time.Second).UTC()
+	testCases := []struct {
+		name          string
+		requestBody   modifyReservationRequest
+		buildStubs    func(repository *mockdb.ReservationMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "date in the past",
+			requestBody: modifyReservationRequest{Date: time.Now().AddDate(0, 0, -1).Format(time.RFC3339)},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().ModifyReservation(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "reservation of another user",
+			requestBody: modifyReservationRequest{SeatsCount: 4},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().
+					ModifyReservation(gomock.Any(), 1, userID, reservation.Modification{SeatsCount: 4}).
+					Return(nil, reservation.ErrReservationNotOwned)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), reservation.ErrReservationNotFound.Error())
+			},
+		},
+		{
+			name:        "no tables are available",
+			requestBody: modifyReservationRequest{SeatsCount: 10},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().
+					ModifyReservation(gomock.Any(), 1, userID, reservation.Modification{SeatsCount: 10}).
+					Return(nil, reservation.ErrNoTablesAreAvailable)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:        "reservation already seated",
+			requestBody: modifyReservationRequest{SeatsCount: 2},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().
+					ModifyReservation(gomock.Any(), 1, userID, reservation.Modification{SeatsCount: 2}).
+					Return(nil, reservation.ErrReservationNotModifiable)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "ok",
+			requestBody: modifyReservationRequest{
+				SeatsCount:      5,
+				Date:            startAt.Format(time.RFC3339),
+				DurationMinutes: 90,
+			},
+			buildStubs: func(repository *mockdb.ReservationMockRepository) {
+				repository.EXPECT().
+					ModifyReservation(gomock.Any(), 1, userID, reservation.Modification{
+						SeatsCount: 6,
+						StartAt:    startAt,
+						Duration:   90 * time.Minute,
+					}).
+					Return(&reservation.Reservation{
+						ID:         1,
+						UserID:     uint(userID),
+						TableID:    2,
+						SeatsCount: 6,
+						Price:      money.New(5000, "USD"),
+						StartAt:    startAt,
+						EndAt:      startAt.Add(90 * time.Minute),
+						Status:     reservation.StatusConfirmed,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.ReservationResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, 6, resp.SeatsCount)
+				require.Equal(t, 90, resp.DurationMinutes)
+				require.Equal(t, money.New(5000, "USD"), resp.Price)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewReservationMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetReservationRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository)
+
+			recorder := httptest.NewRecorder()
+			jsonData, err := json.Marshal(tc.requestBody)
+			require.NoError(t, err)
+			request := httptest.NewRequest(http.MethodPatch, "/reservations/1", bytes.NewReader(jsonData))
+			token, err := tokenManager.GenerateToken(userID, user.RoleGuest, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+type modifyReservationRequest struct {
+	SeatsCount      int    `json:"seats_count,omitempty"`
+	Date            string `json:"date,omitempty"`
+	DurationMinutes int    `json:"duration_minutes,omitempty"`
+}
@@ -0,0 +1,177 @@
+package actions
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+const (
+	// oidcUsernameHashLength is the number of hex characters of the hash of the subject in the usernames of the users
+	// signing up through a provider
+	oidcUsernameHashLength = 12
+	// oidcStateCookieName is the cookie binding a sign-in at a provider to the browser that started it
+	oidcStateCookieName = "oidc_state"
+)
+
+// findOIDCProvider finds the provider of the provider parameter, responding with an error when there is none
+func findOIDCProvider(ctx *gin.Context, providers map[string]*oidc.Provider) (*oidc.Provider, bool) {
+	provider, ok := providers[ctx.Param("provider")]
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "unknown identity provider"})
+		return nil, false
+	}
+	return provider, true
+}
+
+// startOIDCSignIn stores a new sign-in at the provider and returns the URL of the provider the user signs in at, the
+// identity is linked to the account of the user when the user ID is set. The hash of the state is set as a cookie, so
+// that only the browser that started the sign-in can finish it.
+func startOIDCSignIn(ctx *gin.Context, identityRepo identity.Repository, provider *oidc.Provider, stateDuration time.Duration, userID *int) (string, error) {
+	state, stateHash, err := token.NewOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := oidc.NewNonce()
+	if err != nil {
+		return "", err
+	}
+	codeVerifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	// the provider is asked first so that no sign-in is stored when it can not be reached
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(codeVerifier))
+	if err != nil {
+		return "", err
+	}
+
+	loginState := identity.LoginState{
+		StateHash:    stateHash,
+		Provider:     provider.Name(),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		UserID:       userID,
+		ExpiresAt:    time.Now().Add(stateDuration),
+	}
+	if err := identityRepo.CreateLoginState(ctx, &loginState); err != nil {
+		return "", err
+	}
+
+	setOIDCStateCookie(ctx, provider, stateHash, int(stateDuration.Seconds()))
+	return authURL, nil
+}
+
+// setOIDCStateCookie sets the state cookie for the callback of the provider only, a negative max age deletes it. It
+// is sent along when the provider redirects the browser back, but not with requests other sites make.
+func setOIDCStateCookie(ctx *gin.Context, provider *oidc.Provider, value string, maxAge int) {
+	path, secure := "/", false
+	if redirectURL, err := url.Parse(provider.RedirectURL()); err == nil {
+		secure = redirectURL.Scheme == "https"
+		if redirectURL.Path != "" {
+			path = redirectURL.Path
+		}
+	}
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcStateCookieName, value, maxAge, path, "", secure, true)
+}
+
+// hasOIDCStateCookie reports whether the browser of the request started the sign-in of the state
+func hasOIDCStateCookie(ctx *gin.Context, state string) bool {
+	stateHash, err := ctx.Cookie(oidcStateCookieName)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(stateHash), []byte(token.HashOpaqueToken(state))) == 1
+}
+
+// respondOIDCError responds with the error of a provider, telling a provider that can not be reached from a sign-in
+// it refused
+func respondOIDCError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, oidc.ErrCodeRefused), errors.Is(err, oidc.ErrInvalidIDToken):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, oidc.ErrProviderUnavailable):
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// findOrRegisterOIDCUser returns the user of the identity the provider told, linking the identity to the account of
+// its email or registering a new user when it is not linked yet. An account is only linked by its email when both the
+// provider and the account verified it, so that nobody takes over an account by setting its email elsewhere.
+func findOrRegisterOIDCUser(ctx context.Context, userRepo user.Repository, identityRepo identity.Repository, providerName string, claims oidc.Claims) (*user.User, error) {
+	i, err := identityRepo.FindIdentity(ctx, providerName, claims.Subject)
+	if err == nil {
+		return userRepo.FindByID(ctx, i.UserID)
+	}
+	if !errors.Is(err, identity.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	email := ""
+	if claims.EmailVerified {
+		email = strings.ToLower(claims.Email)
+	}
+	newIdentity := identity.Identity{
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    strings.ToLower(claims.Email),
+	}
+
+	if email != "" {
+		u, err := userRepo.FindByEmail(ctx, email)
+		if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+		if u != nil {
+			if !u.IsEmailVerified() {
+				return nil, identity.ErrUnverifiedAccount
+			}
+			newIdentity.UserID = u.ID
+			if err := identityRepo.LinkIdentity(ctx, &newIdentity); err != nil {
+				return nil, err
+			}
+			return u, nil
+		}
+	}
+
+	u := &user.User{
+		Username: oidcUsername(providerName, claims.Subject),
+		Role:     user.RoleGuest,
+		Name:     claims.Name,
+		Email:    email,
+	}
+	// the provider verified the email already
+	if email != "" {
+		now := time.Now()
+		u.EmailVerifiedAt = &now
+	}
+	if err := identityRepo.RegisterUser(ctx, u, &newIdentity); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// oidcUsername returns the username of a user signing up through a provider, which tells nothing about the identity
+func oidcUsername(providerName, subject string) string {
+	sum := sha256.Sum256([]byte(subject))
+	return fmt.Sprintf("%s-%s", providerName, hex.EncodeToString(sum[:])[:oidcUsernameHashLength])
+}
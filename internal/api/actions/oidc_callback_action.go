@@ -0,0 +1,162 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// OIDCCallbackRequest represents the query an OpenID Connect provider sends the user back to the callback with
+type OIDCCallbackRequest struct {
+	State            string `form:"state" binding:"required"`
+	Code             string `form:"code"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// OIDCCallbackAction is a function that handles the user coming back from signing in at an OpenID Connect provider.
+// Only the browser that started the sign-in finishes it. The identity is linked to the account of the user who
+// started linking it, who has to be the user of the request, otherwise the user of the identity is logged in like with
+// a password, signing up first when the identity is new.
+func OIDCCallbackAction(userRepo user.Repository, sessionRepo session.Repository, twoFactorRepo twofactor.Repository, identityRepo identity.Repository, providers map[string]*oidc.Provider, tokenManager token.Manager, tokenDuration, refreshTokenDuration, challengeDuration time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestQuery OIDCCallbackRequest
+		if err := ctx.ShouldBindQuery(&requestQuery); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		provider, ok := findOIDCProvider(ctx, providers)
+		if !ok {
+			return
+		}
+
+		// a link of another browser would sign its user in to, or link their identity to, the account of whoever
+		// started the sign-in
+		if !hasOIDCStateCookie(ctx, requestQuery.State) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": identity.ErrInvalidState.Error()})
+			return
+		}
+		setOIDCStateCookie(ctx, provider, "", -1)
+
+		// the sign-in is used up whatever the provider answered
+		loginState, err := identityRepo.ConsumeLoginState(ctx, token.HashOpaqueToken(requestQuery.State))
+		if err != nil {
+			if errors.Is(err, identity.ErrInvalidState) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if loginState.Provider != provider.Name() {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": identity.ErrInvalidState.Error()})
+			return
+		}
+
+		if loginState.IsLinking() {
+			userID, ok := ctx.Get(middlewares.AuthUserIDKey)
+			if !ok {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required to link an identity"})
+				return
+			}
+			if userID.(int) != *loginState.UserID {
+				ctx.JSON(http.StatusForbidden, gin.H{"error": "The identity can only be linked by the user who started linking it"})
+				return
+			}
+		}
+
+		if requestQuery.Error != "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "sign-in at the identity provider failed: " + requestQuery.Error + " " + requestQuery.ErrorDescription})
+			return
+		}
+		if requestQuery.Code == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+			return
+		}
+
+		claims, err := provider.Exchange(ctx, requestQuery.Code, loginState.CodeVerifier, loginState.Nonce)
+		if err != nil {
+			respondOIDCError(ctx, err)
+			return
+		}
+
+		if loginState.IsLinking() {
+			linked := identity.Identity{
+				UserID:   *loginState.UserID,
+				Provider: provider.Name(),
+				Subject:  claims.Subject,
+				Email:    strings.ToLower(claims.Email),
+			}
+			if err := identityRepo.LinkIdentity(ctx, &linked); err != nil {
+				if errors.Is(err, identity.ErrIdentityAlreadyLinked) || errors.Is(err, identity.ErrProviderAlreadyLinked) {
+					ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+					return
+				}
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			ctx.JSON(http.StatusOK, gin.H{"message": "Identity linked successfully"})
+			return
+		}
+
+		u, err := findOrRegisterOIDCUser(ctx, userRepo, identityRepo, provider.Name(), claims)
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, identity.ErrUnverifiedAccount) || errors.Is(err, identity.ErrIdentityAlreadyLinked) ||
+				errors.Is(err, identity.ErrProviderAlreadyLinked) || errors.Is(err, user.ErrUsernameAlreadyExists) ||
+				errors.Is(err, user.ErrEmailAlreadyExists) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		tf, err := findEnabledTwoFactor(ctx, twoFactorRepo, u.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// the provider stands in for the password, not for the second factor
+		if tf != nil {
+			challenge, err := newTwoFactorChallenge(tokenManager, u, challengeDuration)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusAccepted, challenge)
+			return
+		}
+
+		s := newSession(ctx)
+		tokens, err := issueSessionTokens(tokenManager, &s, u, tokenDuration, refreshTokenDuration, func(s *session.Session) error {
+			return sessionRepo.CreateSession(ctx, s)
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, LoginResponse{
+			AccessToken:  tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			User:         newUserResponse(*u),
+		})
+	}
+}
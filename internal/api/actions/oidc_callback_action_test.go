@@ -0,0 +1,389 @@
+package actions_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc/oidctest"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOIDCCallbackAction(t *testing.T) {
+	verifiedAt := time.Now().Add(-time.Hour)
+	u := user.User{ID: 1, Username: "jane", Role: user.RoleGuest, Email: "jane@example.com", EmailVerifiedAt: &verifiedAt}
+	jane := oidctest.User{Subject: "248289761001", Email: "Jane@example.com", EmailVerified: true, Name: "Jane Doe"}
+
+	type stubs struct {
+		userRepository      *mockdb.UserMockRepository
+		sessionRepository   *mockdb.SessionMockRepository
+		twoFactorRepository *mockdb.TwoFactorMockRepository
+		identityRepository  *mockdb.IdentityMockRepository
+	}
+
+	testCases := []struct {
+		name          string
+		provider      string
+		user          oidctest.User
+		tamper        func(claims jwt.MapClaims)
+		editQuery     func(query url.Values)
+		editCookie    func(cookie *http.Cookie) *http.Cookie
+		buildStubs    func(s stubs)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "unknown provider",
+			provider: "unknown",
+			user:     jane,
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "forged state",
+			provider: "fake",
+			user:     jane,
+			editQuery: func(query url.Values) {
+				query.Set("state", "forged")
+			},
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "without state cookie",
+			provider: "fake",
+			user:     jane,
+			editCookie: func(cookie *http.Cookie) *http.Cookie {
+				return nil
+			},
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			// a browser sent the callback link of a sign-in someone else started is not signed in to their account
+			name:     "state cookie of another sign-in",
+			provider: "fake",
+			user:     jane,
+			editCookie: func(cookie *http.Cookie) *http.Cookie {
+				return &http.Cookie{Name: cookie.Name, Value: token.HashOpaqueToken("another state")}
+			},
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			// the state of a sign-in at a provider is not taken at the callback of another
+			name:     "state of another provider",
+			provider: "other",
+			user:     jane,
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "sign-in failed at the provider",
+			provider: "fake",
+			user:     jane,
+			editQuery: func(query url.Values) {
+				query.Del("code")
+				query.Set("error", "access_denied")
+			},
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "access_denied")
+			},
+		},
+		{
+			name:     "ID token of another client",
+			provider: "fake",
+			user:     jane,
+			tamper: func(claims jwt.MapClaims) {
+				claims["aud"] = "another client"
+			},
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "linked identity",
+			provider: "fake",
+			user:     jane,
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().
+					FindIdentity(gomock.Any(), "fake", jane.Subject).
+					Return(&identity.Identity{ID: 1, UserID: u.ID, Provider: "fake", Subject: jane.Subject}, nil)
+				s.userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				s.identityRepository.EXPECT().LinkIdentity(gomock.Any(), gomock.Any()).Times(0)
+				s.identityRepository.EXPECT().RegisterUser(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				s.twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), u.ID).Return(nil, twofactor.ErrTwoFactorNotFound)
+				s.sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireLoggedIn(t, recorder, u.ID)
+
+				// the state cookie is deleted along with the sign-in
+				cookies := recorder.Result().Cookies()
+				require.Len(t, cookies, 1)
+				require.Equal(t, "oidc_state", cookies[0].Name)
+				require.Negative(t, cookies[0].MaxAge)
+			},
+		},
+		{
+			name:     "linked by verified email",
+			provider: "fake",
+			user:     jane,
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), "fake", jane.Subject).Return(nil, identity.ErrIdentityNotFound)
+				s.userRepository.EXPECT().FindByEmail(gomock.Any(), "jane@example.com").Return(&u, nil)
+				s.identityRepository.EXPECT().
+					LinkIdentity(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, i *identity.Identity) error {
+						require.Equal(t, identity.Identity{UserID: u.ID, Provider: "fake", Subject: jane.Subject, Email: "jane@example.com"}, *i)
+						return nil
+					})
+				s.identityRepository.EXPECT().RegisterUser(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				s.twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), u.ID).Return(nil, twofactor.ErrTwoFactorNotFound)
+				s.sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireLoggedIn(t, recorder, u.ID)
+			},
+		},
+		{
+			// whoever set the email of the account elsewhere does not take it over
+			name:     "account of the email not verified",
+			provider: "fake",
+			user:     jane,
+			buildStubs: func(s stubs) {
+				unverified := u
+				unverified.EmailVerifiedAt = nil
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), "fake", jane.Subject).Return(nil, identity.ErrIdentityNotFound)
+				s.userRepository.EXPECT().FindByEmail(gomock.Any(), "jane@example.com").Return(&unverified, nil)
+				s.identityRepository.EXPECT().LinkIdentity(gomock.Any(), gomock.Any()).Times(0)
+				s.identityRepository.EXPECT().RegisterUser(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				s.sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "sign-up",
+			provider: "fake",
+			user:     jane,
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), "fake", jane.Subject).Return(nil, identity.ErrIdentityNotFound)
+				s.userRepository.EXPECT().FindByEmail(gomock.Any(), "jane@example.com").Return(nil, user.ErrUserNotFound)
+				s.identityRepository.EXPECT().
+					RegisterUser(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, registered *user.User, i *identity.Identity) error {
+						require.True(t, strings.HasPrefix(registered.Username, "fake-"))
+						require.NotContains(t, registered.Username, jane.Subject)
+						require.Empty(t, registered.Password)
+						require.Equal(t, user.RoleGuest, registered.Role)
+						require.Equal(t, "Jane Doe", registered.Name)
+						require.Equal(t, "jane@example.com", registered.Email)
+						require.True(t, registered.IsEmailVerified())
+						require.Equal(t, identity.Identity{Provider: "fake", Subject: jane.Subject, Email: "jane@example.com"}, *i)
+						registered.ID = 2
+						return nil
+					})
+				s.twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), 2).Return(nil, twofactor.ErrTwoFactorNotFound)
+				s.sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireLoggedIn(t, recorder, 2)
+			},
+		},
+		{
+			// an email the provider did not verify is neither linked by nor kept
+			name:     "sign-up with unverified email",
+			provider: "fake",
+			user:     oidctest.User{Subject: "90342", Email: "jane@example.com", Name: "Jane Doe"},
+			buildStubs: func(s stubs) {
+				s.identityRepository.EXPECT().FindIdentity(gomock.Any(), "fake", "90342").Return(nil, identity.ErrIdentityNotFound)
+				s.userRepository.EXPECT().FindByEmail(gomock.Any(), gomock.Any()).Times(0)
+				s.identityRepository.EXPECT().
+					RegisterUser(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, registered *user.User, _ *identity.Identity) error {
+						require.Empty(t, registered.Email)
+						require.False(t, registered.IsEmailVerified())
+						registered.ID = 3
+						return nil
+					})
+				s.twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), 3).Return(nil, twofactor.ErrTwoFactorNotFound)
+				s.sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireLoggedIn(t, recorder, 3)
+			},
+		},
+		{
+			name:     "two-factor authentication enabled",
+			provider: "fake",
+			user:     jane,
+			buildStubs: func(s stubs) {
+				enabledAt := time.Now()
+				s.identityRepository.EXPECT().
+					FindIdentity(gomock.Any(), "fake", jane.Subject).
+					Return(&identity.Identity{ID: 1, UserID: u.ID, Provider: "fake", Subject: jane.Subject}, nil)
+				s.userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				s.twoFactorRepository.EXPECT().
+					FindByUserID(gomock.Any(), u.ID).
+					Return(&twofactor.TwoFactor{UserID: u.ID, Secret: "JBSWY3DPEHPK3PXP", EnabledAt: &enabledAt}, nil)
+				s.sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var resp actions.TwoFactorChallengeResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+				require.NotEmpty(t, resp.ChallengeToken)
+			},
+		},
+	}
+
+	fake, providers := newOIDCProviders(t)
+	providers["other"] = oidc.NewProvider(oidc.ProviderConfig{
+		Name:         "other",
+		Issuer:       fake.Issuer(),
+		ClientID:     "restaurant",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/users/oidc/other/callback",
+	}, http.DefaultClient)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := stubs{
+		userRepository:      mockdb.NewUserMockRepository(ctrl),
+		sessionRepository:   mockdb.NewSessionMockRepository(ctrl),
+		twoFactorRepository: mockdb.NewTwoFactorMockRepository(ctrl),
+		identityRepository:  mockdb.NewIdentityMockRepository(ctrl),
+	}
+	stubLoginStates(s.identityRepository)
+
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(s.userRepository)
+	app.SetSessionRepository(s.sessionRepository)
+	app.SetTwoFactorRepository(s.twoFactorRepository)
+	app.SetIdentityRepository(s.identityRepository)
+	app.Services.OIDCProviders = providers
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(s)
+			fake.SignIn(tc.user)
+			fake.TamperIDToken = tc.tamper
+			defer func() { fake.TamperIDToken = nil }()
+
+			query, stateCookie := signInAtFakeProvider(t, app, fake, "/users/oidc/fake", "")
+			if tc.editQuery != nil {
+				tc.editQuery(query)
+			}
+			if tc.editCookie != nil {
+				stateCookie = tc.editCookie(stateCookie)
+			}
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/users/oidc/"+tc.provider+"/callback?"+query.Encode(), nil)
+			if stateCookie != nil {
+				request.AddCookie(stateCookie)
+			}
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+// signInAtFakeProvider starts a sign-in at the path, signs in at the fake provider and returns the query the provider
+// sends the user back to the callback with, along with the state cookie the browser sends with it
+func signInAtFakeProvider(t *testing.T, app *application.Application, fake *oidctest.Provider, path, authorization string) (url.Values, *http.Cookie) {
+	recorder := httptest.NewRecorder()
+	var authURL string
+	if authorization == "" {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		app.Router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusFound, recorder.Code)
+		authURL = recorder.Header().Get("Location")
+	} else {
+		request := httptest.NewRequest(http.MethodPost, path, nil)
+		request.Header.Set("Authorization", authorization)
+		app.Router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp actions.LinkIdentityResponse
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+		authURL = resp.AuthorizationURL
+	}
+
+	var stateCookie *http.Cookie
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == "oidc_state" {
+			stateCookie = cookie
+		}
+	}
+	require.NotNil(t, stateCookie)
+
+	return fake.Authorize(t, authURL).Query(), stateCookie
+}
+
+// requireLoggedIn requires a login response with the tokens of a session of the user
+func requireLoggedIn(t *testing.T, recorder *httptest.ResponseRecorder, userID int) {
+	var resp actions.LoginResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+	require.Equal(t, userID, resp.User.ID)
+	require.NotEmpty(t, resp.RefreshToken)
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+	payload, err := tokenManager.VerifyToken(resp.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, userID, payload.UserID)
+	require.WithinDuration(t, time.Now(), payload.AuthTime.Time, time.Minute)
+}
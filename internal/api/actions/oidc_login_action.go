@@ -0,0 +1,29 @@
+package actions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc"
+)
+
+// OIDCLoginAction is a function that handles starting a login at an OpenID Connect provider, the user is redirected
+// to the provider which sends them back to the callback
+func OIDCLoginAction(identityRepo identity.Repository, providers map[string]*oidc.Provider, stateDuration time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		provider, ok := findOIDCProvider(ctx, providers)
+		if !ok {
+			return
+		}
+
+		authURL, err := startOIDCSignIn(ctx, identityRepo, provider, stateDuration, nil)
+		if err != nil {
+			respondOIDCError(ctx, err)
+			return
+		}
+
+		ctx.Redirect(http.StatusFound, authURL)
+	}
+}
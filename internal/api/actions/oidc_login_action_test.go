@@ -0,0 +1,157 @@
+package actions_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc/oidctest"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newOIDCProviders starts a fake OpenID Connect provider and returns it along with the providers of the app, where
+// it is named fake
+func newOIDCProviders(t *testing.T) (*oidctest.Provider, map[string]*oidc.Provider) {
+	fake := oidctest.NewProvider(t, "restaurant", "secret")
+	providers := map[string]*oidc.Provider{
+		"fake": oidc.NewProvider(oidc.ProviderConfig{
+			Name:         "fake",
+			Issuer:       fake.Issuer(),
+			ClientID:     "restaurant",
+			ClientSecret: "secret",
+			RedirectURL:  "http://localhost:8080/users/oidc/fake/callback",
+			Scopes:       []string{"email", "profile"},
+		}, http.DefaultClient),
+	}
+	return fake, providers
+}
+
+// stubLoginStates keeps the sign-ins stored through the identity repository in memory, each can be consumed once
+func stubLoginStates(identityRepository *mockdb.IdentityMockRepository) {
+	states := make(map[string]identity.LoginState)
+	identityRepository.EXPECT().CreateLoginState(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, state *identity.LoginState) error {
+			states[state.StateHash] = *state
+			return nil
+		})
+	identityRepository.EXPECT().ConsumeLoginState(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, stateHash string) (*identity.LoginState, error) {
+			state, ok := states[stateHash]
+			delete(states, stateHash)
+			if !ok || !state.ExpiresAt.After(time.Now()) {
+				return nil, identity.ErrInvalidState
+			}
+			return &state, nil
+		})
+}
+
+func TestOIDCLoginAction(t *testing.T) {
+	fake, providers := newOIDCProviders(t)
+	providers["down"] = oidc.NewProvider(oidc.ProviderConfig{
+		Name:        "down",
+		Issuer:      "http://127.0.0.1:1",
+		ClientID:    "restaurant",
+		RedirectURL: "http://localhost:8080/users/oidc/down/callback",
+	}, http.DefaultClient)
+	var createdState *identity.LoginState
+
+	testCases := []struct {
+		name          string
+		provider      string
+		buildStubs    func(identityRepository *mockdb.IdentityMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "unknown provider",
+			provider: "unknown",
+			buildStubs: func(identityRepository *mockdb.IdentityMockRepository) {
+				identityRepository.EXPECT().CreateLoginState(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "provider unavailable",
+			provider: "down",
+			buildStubs: func(identityRepository *mockdb.IdentityMockRepository) {
+				identityRepository.EXPECT().CreateLoginState(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, recorder.Code)
+			},
+		},
+		{
+			name:     "ok",
+			provider: "fake",
+			buildStubs: func(identityRepository *mockdb.IdentityMockRepository) {
+				identityRepository.EXPECT().
+					CreateLoginState(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, state *identity.LoginState) error {
+						require.Equal(t, "fake", state.Provider)
+						require.False(t, state.IsLinking())
+						require.WithinDuration(t, time.Now().Add(c.OIDC.StateDuration), state.ExpiresAt, time.Minute)
+						createdState = state
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusFound, recorder.Code)
+
+				location := recorder.Header().Get("Location")
+				require.True(t, strings.HasPrefix(location, fake.Issuer()+"/authorize?"))
+				authURL, err := url.Parse(location)
+				require.NoError(t, err)
+
+				// only the hash of the state is stored, and the code verifier never leaves
+				query := authURL.Query()
+				require.Equal(t, createdState.StateHash, token.HashOpaqueToken(query.Get("state")))
+				require.Equal(t, createdState.Nonce, query.Get("nonce"))
+				require.Equal(t, oidc.CodeChallenge(createdState.CodeVerifier), query.Get("code_challenge"))
+				require.NotContains(t, location, createdState.CodeVerifier)
+
+				// the browser is sent the hash of the state back to the callback only, and scripts can not read it
+				cookies := recorder.Result().Cookies()
+				require.Len(t, cookies, 1)
+				require.Equal(t, "oidc_state", cookies[0].Name)
+				require.Equal(t, createdState.StateHash, cookies[0].Value)
+				require.Equal(t, "/users/oidc/fake/callback", cookies[0].Path)
+				require.True(t, cookies[0].HttpOnly)
+				require.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+				require.Equal(t, int(c.OIDC.StateDuration.Seconds()), cookies[0].MaxAge)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	identityRepository := mockdb.NewIdentityMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetIdentityRepository(identityRepository)
+	app.Services.OIDCProviders = providers
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(identityRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/users/oidc/"+tc.provider, nil)
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package actions_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// TestRoutePermissions checks the role boundary of every restricted route. Allowed roles
+// are sent requests the action rejects, so that no repository call has to succeed.
+func TestRoutePermissions(t *testing.T) {
+	routes := []struct {
+		method  string
+		url     string
+		minimum user.Role
+	}{
+		{method: http.MethodPost, url: "/reservations/abc/confirm", minimum: user.RoleHost},
+		{method: http.MethodPost, url: "/reservations/abc/seat", minimum: user.RoleHost},
+		{method: http.MethodPost, url: "/reservations/abc/complete", minimum: user.RoleHost},
+		{method: http.MethodPost, url: "/reservations/abc/no-show", minimum: user.RoleHost},
+		{method: http.MethodGet, url: "/admin/tables", minimum: user.RoleManager},
+		{method: http.MethodPost, url: "/admin/tables", minimum: user.RoleManager},
+		{method: http.MethodPatch, url: "/admin/tables/abc", minimum: user.RoleManager},
+		{method: http.MethodDelete, url: "/admin/tables/abc", minimum: user.RoleManager},
+		{method: http.MethodPost, url: "/admin/tables/abc/reassign", minimum: user.RoleManager},
+		{method: http.MethodGet, url: "/admin/settings", minimum: user.RoleManager},
+		{method: http.MethodPost, url: "/admin/settings", minimum: user.RoleManager},
+		{method: http.MethodDelete, url: "/admin/settings/abc", minimum: user.RoleManager},
+		{method: http.MethodPut, url: "/admin/users/abc/role", minimum: user.RoleAdmin},
+		{method: http.MethodGet, url: "/admin/lockouts", minimum: user.RoleAdmin},
+		{method: http.MethodDelete, url: "/admin/lockouts/unknown/abc", minimum: user.RoleAdmin},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tableRepository := mockdb.NewTableMockRepository(ctrl)
+	tableRepository.EXPECT().ListTables(gomock.Any(), gomock.Any()).Return(nil, errors.New("db is down")).AnyTimes()
+	tableRepository.EXPECT().ListSettings(gomock.Any()).Return(nil, errors.New("db is down")).AnyTimes()
+
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetReservationRepository(mockdb.NewReservationMockRepository(ctrl))
+	app.SetTableRepository(tableRepository)
+	app.SetUserRepository(mockdb.NewUserMockRepository(ctrl))
+	app.RegisterRoutes()
+
+	for _, route := range routes {
+		// roles are ordered by rank, so every role from the minimum on is allowed
+		allowed := false
+		for _, role := range user.Roles() {
+			if role == route.minimum {
+				allowed = true
+			}
+
+			t.Run(fmt.Sprintf("%s %s as %s", route.method, route.url, role), func(t *testing.T) {
+				recorder := httptest.NewRecorder()
+				request := httptest.NewRequest(route.method, route.url, nil)
+				token, err := tokenManager.GenerateToken(1, role, c.App.TokenDuration)
+				require.NoError(t, err)
+				request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+				app.Router.ServeHTTP(recorder, request)
+				if allowed {
+					require.NotEqual(t, http.StatusForbidden, recorder.Code)
+				} else {
+					require.Equal(t, http.StatusForbidden, recorder.Code)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+)
+
+// ReassignTableResponse represents the response body for reassigning the reservations of a table
+type ReassignTableResponse struct {
+	Reservations []ReservationResponse `json:"reservations"`
+}
+
+// ReassignTableAction is a function that handles moving the future reservations of a table to other tables,
+// so the table can be shrunk or retired
+func ReassignTableAction(tableRepository table.Repository, reservationRepository reservation.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tableID, ok := parseTableID(ctx)
+		if !ok {
+			return
+		}
+
+		t, err := tableRepository.FindTableByID(ctx, tableID)
+		if err != nil {
+			respondTableError(ctx, err)
+			return
+		}
+
+		reservations, err := reservationRepository.ReassignTable(ctx, uint(t.ID))
+		if err != nil {
+			if errors.Is(err, reservation.ErrNoTablesAreAvailable) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		res := ReassignTableResponse{Reservations: make([]ReservationResponse, 0, len(reservations))}
+		for _, resv := range reservations {
+			res.Reservations = append(res.Reservations, newReservationResponse(resv))
+		}
+		ctx.JSON(http.StatusOK, res)
+	}
+}
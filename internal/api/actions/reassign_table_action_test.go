@@ -0,0 +1,125 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReassignTableAction(t *testing.T) {
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	staff := user.User{ID: 2, Role: user.RoleManager}
+	testCases := []struct {
+		name          string
+		url           string
+		authUser      user.User
+		buildStubs    func(tableRepository *mockdb.TableMockRepository, repository *mockdb.ReservationMockRepository, authUser user.User)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "guest is forbidden",
+			url:      "/admin/tables/1/reassign",
+			authUser: guest,
+			buildStubs: func(tableRepository *mockdb.TableMockRepository, repository *mockdb.ReservationMockRepository, authUser user.User) {
+				repository.EXPECT().ReassignTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "table not found",
+			url:      "/admin/tables/1/reassign",
+			authUser: staff,
+			buildStubs: func(tableRepository *mockdb.TableMockRepository, repository *mockdb.ReservationMockRepository, authUser user.User) {
+				tableRepository.EXPECT().FindTableByID(gomock.Any(), 1).Return(nil, table.ErrTableNotFound)
+				repository.EXPECT().ReassignTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "no other tables are available",
+			url:      "/admin/tables/1/reassign",
+			authUser: staff,
+			buildStubs: func(tableRepository *mockdb.TableMockRepository, repository *mockdb.ReservationMockRepository, authUser user.User) {
+				tableRepository.EXPECT().FindTableByID(gomock.Any(), 1).Return(&table.Table{ID: 1, SeatsCount: 4}, nil)
+				repository.EXPECT().ReassignTable(gomock.Any(), uint(1)).Return(nil, reservation.ErrNoTablesAreAvailable)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "ok",
+			url:      "/admin/tables/1/reassign",
+			authUser: staff,
+			buildStubs: func(tableRepository *mockdb.TableMockRepository, repository *mockdb.ReservationMockRepository, authUser user.User) {
+				tableRepository.EXPECT().FindTableByID(gomock.Any(), 1).Return(&table.Table{ID: 1, SeatsCount: 4}, nil)
+				repository.EXPECT().
+					ReassignTable(gomock.Any(), uint(1)).
+					Return([]reservation.Reservation{{
+						ID:         7,
+						TableID:    3,
+						SeatsCount: 4,
+						Status:     reservation.StatusConfirmed,
+						Tables:     []reservation.ReservationTable{{ReservationID: 7, TableID: 3, SeatsCount: 4}},
+					}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.ReassignTableResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Len(t, resp.Reservations, 1)
+				require.Equal(t, 7, resp.Reservations[0].ID)
+				require.Equal(t, []int{3}, resp.Reservations[0].TableIDs)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tableRepository := mockdb.NewTableMockRepository(ctrl)
+	repository := mockdb.NewReservationMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetTableRepository(tableRepository)
+	app.SetReservationRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(tableRepository, repository, tc.authUser)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, tc.url, nil)
+			token, err := tokenManager.GenerateToken(tc.authUser.ID, tc.authUser.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
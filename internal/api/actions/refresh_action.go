@@ -0,0 +1,82 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// RefreshRequest represents the request body for exchanging a refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RefreshAction is a function that handles exchanging a refresh token for a new access token and refresh token,
+// presenting a refresh token that was exchanged before revokes the whole session since it must have leaked
+func RefreshAction(userRepo user.Repository, sessionRepo session.Repository, tokenManager token.Manager, revocations token.RevocationList, tokenDuration, refreshTokenDuration time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody RefreshRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		refreshTokenHash := token.HashOpaqueToken(requestBody.RefreshToken)
+		s, err := sessionRepo.FindByRefreshToken(ctx, refreshTokenHash)
+		if err != nil {
+			if errors.Is(err, session.ErrSessionNotFound) {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !s.IsActive(time.Now()) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+			return
+		}
+
+		// the user and whoever stole the token can not be told apart, so neither keeps the session
+		if s.RefreshTokenHash != refreshTokenHash {
+			if err := revokeSession(ctx, sessionRepo, revocations, s); err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token was already used, the session is revoked"})
+			return
+		}
+
+		// the role is read again so that role changes apply from the next refresh
+		u, err := userRepo.FindByID(ctx, s.UserID)
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		s.IPAddress = ctx.ClientIP()
+		tokens, err := issueSessionTokens(tokenManager, s, u, tokenDuration, refreshTokenDuration, func(s *session.Session) error {
+			return sessionRepo.RotateRefreshToken(ctx, s)
+		})
+		if err != nil {
+			// a concurrent request exchanged the refresh token first
+			if errors.Is(err, session.ErrSessionNotFound) {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, tokens)
+	}
+}
@@ -0,0 +1,229 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRefreshAction(t *testing.T) {
+	refreshToken, refreshTokenHash, err := token.NewOpaqueToken()
+	require.NoError(t, err)
+
+	u := user.User{ID: 1, Username: "guest1", Role: user.RoleHost}
+	loggedInAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	newSession := func() *session.Session {
+		return &session.Session{
+			ID:                   1,
+			UserID:               u.ID,
+			RefreshTokenHash:     refreshTokenHash,
+			AccessTokenID:        "access-token-id",
+			AccessTokenExpiresAt: time.Now().Add(time.Minute),
+			ExpiresAt:            time.Now().Add(time.Hour),
+			CreatedAt:            loggedInAt,
+		}
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.SetSessionRepository(sessionRepository)
+	app.RegisterRoutes()
+
+	testCases := []struct {
+		name          string
+		requestBody   string
+		buildStubs    func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "without refresh token",
+			requestBody: `{}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "unknown refresh token",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				sessionRepository.EXPECT().
+					FindByRefreshToken(gomock.Any(), refreshTokenHash).
+					Return(nil, session.ErrSessionNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:        "expired session",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				s := newSession()
+				s.ExpiresAt = time.Now().Add(-time.Minute)
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), refreshTokenHash).Return(s, nil)
+				sessionRepository.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:        "revoked session",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				s := newSession()
+				revokedAt := time.Now()
+				s.RevokedAt = &revokedAt
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), refreshTokenHash).Return(s, nil)
+				sessionRepository.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:        "refresh token used twice",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				s := newSession()
+				s.PreviousRefreshTokenHash = refreshTokenHash
+				s.RefreshTokenHash = token.HashOpaqueToken("the token it was exchanged for")
+				s.AccessTokenID = "reused-access-token-id"
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), refreshTokenHash).Return(s, nil)
+				sessionRepository.EXPECT().RevokeSession(gomock.Any(), s.ID).Return(nil)
+				sessionRepository.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+				// the access token issued along with the latest refresh token is revoked as well
+				revoked, err := app.Services.RevocationList.IsRevoked(context.Background(), "reused-access-token-id")
+				require.NoError(t, err)
+				require.True(t, revoked)
+			},
+		},
+		{
+			// any refresh token exchanged before is a replay, not only the one exchanged last
+			name:        "older refresh token replayed",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				s := newSession()
+				s.PreviousRefreshTokenHash = token.HashOpaqueToken("the token it was exchanged for")
+				s.RefreshTokenHash = token.HashOpaqueToken("the token that was exchanged for")
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), refreshTokenHash).Return(s, nil)
+				sessionRepository.EXPECT().RevokeSession(gomock.Any(), s.ID).Return(nil)
+				sessionRepository.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:        "user deleted",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), refreshTokenHash).Return(newSession(), nil)
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(nil, user.ErrUserNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:        "exchanged by a concurrent request",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), refreshTokenHash).Return(newSession(), nil)
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				sessionRepository.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any()).Return(session.ErrSessionNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:        "internal error",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				sessionRepository.EXPECT().
+					FindByRefreshToken(gomock.Any(), refreshTokenHash).
+					Return(nil, errors.New("db is down"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			requestBody: fmt.Sprintf(`{"refresh_token": %q}`, refreshToken),
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				sessionRepository.EXPECT().FindByRefreshToken(gomock.Any(), refreshTokenHash).Return(newSession(), nil)
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				sessionRepository.EXPECT().
+					RotateRefreshToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, s *session.Session) error {
+						require.Equal(t, refreshTokenHash, s.PreviousRefreshTokenHash)
+						require.NotEqual(t, refreshTokenHash, s.RefreshTokenHash)
+						require.NotEqual(t, "access-token-id", s.AccessTokenID)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.TokensResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+				require.NotEmpty(t, resp.RefreshToken)
+				require.NotEqual(t, refreshToken, resp.RefreshToken)
+
+				// the access token carries the current role of the user
+				payload, err := tokenManager.VerifyToken(resp.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, u.ID, payload.UserID)
+				require.Equal(t, user.RoleHost, payload.Role)
+				// refreshing is not logging in again
+				require.True(t, loggedInAt.Equal(payload.AuthTime.Time))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository, sessionRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/refresh", bytes.NewBufferString(tc.requestBody))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
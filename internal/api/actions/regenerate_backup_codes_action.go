@@ -0,0 +1,66 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+)
+
+// RegenerateBackupCodesRequest represents the request body for replacing the backup codes of the user
+type RegenerateBackupCodesRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RegenerateBackupCodesAction is a function that handles replacing every backup code of the user of the request,
+// confirmed with a code of the authenticator app or one of the old backup codes
+func RegenerateBackupCodesAction(userRepo user.Repository, twoFactorRepo twofactor.Repository, guard *lockout.Guard) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody RegenerateBackupCodesRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		u, err := userRepo.FindByID(ctx, ctx.MustGet(middlewares.AuthUserIDKey).(int))
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		tf, err := findEnabledTwoFactor(ctx, twoFactorRepo, u.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if tf == nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": twofactor.ErrTwoFactorNotFound.Error()})
+			return
+		}
+
+		if !confirmTwoFactorCode(ctx, guard, twoFactorRepo, u, tf, requestBody.Code) {
+			return
+		}
+
+		codes, hashes, err := newBackupCodes()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := twoFactorRepo.ReplaceBackupCodes(ctx, u.ID, hashes); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, BackupCodesResponse{BackupCodes: codes})
+	}
+}
@@ -0,0 +1,193 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/totp"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegenerateBackupCodesAction(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	enabled := func(u user.User) *twofactor.TwoFactor {
+		return &twofactor.TwoFactor{UserID: u.ID, Secret: secret, EnabledAt: &enabledAt}
+	}
+	currentCode := func(t *testing.T) string {
+		code, err := totp.Code(secret, totp.Step(time.Now()))
+		require.NoError(t, err)
+		return code
+	}
+
+	// every case has a user of its own, as failed codes count towards the lockout of the account
+	pending := user.User{ID: 1, Username: "manager1", Role: user.RoleManager}
+	mistaken := user.User{ID: 2, Username: "manager2", Role: user.RoleManager}
+	replaying := user.User{ID: 3, Username: "manager3", Role: user.RoleManager}
+	regenerating := user.User{ID: 4, Username: "manager4", Role: user.RoleManager}
+	var replacedHashes []string
+
+	testCases := []struct {
+		name          string
+		user          user.User
+		code          func(t *testing.T) string
+		buildStubs    func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "without code",
+			user: pending,
+			code: func(t *testing.T) string { return "" },
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				twoFactorRepository.EXPECT().ReplaceBackupCodes(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			// a secret that was not confirmed yet has no backup codes to replace
+			name: "two-factor authentication not enabled",
+			user: pending,
+			code: currentCode,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), pending.ID).Return(&pending, nil)
+				twoFactorRepository.EXPECT().
+					FindByUserID(gomock.Any(), pending.ID).
+					Return(&twofactor.TwoFactor{UserID: pending.ID, Secret: secret}, nil)
+				twoFactorRepository.EXPECT().ReplaceBackupCodes(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "wrong code",
+			user: mistaken,
+			code: func(t *testing.T) string { return "abcde-fghij" },
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), mistaken.ID).Return(&mistaken, nil)
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), mistaken.ID).Return(enabled(mistaken), nil)
+				twoFactorRepository.EXPECT().
+					UseBackupCode(gomock.Any(), mistaken.ID, token.HashOpaqueToken("abcdefghij")).
+					Return(twofactor.ErrInvalidCode)
+				twoFactorRepository.EXPECT().ReplaceBackupCodes(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			// the wrong code locked the account out, so even the right one has to wait
+			name: "throttled after a wrong code",
+			user: mistaken,
+			code: currentCode,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), mistaken.ID).Return(&mistaken, nil)
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), mistaken.ID).Return(enabled(mistaken), nil)
+				twoFactorRepository.EXPECT().UseStep(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				twoFactorRepository.EXPECT().ReplaceBackupCodes(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Equal(t, "3600", recorder.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name: "reused step",
+			user: replaying,
+			code: currentCode,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), replaying.ID).Return(&replaying, nil)
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), replaying.ID).Return(enabled(replaying), nil)
+				twoFactorRepository.EXPECT().UseStep(gomock.Any(), replaying.ID, gomock.Any()).Return(twofactor.ErrCodeAlreadyUsed)
+				twoFactorRepository.EXPECT().ReplaceBackupCodes(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ok",
+			user: regenerating,
+			code: currentCode,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), regenerating.ID).Return(&regenerating, nil)
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), regenerating.ID).Return(enabled(regenerating), nil)
+				twoFactorRepository.EXPECT().UseStep(gomock.Any(), regenerating.ID, totp.Step(time.Now())).Return(nil)
+				twoFactorRepository.EXPECT().
+					ReplaceBackupCodes(gomock.Any(), regenerating.ID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int, codeHashes []string) error {
+						replacedHashes = codeHashes
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				// the codes the user is given are the ones that replace the old codes
+				var resp actions.BackupCodesResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+				require.Len(t, resp.BackupCodes, 10)
+				require.Len(t, replacedHashes, len(resp.BackupCodes))
+				for i, code := range resp.BackupCodes {
+					require.Equal(t, token.HashOpaqueToken(strings.ReplaceAll(code, "-", "")), replacedHashes[i])
+				}
+			},
+		},
+	}
+
+	cfg := *c
+	cfg.LoginThrottling.Account.FreeFailures = 0
+	cfg.LoginThrottling.Account.MaxFailures = 1
+	cfg.LoginThrottling.Account.LockoutDuration = time.Hour
+
+	tokenManager, err := token.NewJWTManger(cfg.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	twoFactorRepository := mockdb.NewTwoFactorMockRepository(ctrl)
+	app, err := application.New(&cfg)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.SetTwoFactorRepository(twoFactorRepository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository, twoFactorRepository)
+
+			jsonData, err := json.Marshal(actions.RegenerateBackupCodesRequest{Code: tc.code(t)})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/me/2fa/backup-codes", bytes.NewReader(jsonData))
+			accessToken, err := tokenManager.GenerateToken(tc.user.ID, tc.user.Role, cfg.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+
+}
@@ -2,26 +2,41 @@ package actions
 
 import (
 	"errors"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
-	"github.com/mohammad19khodaei/restaurant_reservation/internal/utils"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/notification"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
 )
 
 // RegisterUserRequest is the request body for the register user action
 type RegisterUserRequest struct {
 	Username string `json:"username" binding:"required,min=5"`
-	Password string `json:"password" binding:"required,min=8"`
+	Password string `json:"password" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
 }
 
 type UserResponse struct {
-	ID       int    `json:"id"`
-	Username string `json:"username"`
+	ID       int       `json:"id"`
+	Username string    `json:"username"`
+	Role     user.Role `json:"role"`
 }
 
-// RegisterUserAction is the action for registering a user
-func RegisterUserAction(userRepo user.Repository) gin.HandlerFunc {
+func newUserResponse(u user.User) UserResponse {
+	return UserResponse{
+		ID:       u.ID,
+		Username: u.Username,
+		Role:     u.Role,
+	}
+}
+
+// RegisterUserAction is the action for registering a user, the password has to comply with the password policy. The
+// user is sent a token to verify their email with through the notifier.
+func RegisterUserAction(userRepo user.Repository, hasher *password.Hasher, policy password.Policy, notifier notification.Notifier, verifyTokenDuration time.Duration, verifyURL string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var requestBody RegisterUserRequest
 		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
@@ -29,7 +44,12 @@ func RegisterUserAction(userRepo user.Repository) gin.HandlerFunc {
 			return
 		}
 
-		hashedPassword, err := utils.HashPassword(requestBody.Password)
+		if err := policy.Validate(requestBody.Password); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		hashedPassword, err := hasher.Hash(requestBody.Password)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not hash the provided password"})
 			return
@@ -38,10 +58,12 @@ func RegisterUserAction(userRepo user.Repository) gin.HandlerFunc {
 		u := &user.User{
 			Username: requestBody.Username,
 			Password: hashedPassword,
+			Role:     user.RoleGuest,
+			Email:    strings.ToLower(requestBody.Email),
 		}
 		err = userRepo.Register(ctx, u)
 		if err != nil {
-			if errors.Is(err, user.ErrUsernameAlreadyExists) {
+			if errors.Is(err, user.ErrUsernameAlreadyExists) || errors.Is(err, user.ErrEmailAlreadyExists) {
 				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 				return
 			}
@@ -49,9 +71,11 @@ func RegisterUserAction(userRepo user.Repository) gin.HandlerFunc {
 			return
 		}
 
-		ctx.JSON(http.StatusCreated, UserResponse{
-			ID:       u.ID,
-			Username: u.Username,
-		})
+		// the user is registered either way, and can ask for another verification email
+		if err := sendEmailVerification(ctx, userRepo, notifier, *u, verifyTokenDuration, verifyURL); err != nil {
+			log.Printf("could not send the email verification of user %d: %v", u.ID, err)
+		}
+
+		ctx.JSON(http.StatusCreated, newUserResponse(*u))
 	}
 }
@@ -2,6 +2,7 @@ package actions_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +11,8 @@ import (
 	"github.com/bxcodec/faker/v3"
 	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -17,6 +20,7 @@ import (
 type registerRequestBody struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Email    string `json:"email"`
 }
 
 func TestRegisterUserAction(t *testing.T) {
@@ -63,16 +67,109 @@ func TestRegisterUserAction(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "short password",
+			requestBody: registerRequestBody{
+				Username: faker.Username(),
+				Password: "short",
+				Email:    faker.Email(),
+			},
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().Register(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody registerRequestBody) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "breached password",
+			requestBody: registerRequestBody{
+				Username: faker.Username(),
+				Password: "password123",
+				Email:    faker.Email(),
+			},
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().Register(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody registerRequestBody) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), password.ErrBreached.Error())
+			},
+		},
+		{
+			name: "without email",
+			requestBody: registerRequestBody{
+				Username: faker.Username(),
+				Password: faker.Password(),
+			},
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().Register(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody registerRequestBody) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "invalid email",
+			requestBody: registerRequestBody{
+				Username: faker.Username(),
+				Password: faker.Password(),
+				Email:    "not an email",
+			},
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().Register(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody registerRequestBody) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "email of another account",
+			requestBody: registerRequestBody{
+				Username: faker.Username(),
+				Password: faker.Password(),
+				Email:    faker.Email(),
+			},
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().Register(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user.ErrEmailAlreadyExists)
+				repository.EXPECT().CreateEmailVerification(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody registerRequestBody) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
 		{
 			name: "ok",
 			requestBody: registerRequestBody{
 				Username: faker.Username(),
 				Password: faker.Password(),
+				Email:    "Guest1@Example.com",
 			},
 			buildStubs: func(repository *mockdb.UserMockRepository) {
 				repository.EXPECT().Register(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(nil)
+					DoAndReturn(func(_ context.Context, u *user.User) error {
+						require.Equal(t, "guest1@example.com", u.Email)
+						require.False(t, u.IsEmailVerified())
+						u.ID = 1
+						return nil
+					})
+				// the new user is sent a verification email
+				repository.EXPECT().CreateEmailVerification(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, verification *user.EmailVerification) error {
+						require.Equal(t, 1, verification.UserID)
+						require.Equal(t, "guest1@example.com", verification.Email)
+						require.NotEmpty(t, verification.TokenHash)
+						return nil
+					})
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, requestBody registerRequestBody) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/notification"
+)
+
+// ResendEmailVerificationAction is a function that handles sending the user of the request another token to verify
+// their email with, as often as the resend policy allows
+func ResendEmailVerificationAction(userRepo user.Repository, notifier notification.Notifier, tokenDuration time.Duration, verifyURL string, policy user.ResendPolicy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		u, err := userRepo.FindByID(ctx, ctx.MustGet(middlewares.AuthUserIDKey).(int))
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if u.Email == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": user.ErrNoEmail.Error()})
+			return
+		}
+		if u.IsEmailVerified() {
+			ctx.JSON(http.StatusConflict, gin.H{"error": user.ErrEmailAlreadyVerified.Error()})
+			return
+		}
+
+		retryAfter, err := emailVerificationRetryAfter(ctx, userRepo, u.ID, policy)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if retryAfter > 0 {
+			respondTooManyEmailVerifications(ctx, retryAfter)
+			return
+		}
+
+		if err := sendEmailVerification(ctx, userRepo, notifier, *u, tokenDuration, verifyURL); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Verification email sent successfully"})
+	}
+}
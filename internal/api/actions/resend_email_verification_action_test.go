@@ -0,0 +1,150 @@
+package actions_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResendEmailVerificationAction(t *testing.T) {
+	verifiedAt := time.Now()
+	unverified := user.User{ID: 1, Username: "guest1", Email: "guest1@example.com"}
+	notifier := newRecordingNotifier()
+	var createdVerification *user.EmailVerification
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(userRepository *mockdb.UserMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "without email",
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), 1).Return(&user.User{ID: 1, Username: "guest1"}, nil)
+				userRepository.EXPECT().CreateEmailVerification(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "already verified",
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				verified := unverified
+				verified.EmailVerifiedAt = &verifiedAt
+				userRepository.EXPECT().FindByID(gomock.Any(), 1).Return(&verified, nil)
+				userRepository.EXPECT().CreateEmailVerification(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "sent moments ago",
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				u := unverified
+				userRepository.EXPECT().FindByID(gomock.Any(), 1).Return(&u, nil)
+				userRepository.EXPECT().
+					ListEmailVerificationsSince(gomock.Any(), 1, gomock.Any()).
+					Return([]user.EmailVerification{{UserID: 1, CreatedAt: time.Now().Add(-10 * time.Second)}}, nil)
+				userRepository.EXPECT().CreateEmailVerification(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+				retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+				require.NoError(t, err)
+				require.Positive(t, retryAfter)
+				require.LessOrEqual(t, retryAfter, int(c.EmailVerification.ResendInterval.Seconds()))
+			},
+		},
+		{
+			name: "limit reached",
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				u := unverified
+				sent := make([]user.EmailVerification, c.EmailVerification.ResendLimit)
+				for i := range sent {
+					sent[i] = user.EmailVerification{UserID: 1, CreatedAt: time.Now().Add(-time.Duration(i+1) * time.Hour)}
+				}
+				userRepository.EXPECT().FindByID(gomock.Any(), 1).Return(&u, nil)
+				userRepository.EXPECT().ListEmailVerificationsSince(gomock.Any(), 1, gomock.Any()).Return(sent, nil)
+				userRepository.EXPECT().CreateEmailVerification(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.NotEmpty(t, recorder.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name: "ok",
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				u := unverified
+				userRepository.EXPECT().FindByID(gomock.Any(), 1).Return(&u, nil)
+				userRepository.EXPECT().
+					ListEmailVerificationsSince(gomock.Any(), 1, gomock.Any()).
+					Return([]user.EmailVerification{{UserID: 1, CreatedAt: time.Now().Add(-time.Hour)}}, nil)
+				userRepository.EXPECT().
+					CreateEmailVerification(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, verification *user.EmailVerification) error {
+						require.Equal(t, 1, verification.UserID)
+						require.Equal(t, "guest1@example.com", verification.Email)
+						require.WithinDuration(t, time.Now().Add(c.EmailVerification.TokenDuration), verification.ExpiresAt, time.Minute)
+						createdVerification = verification
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Len(t, notifier.messages[1], 1)
+
+				// only the hash of the token is stored, the token itself is sent in the link
+				body := notifier.messages[1][0].Body
+				link, err := url.Parse(strings.Fields(body[strings.Index(body, c.EmailVerification.URL):])[0])
+				require.NoError(t, err)
+				require.Equal(t, createdVerification.TokenHash, token.HashOpaqueToken(link.Query().Get("token")))
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.Services.Notifier = notifier
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/me/email/verification", nil)
+			token, err := tokenManager.GenerateToken(1, user.RoleGuest, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
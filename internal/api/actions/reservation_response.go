@@ -0,0 +1,70 @@
+package actions
+
+import (
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+// ReservationResponse represents a reservation in response bodies
+type ReservationResponse struct {
+	ID              int                     `json:"id"`
+	TableID         int                     `json:"table_id"`
+	TableIDs        []int                   `json:"table_ids"`
+	SeatsCount      int                     `json:"seats_count"`
+	Price           money.Money             `json:"price"`
+	PriceBreakdown  []reservation.PriceLine `json:"price_breakdown"`
+	Totals          TotalsResponse          `json:"totals"`
+	StartAt         time.Time               `json:"start_at"`
+	EndAt           time.Time               `json:"end_at"`
+	DurationMinutes int                     `json:"duration_minutes"`
+	Status          reservation.Status      `json:"status"`
+}
+
+func newReservationResponse(resv reservation.Reservation) ReservationResponse {
+	return ReservationResponse{
+		ID:              resv.ID,
+		TableID:         int(resv.TableID),
+		TableIDs:        toInts(resv.TableIDs()),
+		SeatsCount:      resv.SeatsCount,
+		Price:           resv.Price,
+		PriceBreakdown:  resv.PriceBreakdown,
+		Totals:          newTotalsResponse(resv.Totals),
+		StartAt:         resv.StartAt,
+		EndAt:           resv.EndAt,
+		DurationMinutes: int(resv.Duration().Minutes()),
+		Status:          resv.Status,
+	}
+}
+
+// TotalsResponse represents the itemized price of a reservation in response bodies
+type TotalsResponse struct {
+	Net               money.Money `json:"net"`
+	ServiceCharge     money.Money `json:"service_charge"`
+	Tax               money.Money `json:"tax"`
+	Gross             money.Money `json:"gross"`
+	TaxRate           float64     `json:"tax_rate"`
+	TaxInclusive      bool        `json:"tax_inclusive"`
+	ServiceChargeRate float64     `json:"service_charge_rate"`
+}
+
+func newTotalsResponse(totals reservation.Totals) TotalsResponse {
+	return TotalsResponse{
+		Net:               totals.Net,
+		ServiceCharge:     totals.ServiceCharge,
+		Tax:               totals.Tax,
+		Gross:             totals.Gross,
+		TaxRate:           totals.TaxRate,
+		TaxInclusive:      totals.TaxInclusive,
+		ServiceChargeRate: totals.ServiceChargeRate,
+	}
+}
+
+func toInts(ids []uint) []int {
+	ints := make([]int, 0, len(ids))
+	for _, id := range ids {
+		ints = append(ints, int(id))
+	}
+	return ints
+}
@@ -0,0 +1,65 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// ResetPasswordRequest represents the request body for choosing a new password with a password reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// ResetPasswordAction is a function that handles choosing a new password with a password reset token, every
+// session of the user is revoked since whoever knew the old password may have started one
+func ResetPasswordAction(userRepo user.Repository, sessionRepo session.Repository, revocations token.RevocationList, guard *lockout.Guard, hasher *password.Hasher, policy password.Policy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody ResetPasswordRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := policy.Validate(requestBody.Password); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		hashedPassword, err := hasher.Hash(requestBody.Password)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		u, err := userRepo.ResetPassword(ctx, token.HashOpaqueToken(requestBody.Token), hashedPassword)
+		if err != nil {
+			if errors.Is(err, user.ErrInvalidResetToken) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := revokeUserSessions(ctx, sessionRepo, revocations, u.ID); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// the account is not kept locked out for the failures with its old password
+		if err := guard.RecordSuccess(ctx, u.Username); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+	}
+}
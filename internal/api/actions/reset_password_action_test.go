@@ -0,0 +1,107 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResetPasswordAction(t *testing.T) {
+	u := user.User{ID: 1, Username: "guest1"}
+	resetToken, resetTokenHash, err := token.NewOpaqueToken()
+	require.NoError(t, err)
+	sessions := []session.Session{
+		{ID: 1, UserID: u.ID, AccessTokenID: "access-1", AccessTokenExpiresAt: time.Now().Add(time.Minute)},
+		{ID: 2, UserID: u.ID, AccessTokenID: "access-2", AccessTokenExpiresAt: time.Now().Add(time.Minute)},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.SetSessionRepository(sessionRepository)
+	app.RegisterRoutes()
+
+	testCases := []struct {
+		name          string
+		requestBody   string
+		buildStubs    func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "short password",
+			requestBody: `{"token": "` + resetToken + `", "password": "short"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().ResetPassword(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "invalid token",
+			requestBody: `{"token": "used-or-expired", "password": "new password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().
+					ResetPassword(gomock.Any(), token.HashOpaqueToken("used-or-expired"), gomock.Any()).
+					Return(nil, user.ErrInvalidResetToken)
+				sessionRepository.EXPECT().RevokeUserSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			requestBody: `{"token": "` + resetToken + `", "password": "new password"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository) {
+				userRepository.EXPECT().
+					ResetPassword(gomock.Any(), resetTokenHash, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, password string) (*user.User, error) {
+						valid, needsRehash := app.Services.PasswordHasher.Verify(password, "new password")
+						require.True(t, valid)
+						require.False(t, needsRehash)
+						return &u, nil
+					})
+				sessionRepository.EXPECT().RevokeUserSessions(gomock.Any(), u.ID).Return(sessions, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				// the access tokens of the revoked sessions stop working right away
+				for _, s := range sessions {
+					revoked, err := app.Services.RevocationList.IsRevoked(context.Background(), s.AccessTokenID)
+					require.NoError(t, err)
+					require.True(t, revoked)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository, sessionRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/password/reset", bytes.NewBufferString(tc.requestBody))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package actions
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+)
+
+// RetireTableAction is a function that handles taking a table out of service, the table is kept for the history
+// of its reservations but is never booked again
+func RetireTableAction(repository table.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tableID, ok := parseTableID(ctx)
+		if !ok {
+			return
+		}
+
+		t, err := repository.RetireTable(ctx, tableID)
+		if err != nil {
+			respondTableError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, newTableResponse(*t))
+	}
+}
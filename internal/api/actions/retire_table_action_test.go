@@ -0,0 +1,137 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRetireTableAction(t *testing.T) {
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	staff := user.User{ID: 2, Role: user.RoleManager}
+	retiredAt := time.Now().UTC()
+	testCases := []struct {
+		name          string
+		url           string
+		authUser      user.User
+		buildStubs    func(repository *mockdb.TableMockRepository, authUser user.User)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "guest is forbidden",
+			url:      "/admin/tables/1",
+			authUser: guest,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().RetireTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "invalid table id",
+			url:      "/admin/tables/abc",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().RetireTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "table not found",
+			url:      "/admin/tables/1",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().RetireTable(gomock.Any(), 1).Return(nil, table.ErrTableNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "table with future reservations",
+			url:      "/admin/tables/1",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().RetireTable(gomock.Any(), 1).Return(nil, table.ErrTableHasFutureReservations)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "table with a seated party",
+			url:      "/admin/tables/1",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().RetireTable(gomock.Any(), 1).Return(nil, table.ErrTableHasSeatedParty)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "ok",
+			url:      "/admin/tables/1",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().
+					RetireTable(gomock.Any(), 1).
+					Return(&table.Table{ID: 1, SeatsCount: 4, RetiredAt: &retiredAt}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.TableResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, 1, resp.ID)
+				require.NotNil(t, resp.RetiredAt)
+				require.True(t, retiredAt.Equal(*resp.RetiredAt))
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewTableMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetTableRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository, tc.authUser)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodDelete, tc.url, nil)
+			token, err := tokenManager.GenerateToken(tc.authUser.ID, tc.authUser.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
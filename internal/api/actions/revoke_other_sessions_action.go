@@ -0,0 +1,25 @@
+package actions
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// RevokeOtherSessionsAction is a function that handles revoking every session of the user of the request but the
+// one of the request, logging the user out on every other device
+func RevokeOtherSessionsAction(sessionRepo session.Repository, revocations token.RevocationList) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(middlewares.AuthPayloadKey).(*token.Payload)
+
+		if err := revokeOtherUserSessions(ctx, sessionRepo, revocations, payload.UserID, payload.SessionID); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Other sessions revoked successfully"})
+	}
+}
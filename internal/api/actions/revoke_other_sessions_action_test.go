@@ -0,0 +1,56 @@
+package actions_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRevokeOtherSessionsAction(t *testing.T) {
+	u := user.User{ID: 1, Role: user.RoleGuest}
+	current := session.Session{ID: 1, UserID: u.ID}
+	others := []session.Session{
+		{ID: 2, UserID: u.ID, AccessTokenID: "access-2", AccessTokenExpiresAt: time.Now().Add(time.Minute)},
+		{ID: 3, UserID: u.ID, AccessTokenID: "access-3", AccessTokenExpiresAt: time.Now().Add(time.Minute)},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewSessionMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetSessionRepository(repository)
+	app.RegisterRoutes()
+
+	repository.EXPECT().TouchSession(gomock.Any(), current.ID, u.ID, gomock.Any()).Return(nil)
+	repository.EXPECT().RevokeOtherUserSessions(gomock.Any(), u.ID, current.ID).Return(others, nil)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodDelete, "/users/me/sessions", nil)
+	request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, sessionAccessToken(t, tokenManager, u, current.ID)))
+
+	app.Router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	for _, s := range others {
+		revoked, err := app.Services.RevocationList.IsRevoked(context.Background(), s.AccessTokenID)
+		require.NoError(t, err)
+		require.True(t, revoked)
+	}
+}
@@ -0,0 +1,47 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// RevokeSessionAction is a function that handles revoking a session of the user of the request, e.g. of a lost
+// device, the access token issued for it last is rejected right away
+func RevokeSessionAction(sessionRepo session.Repository, revocations token.RevocationList) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		sessionID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+			return
+		}
+
+		s, err := sessionRepo.FindByID(ctx, sessionID)
+		if err != nil {
+			if errors.Is(err, session.ErrSessionNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// the session of another user is not revealed
+		if s.UserID != ctx.MustGet(middlewares.AuthUserIDKey).(int) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": session.ErrSessionNotFound.Error()})
+			return
+		}
+
+		if err := revokeSession(ctx, sessionRepo, revocations, s); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+	}
+}
@@ -0,0 +1,96 @@
+package actions_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRevokeSessionAction(t *testing.T) {
+	u := user.User{ID: 1, Role: user.RoleGuest}
+	current := session.Session{ID: 1, UserID: u.ID}
+	other := session.Session{ID: 2, UserID: u.ID, AccessTokenID: "access-2", AccessTokenExpiresAt: time.Now().Add(time.Minute)}
+	foreign := session.Session{ID: 3, UserID: 2}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewSessionMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetSessionRepository(repository)
+	app.RegisterRoutes()
+
+	testCases := []struct {
+		name          string
+		sessionID     string
+		buildStubs    func(repository *mockdb.SessionMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "invalid id",
+			sessionID: "abc",
+			buildStubs: func(repository *mockdb.SessionMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "session of another user",
+			sessionID: "3",
+			buildStubs: func(repository *mockdb.SessionMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), foreign.ID).Return(&foreign, nil)
+				repository.EXPECT().RevokeSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "ok",
+			sessionID: "2",
+			buildStubs: func(repository *mockdb.SessionMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), other.ID).Return(&other, nil)
+				repository.EXPECT().RevokeSession(gomock.Any(), other.ID).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				revoked, err := app.Services.RevocationList.IsRevoked(context.Background(), other.AccessTokenID)
+				require.NoError(t, err)
+				require.True(t, revoked)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository.EXPECT().TouchSession(gomock.Any(), current.ID, u.ID, gomock.Any()).Return(nil)
+			tc.buildStubs(repository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodDelete, "/users/me/sessions/"+tc.sessionID, nil)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, sessionAccessToken(t, tokenManager, u, current.ID)))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+)
+
+// ScheduleSettingsRequest represents the request body for changing the restaurant settings
+type ScheduleSettingsRequest struct {
+	// SeatPrice is a decimal amount in major units of the restaurant currency, e.g. "12.50"
+	SeatPrice string `json:"seat_price" binding:"required"`
+	// EffectiveFrom is when the settings take effect, now when omitted
+	EffectiveFrom string `json:"effective_from"`
+}
+
+// ScheduleSettingsAction is a function that handles adding a version of the restaurant settings, taking effect
+// now or at a scheduled time in the future. Reservations starting once it is in effect are priced by it.
+func ScheduleSettingsAction(repository table.Repository, currency string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody ScheduleSettingsRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		seatPrice, err := money.Parse(requestBody.SeatPrice, currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seat price: " + err.Error()})
+			return
+		}
+		if seatPrice.IsNegative() {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seat price, should not be negative"})
+			return
+		}
+
+		effectiveFrom := time.Now()
+		if requestBody.EffectiveFrom != "" {
+			// Validate date format (RFC 3339)
+			effectiveFrom, err = time.Parse(time.RFC3339, requestBody.EffectiveFrom)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid effective_from format, expected RFC 3339 (e.g. 2025-01-01T00:00:00Z)"})
+				return
+			}
+
+			if time.Now().After(effectiveFrom) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid effective_from, should be in the future"})
+				return
+			}
+		}
+
+		settings := table.Settings{SeatPrice: seatPrice, EffectiveFrom: effectiveFrom}
+		if err := repository.ScheduleSettings(ctx, &settings); err != nil {
+			if errors.Is(err, table.ErrSettingsAlreadyScheduled) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, newSettingsResponse(settings))
+	}
+}
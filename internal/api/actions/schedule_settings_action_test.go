@@ -0,0 +1,165 @@
+package actions_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestScheduleSettingsAction(t *testing.T) {
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	staff := user.User{ID: 2, Role: user.RoleManager}
+	effectiveFrom := time.Now().AddDate(0, 1, 0).Truncate(time.Second).UTC()
+	testCases := []struct {
+		name          string
+		requestBody   actions.ScheduleSettingsRequest
+		authUser      user.User
+		buildStubs    func(repository *mockdb.TableMockRepository, authUser user.User)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "guest is forbidden",
+			requestBody: actions.ScheduleSettingsRequest{SeatPrice: "12.50"},
+			authUser:    guest,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ScheduleSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "invalid seat price",
+			requestBody: actions.ScheduleSettingsRequest{SeatPrice: "12.505"},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ScheduleSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "negative seat price",
+			requestBody: actions.ScheduleSettingsRequest{SeatPrice: "-1"},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ScheduleSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "effective from in the past",
+			requestBody: actions.ScheduleSettingsRequest{SeatPrice: "12.50", EffectiveFrom: time.Now().AddDate(0, 0, -1).Format(time.RFC3339)},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ScheduleSettings(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "already scheduled",
+			requestBody: actions.ScheduleSettingsRequest{SeatPrice: "12.50", EffectiveFrom: effectiveFrom.Format(time.RFC3339)},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().ScheduleSettings(gomock.Any(), gomock.Any()).Return(table.ErrSettingsAlreadyScheduled)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:        "effective now",
+			requestBody: actions.ScheduleSettingsRequest{SeatPrice: "12"},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().
+					ScheduleSettings(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ interface{}, settings *table.Settings) error {
+						require.Equal(t, money.New(1200, c.Restaurant.Currency), settings.SeatPrice)
+						require.WithinDuration(t, time.Now(), settings.EffectiveFrom, time.Minute)
+						settings.ID = 3
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var resp actions.SettingsResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, 3, resp.ID)
+			},
+		},
+		{
+			name:        "scheduled",
+			requestBody: actions.ScheduleSettingsRequest{SeatPrice: "12.50", EffectiveFrom: effectiveFrom.Format(time.RFC3339)},
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().
+					ScheduleSettings(gomock.Any(), &table.Settings{SeatPrice: money.New(1250, c.Restaurant.Currency), EffectiveFrom: effectiveFrom}).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var resp actions.SettingsResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, money.New(1250, c.Restaurant.Currency), resp.SeatPrice)
+				require.True(t, effectiveFrom.Equal(resp.EffectiveFrom))
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewTableMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetTableRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository, tc.authUser)
+
+			body, err := json.Marshal(tc.requestBody)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/admin/settings", bytes.NewReader(body))
+			token, err := tokenManager.GenerateToken(tc.authUser.ID, tc.authUser.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
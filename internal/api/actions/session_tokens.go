@@ -0,0 +1,108 @@
+package actions
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// TokensResponse is a struct that represents the tokens of a session
+type TokensResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// newSession starts a session for the device of the request
+func newSession(ctx *gin.Context) session.Session {
+	return session.Session{
+		UserAgent:  ctx.Request.UserAgent(),
+		IPAddress:  ctx.ClientIP(),
+		LastSeenAt: time.Now(),
+		CreatedAt:  time.Now(),
+	}
+}
+
+// issueSessionTokens issues a new access token and refresh token of the session for the user, the refresh token
+// they replace is kept on the session so that presenting it again can be caught. The session is stored by store
+// before the access token is signed, so that the token names the session.
+func issueSessionTokens(tokenManager token.Manager, s *session.Session, u *user.User, tokenDuration, refreshTokenDuration time.Duration, store func(s *session.Session) error) (TokensResponse, error) {
+	payload, err := token.NewPayload(u.ID, u.Role, tokenDuration)
+	if err != nil {
+		return TokensResponse{}, err
+	}
+	payload.TwoFactor = s.TwoFactor
+
+	refreshToken, refreshTokenHash, err := token.NewOpaqueToken()
+	if err != nil {
+		return TokensResponse{}, err
+	}
+
+	s.UserID = u.ID
+	s.PreviousRefreshTokenHash = s.RefreshTokenHash
+	s.RefreshTokenHash = refreshTokenHash
+	s.AccessTokenID = payload.ID
+	s.AccessTokenExpiresAt = payload.ExpiresAt.Time
+	s.ExpiresAt = time.Now().Add(refreshTokenDuration)
+	if err := store(s); err != nil {
+		return TokensResponse{}, err
+	}
+
+	payload.SessionID = s.ID
+	if !s.CreatedAt.IsZero() {
+		payload.AuthTime = jwt.NewNumericDate(s.CreatedAt)
+	}
+	accessToken, err := tokenManager.SignToken(payload)
+	if err != nil {
+		return TokensResponse{}, err
+	}
+
+	return TokensResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// revokeSession revokes the session along with the latest access token issued for it
+func revokeSession(ctx context.Context, sessionRepo session.Repository, revocations token.RevocationList, s *session.Session) error {
+	if err := sessionRepo.RevokeSession(ctx, s.ID); err != nil {
+		return err
+	}
+
+	return revocations.Revoke(ctx, s.AccessTokenID, s.AccessTokenExpiresAt)
+}
+
+// revokeUserSessions revokes every session of the user along with the latest access token issued for each
+func revokeUserSessions(ctx context.Context, sessionRepo session.Repository, revocations token.RevocationList, userID int) error {
+	sessions, err := sessionRepo.RevokeUserSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		if err := revocations.Revoke(ctx, s.AccessTokenID, s.AccessTokenExpiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revokeOtherUserSessions revokes every session of the user but the given one along with the latest access token
+// issued for each
+func revokeOtherUserSessions(ctx context.Context, sessionRepo session.Repository, revocations token.RevocationList, userID, sessionID int) error {
+	sessions, err := sessionRepo.RevokeOtherUserSessions(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		if err := revocations.Revoke(ctx, s.AccessTokenID, s.AccessTokenExpiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package actions
+
+import (
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+)
+
+// SettingsResponse represents a version of the restaurant settings in response bodies
+type SettingsResponse struct {
+	ID            int         `json:"id"`
+	SeatPrice     money.Money `json:"seat_price"`
+	EffectiveFrom time.Time   `json:"effective_from"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+func newSettingsResponse(settings table.Settings) SettingsResponse {
+	return SettingsResponse{
+		ID:            settings.ID,
+		SeatPrice:     settings.SeatPrice,
+		EffectiveFrom: settings.EffectiveFrom,
+		CreatedAt:     settings.CreatedAt,
+	}
+}
@@ -0,0 +1,46 @@
+package actions
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+)
+
+// TableResponse represents a table in response bodies
+type TableResponse struct {
+	ID         int        `json:"id"`
+	SeatsCount int        `json:"seats_count"`
+	Name       string     `json:"name"`
+	Zone       string     `json:"zone"`
+	Attributes []string   `json:"attributes"`
+	RetiredAt  *time.Time `json:"retired_at"`
+}
+
+func newTableResponse(t table.Table) TableResponse {
+	attributes := []string(t.Attributes)
+	if attributes == nil {
+		attributes = []string{}
+	}
+
+	return TableResponse{
+		ID:         t.ID,
+		SeatsCount: t.SeatsCount,
+		Name:       t.Name,
+		Zone:       t.Zone,
+		Attributes: attributes,
+		RetiredAt:  t.RetiredAt,
+	}
+}
+
+// parseTableID parses the table id path parameter
+func parseTableID(ctx *gin.Context) (int, bool) {
+	tableID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid table id"})
+		return 0, false
+	}
+	return tableID, true
+}
@@ -0,0 +1,146 @@
+package actions
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/totp"
+)
+
+const (
+	// backupCodesCount is how many backup codes a user is given at a time
+	backupCodesCount = 10
+	// backupCodeLength is the number of characters of a backup code, 50 random bits
+	backupCodeLength = 10
+	// backupCodeAlphabet has 32 characters, so that every random byte picks one without bias
+	backupCodeAlphabet = "abcdefghijklmnopqrstuvwxyz234567"
+	// totpSkew is how many periods a code may be off by, for the clocks of phones that are a little off
+	totpSkew = 1
+)
+
+// TwoFactorChallengeResponse is a struct that represents the challenge a login with a correct password is given,
+// the challenge token is exchanged for the tokens of a session along with a code of the second factor
+type TwoFactorChallengeResponse struct {
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// BackupCodesResponse is a struct that represents the backup codes of a user, they are only shown once
+type BackupCodesResponse struct {
+	BackupCodes []string `json:"backup_codes"`
+}
+
+// newTwoFactorChallenge issues a challenge token for the user, which is not an access token
+func newTwoFactorChallenge(tokenManager token.Manager, u *user.User, challengeDuration time.Duration) (TwoFactorChallengeResponse, error) {
+	payload, err := token.NewPayload(u.ID, u.Role, challengeDuration)
+	if err != nil {
+		return TwoFactorChallengeResponse{}, err
+	}
+	payload.Purpose = token.PurposeTwoFactorChallenge
+
+	challengeToken, err := tokenManager.SignToken(payload)
+	if err != nil {
+		return TwoFactorChallengeResponse{}, err
+	}
+
+	return TwoFactorChallengeResponse{
+		ChallengeToken: challengeToken,
+		ExpiresAt:      payload.ExpiresAt.Time,
+	}, nil
+}
+
+// findEnabledTwoFactor returns the two-factor authentication of the user if it is enabled, nil otherwise
+func findEnabledTwoFactor(ctx context.Context, twoFactorRepo twofactor.Repository, userID int) (*twofactor.TwoFactor, error) {
+	tf, err := twoFactorRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, twofactor.ErrTwoFactorNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !tf.IsEnabled() {
+		return nil, nil
+	}
+
+	return tf, nil
+}
+
+// newBackupCodes generates a set of backup codes along with the hashes they are stored by
+func newBackupCodes() (codes []string, hashes []string, err error) {
+	for i := 0; i < backupCodesCount; i++ {
+		b := make([]byte, backupCodeLength)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		for j := range b {
+			b[j] = backupCodeAlphabet[int(b[j])%len(backupCodeAlphabet)]
+		}
+
+		code := string(b[:backupCodeLength/2]) + "-" + string(b[backupCodeLength/2:])
+		codes = append(codes, code)
+		hashes = append(hashes, hashBackupCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// hashBackupCode returns the hash a backup code is stored by, however it is typed in
+func hashBackupCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	return token.HashOpaqueToken(normalized)
+}
+
+// useTwoFactorCode uses up a code of the authenticator app, or a backup code, of the user. A code of the app is
+// used once, and neither it nor the codes before it work again.
+func useTwoFactorCode(ctx context.Context, twoFactorRepo twofactor.Repository, tf *twofactor.TwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(tf.Secret, code, time.Now(), totpSkew)
+		if !ok {
+			return twofactor.ErrInvalidCode
+		}
+		return twoFactorRepo.UseStep(ctx, tf.UserID, step)
+	}
+
+	return twoFactorRepo.UseBackupCode(ctx, tf.UserID, hashBackupCode(code))
+}
+
+// confirmTwoFactorCode checks the code the user of a request confirms a login or a change with. Wrong codes are
+// throttled like failed logins, since a code is much easier to guess than a password. Unless the code is correct
+// the response is written and false is returned.
+func confirmTwoFactorCode(ctx *gin.Context, guard *lockout.Guard, twoFactorRepo twofactor.Repository, u *user.User, tf *twofactor.TwoFactor, code string) bool {
+	clientIP := ctx.ClientIP()
+	if err := guard.Check(ctx, u.Username, clientIP); err != nil {
+		respondGuardError(ctx, err)
+		return false
+	}
+
+	err := useTwoFactorCode(ctx, twoFactorRepo, tf, code)
+	if errors.Is(err, twofactor.ErrInvalidCode) || errors.Is(err, twofactor.ErrCodeAlreadyUsed) {
+		if err := guard.RecordFailure(ctx, u.Username, clientIP); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return false
+		}
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return false
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+
+	if err := guard.RecordSuccess(ctx, u.Username); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
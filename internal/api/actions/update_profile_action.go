@@ -0,0 +1,104 @@
+package actions
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/notification"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
+)
+
+// UpdateProfileRequest represents the request body for changing the profile of the user of the request, only the
+// given fields are changed and an empty one clears its detail. The current password is only needed to change the email.
+type UpdateProfileRequest struct {
+	Name            *string `json:"name" binding:"omitempty,max=100"`
+	Email           *string `json:"email" binding:"omitempty,len=0|email"`
+	Phone           *string `json:"phone" binding:"omitempty,len=0|e164"`
+	Language        *string `json:"language" binding:"omitempty,len=0|bcp47_language_tag"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+// UpdateProfileAction is a function that handles changing the contact details of the user of the request, a new email
+// has to be verified again with the token sent to it through the notifier, as often as the resend policy allows, and is
+// only changed with the current password
+func UpdateProfileAction(userRepo user.Repository, notifier notification.Notifier, guard *lockout.Guard, hasher *password.Hasher, verifyTokenDuration time.Duration, verifyURL string, resendPolicy user.ResendPolicy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody UpdateProfileRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		u, err := userRepo.FindByID(ctx, ctx.MustGet(middlewares.AuthUserIDKey).(int))
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if requestBody.Name != nil {
+			u.Name = strings.TrimSpace(*requestBody.Name)
+		}
+		emailChanged := false
+		if requestBody.Email != nil && strings.ToLower(*requestBody.Email) != u.Email {
+			// the email receives the password reset links, so whoever holds the access token can not take the account
+			// over by changing it
+			if !confirmPassword(ctx, guard, hasher, u, requestBody.CurrentPassword) {
+				return
+			}
+			// a new email is sent a verification email, as often as the resend policy allows
+			if *requestBody.Email != "" {
+				retryAfter, err := emailVerificationRetryAfter(ctx, userRepo, u.ID, resendPolicy)
+				if err != nil {
+					ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+				if retryAfter > 0 {
+					respondTooManyEmailVerifications(ctx, retryAfter)
+					return
+				}
+			}
+
+			u.Email = strings.ToLower(*requestBody.Email)
+			u.EmailVerifiedAt = nil
+			emailChanged = true
+		}
+		if requestBody.Phone != nil {
+			u.Phone = *requestBody.Phone
+		}
+		if requestBody.Language != nil {
+			u.Language = *requestBody.Language
+		}
+
+		if err := userRepo.UpdateProfile(ctx, u); err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, user.ErrEmailAlreadyExists) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if emailChanged && u.Email != "" {
+			if err := sendEmailVerification(ctx, userRepo, notifier, *u, verifyTokenDuration, verifyURL); err != nil {
+				log.Printf("could not send the email verification of user %d: %v", u.ID, err)
+			}
+		}
+
+		ctx.JSON(http.StatusOK, newProfileResponse(*u))
+	}
+}
@@ -0,0 +1,211 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUpdateProfileAction(t *testing.T) {
+	verifiedAt := time.Now()
+	hashedPassword, err := hashPassword("the password")
+	require.NoError(t, err)
+	newUser := func() *user.User {
+		return &user.User{ID: 1, Username: "guest1", Password: hashedPassword, Role: user.RoleGuest, Name: "Guest One", Phone: "+4915112345678"}
+	}
+
+	testCases := []struct {
+		name          string
+		requestBody   string
+		buildStubs    func(repository *mockdb.UserMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "invalid email",
+			requestBody: `{"email": "not an email"}`,
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "invalid phone",
+			requestBody: `{"phone": "0151 12345678"}`,
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "invalid language",
+			requestBody: `{"language": "not a language"}`,
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "email without current password",
+			requestBody: `{"email": "guest1@example.com"}`,
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), 1).Return(newUser(), nil)
+				repository.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "email with wrong current password",
+			requestBody: `{"email": "guest1@example.com", "current_password": "wrong password"}`,
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), 1).Return(newUser(), nil)
+				repository.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Times(0)
+				repository.EXPECT().CreateEmailVerification(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "email of another account",
+			requestBody: `{"email": "taken@example.com", "current_password": "the password"}`,
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), 1).Return(newUser(), nil)
+				repository.EXPECT().ListEmailVerificationsSince(gomock.Any(), 1, gomock.Any()).Return(nil, nil)
+				repository.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Return(user.ErrEmailAlreadyExists)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			// a new email is only taken when it can be sent a verification email
+			name:        "verification email sent moments ago",
+			requestBody: `{"email": "guest1@example.com", "current_password": "the password"}`,
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), 1).Return(newUser(), nil)
+				repository.EXPECT().
+					ListEmailVerificationsSince(gomock.Any(), 1, gomock.Any()).
+					Return([]user.EmailVerification{{UserID: 1, CreatedAt: time.Now().Add(-10 * time.Second)}}, nil)
+				repository.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Times(0)
+				repository.EXPECT().CreateEmailVerification(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+				retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+				require.NoError(t, err)
+				require.Positive(t, retryAfter)
+			},
+		},
+		{
+			name:        "ok",
+			requestBody: `{"email": "Guest1@Example.com", "current_password": "the password", "language": "en-GB", "phone": ""}`,
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				repository.EXPECT().FindByID(gomock.Any(), 1).Return(newUser(), nil)
+				repository.EXPECT().ListEmailVerificationsSince(gomock.Any(), 1, gomock.Any()).Return(nil, nil)
+				repository.EXPECT().
+					UpdateProfile(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, u *user.User) error {
+						// the name is left as it was, the phone is cleared
+						require.Equal(t, "Guest One", u.Name)
+						require.Equal(t, "guest1@example.com", u.Email)
+						require.Equal(t, "", u.Phone)
+						require.Equal(t, "en-GB", u.Language)
+						require.False(t, u.IsEmailVerified())
+						return nil
+					})
+				// the new email is sent a verification email
+				repository.EXPECT().
+					CreateEmailVerification(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, verification *user.EmailVerification) error {
+						require.Equal(t, "guest1@example.com", verification.Email)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response actions.ProfileResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "guest1@example.com", response.Email)
+				require.Equal(t, "en-GB", response.Language)
+				require.False(t, response.EmailVerified)
+			},
+		},
+		{
+			// the password is not needed when the email is left as it was
+			name:        "same email",
+			requestBody: `{"email": "Verified@Example.com"}`,
+			buildStubs: func(repository *mockdb.UserMockRepository) {
+				u := newUser()
+				u.Email = "verified@example.com"
+				u.EmailVerifiedAt = &verifiedAt
+				repository.EXPECT().FindByID(gomock.Any(), 1).Return(u, nil)
+				repository.EXPECT().
+					UpdateProfile(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, u *user.User) error {
+						require.True(t, u.IsEmailVerified())
+						return nil
+					})
+				repository.EXPECT().CreateEmailVerification(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response actions.ProfileResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.True(t, response.EmailVerified)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewUserMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPatch, "/users/me", bytes.NewBufferString(tc.requestBody))
+			token, err := tokenManager.GenerateToken(1, user.RoleGuest, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+// UpdateStatusAction is a function that handles moving a reservation to the given status, it responds with the
+// reservation as stored
+func UpdateStatusAction(repository reservation.Repository, status reservation.Status) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reservationID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation id"})
+			return
+		}
+
+		resv, err := repository.UpdateStatus(ctx, reservationID, status)
+		if err != nil {
+			if errors.Is(err, reservation.ErrReservationNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if errors.Is(err, reservation.ErrInvalidStatusTransition) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, newReservationResponse(*resv))
+	}
+}
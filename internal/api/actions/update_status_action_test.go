@@ -0,0 +1,132 @@
+package actions_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUpdateStatusAction(t *testing.T) {
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	staff := user.User{ID: 2, Role: user.RoleHost}
+	startAt := time.Date(2024, 10, 18, 19, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name          string
+		url           string
+		authUser      user.User
+		buildStubs    func(repository *mockdb.ReservationMockRepository, authUser user.User)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "guest is forbidden",
+			url:      "/reservations/1/seat",
+			authUser: guest,
+			buildStubs: func(repository *mockdb.ReservationMockRepository, authUser user.User) {
+				repository.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "invalid reservation id",
+			url:      "/reservations/abc/seat",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.ReservationMockRepository, authUser user.User) {
+				repository.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "reservation not found",
+			url:      "/reservations/1/seat",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.ReservationMockRepository, authUser user.User) {
+				repository.EXPECT().
+					UpdateStatus(gomock.Any(), 1, reservation.StatusSeated).
+					Return(nil, reservation.ErrReservationNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "invalid transition",
+			url:      "/reservations/1/complete",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.ReservationMockRepository, authUser user.User) {
+				repository.EXPECT().
+					UpdateStatus(gomock.Any(), 1, reservation.StatusCompleted).
+					Return(nil, reservation.ErrInvalidStatusTransition)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "ok",
+			url:      "/reservations/1/no-show",
+			authUser: staff,
+			buildStubs: func(repository *mockdb.ReservationMockRepository, authUser user.User) {
+				repository.EXPECT().
+					UpdateStatus(gomock.Any(), 1, reservation.StatusNoShow).
+					Return(&reservation.Reservation{ID: 1, TableID: 3, SeatsCount: 2, StartAt: startAt, EndAt: startAt.Add(2 * time.Hour), Status: reservation.StatusNoShow}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.ReservationResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, 1, resp.ID)
+				require.Equal(t, reservation.StatusNoShow, resp.Status)
+				require.Equal(t, []int{3}, resp.TableIDs)
+				require.True(t, startAt.Equal(resp.StartAt))
+				require.Equal(t, 120, resp.DurationMinutes)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewReservationMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetReservationRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository, tc.authUser)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, tc.url, nil)
+			token, err := tokenManager.GenerateToken(tc.authUser.ID, tc.authUser.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+)
+
+// UpdateTableRequest represents the request body for updating a table, omitted fields keep their value
+type UpdateTableRequest struct {
+	SeatsCount *int      `json:"seats_count" binding:"omitempty,min=2,max=30"`
+	Name       *string   `json:"name" binding:"omitempty,min=1,max=100"`
+	Zone       *string   `json:"zone" binding:"omitempty,max=100"`
+	Attributes *[]string `json:"attributes" binding:"omitempty,max=20,dive,required,max=50"`
+}
+
+// UpdateTableAction is a function that handles updating the seats count, name, zone and attributes of a table
+func UpdateTableAction(repository table.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tableID, ok := parseTableID(ctx)
+		if !ok {
+			return
+		}
+
+		var requestBody UpdateTableRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if requestBody.SeatsCount != nil && *requestBody.SeatsCount%2 != 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seats count, should be even"})
+			return
+		}
+
+		t, err := repository.FindTableByID(ctx, tableID)
+		if err != nil {
+			respondTableError(ctx, err)
+			return
+		}
+
+		if requestBody.SeatsCount != nil {
+			t.SeatsCount = *requestBody.SeatsCount
+		}
+		if requestBody.Name != nil {
+			t.Name = *requestBody.Name
+		}
+		if requestBody.Zone != nil {
+			t.Zone = *requestBody.Zone
+		}
+		if requestBody.Attributes != nil {
+			t.Attributes = *requestBody.Attributes
+		}
+
+		if err := repository.UpdateTable(ctx, t); err != nil {
+			respondTableError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, newTableResponse(*t))
+	}
+}
+
+// respondTableError responds with the status code matching an error of managing a table
+func respondTableError(ctx *gin.Context, err error) {
+	if errors.Is(err, table.ErrTableNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, table.ErrTableRetired) || errors.Is(err, table.ErrTableHasFutureReservations) ||
+		errors.Is(err, table.ErrTableHasSeatedParty) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
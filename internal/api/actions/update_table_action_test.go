@@ -0,0 +1,173 @@
+package actions_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUpdateTableAction(t *testing.T) {
+	guest := user.User{ID: 1, Role: user.RoleGuest}
+	staff := user.User{ID: 2, Role: user.RoleManager}
+	testCases := []struct {
+		name          string
+		url           string
+		requestBody   string
+		authUser      user.User
+		buildStubs    func(repository *mockdb.TableMockRepository, authUser user.User)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "guest is forbidden",
+			url:         "/admin/tables/1",
+			requestBody: `{"name": "Window"}`,
+			authUser:    guest,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().UpdateTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "invalid table id",
+			url:         "/admin/tables/abc",
+			requestBody: `{"name": "Window"}`,
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().FindTableByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "odd seats count",
+			url:         "/admin/tables/1",
+			requestBody: `{"seats_count": 3}`,
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().FindTableByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "empty name",
+			url:         "/admin/tables/1",
+			requestBody: `{"name": ""}`,
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().FindTableByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "table not found",
+			url:         "/admin/tables/1",
+			requestBody: `{"name": "Window"}`,
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().FindTableByID(gomock.Any(), 1).Return(nil, table.ErrTableNotFound)
+				repository.EXPECT().UpdateTable(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "shrinking a table with future reservations",
+			url:         "/admin/tables/1",
+			requestBody: `{"seats_count": 2}`,
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().FindTableByID(gomock.Any(), 1).Return(&table.Table{ID: 1, SeatsCount: 4}, nil)
+				repository.EXPECT().
+					UpdateTable(gomock.Any(), &table.Table{ID: 1, SeatsCount: 2}).
+					Return(table.ErrTableHasFutureReservations)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:        "retired table",
+			url:         "/admin/tables/1",
+			requestBody: `{"name": "Window"}`,
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().FindTableByID(gomock.Any(), 1).Return(&table.Table{ID: 1, SeatsCount: 4}, nil)
+				repository.EXPECT().UpdateTable(gomock.Any(), gomock.Any()).Return(table.ErrTableRetired)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			url:         "/admin/tables/1",
+			requestBody: `{"seats_count": 6, "zone": "patio", "attributes": ["window", "quiet"]}`,
+			authUser:    staff,
+			buildStubs: func(repository *mockdb.TableMockRepository, authUser user.User) {
+				repository.EXPECT().
+					FindTableByID(gomock.Any(), 1).
+					Return(&table.Table{ID: 1, SeatsCount: 4, Name: "Table 1", Zone: "hall"}, nil)
+				repository.EXPECT().
+					UpdateTable(gomock.Any(), &table.Table{ID: 1, SeatsCount: 6, Name: "Table 1", Zone: "patio", Attributes: table.Attributes{"window", "quiet"}}).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.TableResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, actions.TableResponse{ID: 1, SeatsCount: 6, Name: "Table 1", Zone: "patio", Attributes: []string{"window", "quiet"}}, resp)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mockdb.NewTableMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetTableRepository(repository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(repository, tc.authUser)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPatch, tc.url, bytes.NewBufferString(tc.requestBody))
+			token, err := tokenManager.GenerateToken(tc.authUser.ID, tc.authUser.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
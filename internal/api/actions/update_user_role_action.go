@@ -0,0 +1,52 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
+
+// UpdateUserRoleRequest represents the request body for changing the role of a user
+type UpdateUserRoleRequest struct {
+	Role user.Role `json:"role" binding:"required,oneof=guest host manager admin"`
+}
+
+// UpdateUserRoleAction is a function that handles changing the role of a user, the new role applies to the tokens
+// the user is issued from then on
+func UpdateUserRoleAction(userRepo user.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+			return
+		}
+
+		var requestBody UpdateUserRoleRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// an admin demoting themselves could leave the restaurant without any admin
+		if userID == ctx.MustGet(middlewares.AuthUserIDKey).(int) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "You can not change your own role"})
+			return
+		}
+
+		u, err := userRepo.UpdateRole(ctx, userID, requestBody.Role)
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, newUserResponse(*u))
+	}
+}
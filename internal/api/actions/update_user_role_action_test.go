@@ -0,0 +1,140 @@
+package actions_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUpdateUserRoleAction(t *testing.T) {
+	manager := user.User{ID: 1, Role: user.RoleManager}
+	admin := user.User{ID: 2, Role: user.RoleAdmin}
+	testCases := []struct {
+		name          string
+		url           string
+		requestBody   string
+		authUser      user.User
+		buildStubs    func(userRepository *mockdb.UserMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "manager is forbidden",
+			url:         "/admin/users/3/role",
+			requestBody: `{"role": "host"}`,
+			authUser:    manager,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().UpdateRole(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "invalid user id",
+			url:         "/admin/users/abc/role",
+			requestBody: `{"role": "host"}`,
+			authUser:    admin,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().UpdateRole(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "unknown role",
+			url:         "/admin/users/3/role",
+			requestBody: `{"role": "owner"}`,
+			authUser:    admin,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().UpdateRole(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "own role",
+			url:         "/admin/users/2/role",
+			requestBody: `{"role": "guest"}`,
+			authUser:    admin,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().UpdateRole(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "user not found",
+			url:         "/admin/users/3/role",
+			requestBody: `{"role": "host"}`,
+			authUser:    admin,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().UpdateRole(gomock.Any(), 3, user.RoleHost).Return(nil, user.ErrUserNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			url:         "/admin/users/3/role",
+			requestBody: `{"role": "host"}`,
+			authUser:    admin,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().
+					UpdateRole(gomock.Any(), 3, user.RoleHost).
+					Return(&user.User{ID: 3, Username: "hostess", Role: user.RoleHost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.UserResponse
+				err := json.NewDecoder(recorder.Body).Decode(&resp)
+				require.NoError(t, err)
+
+				require.Equal(t, actions.UserResponse{ID: 3, Username: "hostess", Role: user.RoleHost}, resp)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPut, tc.url, bytes.NewBufferString(tc.requestBody))
+			token, err := tokenManager.GenerateToken(tc.authUser.ID, tc.authUser.Role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
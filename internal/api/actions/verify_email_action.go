@@ -0,0 +1,37 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// VerifyEmailRequest represents the request body for verifying an email with an email verification token
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// VerifyEmailAction is a function that handles verifying the email of a user with the token sent to it
+func VerifyEmailAction(userRepo user.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody VerifyEmailRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if _, err := userRepo.VerifyEmail(ctx, token.HashOpaqueToken(requestBody.Token)); err != nil {
+			if errors.Is(err, user.ErrInvalidVerifyToken) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+	}
+}
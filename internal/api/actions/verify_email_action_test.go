@@ -0,0 +1,86 @@
+package actions_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerifyEmailAction(t *testing.T) {
+	verifiedAt := time.Now()
+	verifyToken, verifyTokenHash, err := token.NewOpaqueToken()
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.RegisterRoutes()
+
+	testCases := []struct {
+		name          string
+		requestBody   string
+		buildStubs    func(userRepository *mockdb.UserMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "without token",
+			requestBody: `{}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().VerifyEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "invalid token",
+			requestBody: `{"token": "used-or-expired"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().
+					VerifyEmail(gomock.Any(), token.HashOpaqueToken("used-or-expired")).
+					Return(nil, user.ErrInvalidVerifyToken)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "ok",
+			requestBody: `{"token": "` + verifyToken + `"}`,
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				// only the hash of the token is looked up
+				userRepository.EXPECT().
+					VerifyEmail(gomock.Any(), verifyTokenHash).
+					Return(&user.User{ID: 1, Email: "guest1@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/email/verify", bytes.NewBufferString(tc.requestBody))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
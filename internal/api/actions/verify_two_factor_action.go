@@ -0,0 +1,94 @@
+package actions
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// VerifyTwoFactorRequest represents the request body for passing the two-factor challenge of a login
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// VerifyTwoFactorAction is a function that handles passing the two-factor challenge of a login with a code of the
+// authenticator app or a backup code, it starts the session the login would have without two-factor
+// authentication. A challenge token is passed once.
+func VerifyTwoFactorAction(userRepo user.Repository, sessionRepo session.Repository, twoFactorRepo twofactor.Repository, tokenManager token.Manager, revocations token.RevocationList, guard *lockout.Guard, tokenDuration, refreshTokenDuration time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var requestBody VerifyTwoFactorRequest
+		if err := ctx.ShouldBindJSON(&requestBody); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		payload, err := tokenManager.VerifyToken(requestBody.ChallengeToken)
+		if err != nil || payload.Purpose != token.PurposeTwoFactorChallenge {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+			return
+		}
+
+		revoked, err := revocations.IsRevoked(ctx, payload.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if revoked {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+			return
+		}
+
+		u, err := userRepo.FindByID(ctx, payload.UserID)
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		tf, err := findEnabledTwoFactor(ctx, twoFactorRepo, u.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if tf == nil {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+			return
+		}
+
+		if !confirmTwoFactorCode(ctx, guard, twoFactorRepo, u, tf, requestBody.Code) {
+			return
+		}
+
+		if err := revocations.Revoke(ctx, payload.ID, payload.ExpiresAt.Time); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		s := newSession(ctx)
+		s.TwoFactor = true
+		tokens, err := issueSessionTokens(tokenManager, &s, u, tokenDuration, refreshTokenDuration, func(s *session.Session) error {
+			return sessionRepo.CreateSession(ctx, s)
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, LoginResponse{
+			AccessToken:  tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			User:         newUserResponse(*u),
+		})
+	}
+}
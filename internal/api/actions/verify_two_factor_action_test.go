@@ -0,0 +1,166 @@
+package actions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/application"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/totp"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerifyTwoFactorAction(t *testing.T) {
+	u := user.User{ID: 1, Username: "manager1", Role: user.RoleManager}
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	tf := twofactor.TwoFactor{UserID: u.ID, Secret: secret, EnabledAt: &enabledAt}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+	newChallenge := func(t *testing.T) string {
+		payload, err := token.NewPayload(u.ID, u.Role, c.TwoFactor.ChallengeDuration)
+		require.NoError(t, err)
+		payload.Purpose = token.PurposeTwoFactorChallenge
+		challengeToken, err := tokenManager.SignToken(payload)
+		require.NoError(t, err)
+		return challengeToken
+	}
+	currentCode := func(t *testing.T) string {
+		code, err := totp.Code(secret, totp.Step(time.Now()))
+		require.NoError(t, err)
+		return code
+	}
+	usedChallenge := newChallenge(t)
+
+	testCases := []struct {
+		name           string
+		challengeToken func(t *testing.T) string
+		code           func(t *testing.T) string
+		buildStubs     func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository)
+		checkResponse  func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "access token instead of challenge token",
+			challengeToken: func(t *testing.T) string {
+				accessToken, err := tokenManager.GenerateToken(u.ID, u.Role, c.App.TokenDuration)
+				require.NoError(t, err)
+				return accessToken
+			},
+			code: currentCode,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:           "invalid backup code",
+			challengeToken: newChallenge,
+			code:           func(t *testing.T) string { return "abcde-fghij" },
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), u.ID).Return(&tf, nil)
+				twoFactorRepository.EXPECT().
+					UseBackupCode(gomock.Any(), u.ID, token.HashOpaqueToken("abcdefghij")).
+					Return(twofactor.ErrInvalidCode)
+				sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:           "replayed code",
+			challengeToken: newChallenge,
+			code:           currentCode,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), u.ID).Return(&tf, nil)
+				twoFactorRepository.EXPECT().UseStep(gomock.Any(), u.ID, gomock.Any()).Return(twofactor.ErrCodeAlreadyUsed)
+				sessionRepository.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:           "ok",
+			challengeToken: func(t *testing.T) string { return usedChallenge },
+			code:           currentCode,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), u.ID).Return(&u, nil)
+				twoFactorRepository.EXPECT().FindByUserID(gomock.Any(), u.ID).Return(&tf, nil)
+				twoFactorRepository.EXPECT().UseStep(gomock.Any(), u.ID, totp.Step(time.Now())).Return(nil)
+				sessionRepository.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, s *session.Session) error {
+						require.Equal(t, u.ID, s.UserID)
+						require.True(t, s.TwoFactor)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp actions.LoginResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+				payload, err := tokenManager.VerifyToken(resp.AccessToken)
+				require.NoError(t, err)
+				require.True(t, payload.IsAccessToken())
+				require.True(t, payload.TwoFactor)
+			},
+		},
+		{
+			name:           "challenge token passed before",
+			challengeToken: func(t *testing.T) string { return usedChallenge },
+			code:           currentCode,
+			buildStubs: func(userRepository *mockdb.UserMockRepository, sessionRepository *mockdb.SessionMockRepository, twoFactorRepository *mockdb.TwoFactorMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepository := mockdb.NewUserMockRepository(ctrl)
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	twoFactorRepository := mockdb.NewTwoFactorMockRepository(ctrl)
+	app, err := application.New(c)
+	require.NoError(t, err)
+	app.SetUserRepository(userRepository)
+	app.SetSessionRepository(sessionRepository)
+	app.SetTwoFactorRepository(twoFactorRepository)
+	app.RegisterRoutes()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.buildStubs(userRepository, sessionRepository, twoFactorRepository)
+
+			jsonData, err := json.Marshal(actions.VerifyTwoFactorRequest{ChallengeToken: tc.challengeToken(t), Code: tc.code(t)})
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/users/login/2fa", bytes.NewReader(jsonData))
+
+			app.Router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -1,20 +1,26 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
 )
 
 const (
 	AuthUserIDKey           = "auth_user_id"
+	AuthUserRoleKey         = "auth_user_role"
+	AuthPayloadKey          = "auth_payload"
 	AuthorizationTypeBearer = "Bearer"
 )
 
-// AuthMiddleware is a Gin middleware that is used to authenticate the request
-func AuthMiddleware(tokenManager token.Manager) gin.HandlerFunc {
+// AuthMiddleware is a Gin middleware that is used to authenticate the request, tokens on the revocation list or of a
+// session that is revoked or expired are rejected even before they expire. Every request marks the session of its
+// token as seen.
+func AuthMiddleware(tokenManager token.Manager, revocations token.RevocationList, sessionRepo session.Repository) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 
@@ -41,7 +47,61 @@ func AuthMiddleware(tokenManager token.Manager) gin.HandlerFunc {
 			})
 			return
 		}
+
+		// e.g. a two-factor challenge token does not authenticate the user until the challenge is passed
+		if !payload.IsAccessToken() {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired token",
+			})
+			return
+		}
+
+		revoked, err := revocations.IsRevoked(ctx, payload.ID)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if revoked {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired token",
+			})
+			return
+		}
+
+		// tokens issued before sessions were named in them have no session to check, they expire soon enough
+		if payload.SessionID != 0 {
+			if err := sessionRepo.TouchSession(ctx, payload.SessionID, payload.UserID, ctx.ClientIP()); err != nil {
+				if errors.Is(err, session.ErrSessionNotFound) {
+					ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+						"error": "Invalid or expired token",
+					})
+					return
+				}
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+
 		ctx.Set(AuthUserIDKey, payload.UserID)
+		ctx.Set(AuthUserRoleKey, payload.Role)
+		ctx.Set(AuthPayloadKey, payload)
 		ctx.Next()
 	}
 }
+
+// OptionalAuthMiddleware is a Gin middleware that authenticates the request like AuthMiddleware when it has an
+// Authorization header, and lets it through unauthenticated otherwise
+func OptionalAuthMiddleware(tokenManager token.Manager, revocations token.RevocationList, sessionRepo session.Repository) gin.HandlerFunc {
+	authenticate := AuthMiddleware(tokenManager, revocations, sessionRepo)
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			ctx.Next()
+			return
+		}
+		authenticate(ctx)
+	}
+}
@@ -1,21 +1,28 @@
 package middlewares_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestAuthMiddleware(t *testing.T) {
+	revocations := token.NewMemoryRevocationList()
 	testCases := []struct {
 		name          string
 		setAuthHeader func(t *testing.T, manager token.Manager, req *http.Request)
+		buildStubs    func(repository *mockdb.SessionMockRepository)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
@@ -37,7 +44,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "with invalid header",
 			setAuthHeader: func(t *testing.T, tokenManager token.Manager, req *http.Request) {
-				token, err := tokenManager.GenerateToken(1, c.App.TokenDuration)
+				token, err := tokenManager.GenerateToken(1, user.RoleGuest, c.App.TokenDuration)
 				require.NoError(t, err)
 				req.Header.Set("Authorization", fmt.Sprintf("%s %s", "unsupported", token))
 			},
@@ -48,7 +55,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "with expired token",
 			setAuthHeader: func(t *testing.T, tokenManager token.Manager, req *http.Request) {
-				token, err := tokenManager.GenerateToken(1, -c.App.TokenDuration)
+				token, err := tokenManager.GenerateToken(1, user.RoleGuest, -c.App.TokenDuration)
 				require.NoError(t, err)
 				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
 			},
@@ -56,10 +63,73 @@ func TestAuthMiddleware(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "with revoked token",
+			setAuthHeader: func(t *testing.T, tokenManager token.Manager, req *http.Request) {
+				payload, err := token.NewPayload(1, user.RoleGuest, c.App.TokenDuration)
+				require.NoError(t, err)
+				token, err := tokenManager.SignToken(payload)
+				require.NoError(t, err)
+				require.NoError(t, revocations.Revoke(context.Background(), payload.ID, payload.ExpiresAt.Time))
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "with two-factor challenge token",
+			setAuthHeader: func(t *testing.T, tokenManager token.Manager, req *http.Request) {
+				payload, err := token.NewPayload(1, user.RoleGuest, c.App.TokenDuration)
+				require.NoError(t, err)
+				payload.Purpose = token.PurposeTwoFactorChallenge
+				token, err := tokenManager.SignToken(payload)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "with token of a revoked session",
+			setAuthHeader: func(t *testing.T, tokenManager token.Manager, req *http.Request) {
+				payload, err := token.NewPayload(1, user.RoleGuest, c.App.TokenDuration)
+				require.NoError(t, err)
+				payload.SessionID = 1
+				token, err := tokenManager.SignToken(payload)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+			},
+			buildStubs: func(repository *mockdb.SessionMockRepository) {
+				repository.EXPECT().TouchSession(gomock.Any(), 1, 1, gomock.Any()).Return(session.ErrSessionNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "with token of an active session",
+			setAuthHeader: func(t *testing.T, tokenManager token.Manager, req *http.Request) {
+				payload, err := token.NewPayload(1, user.RoleGuest, c.App.TokenDuration)
+				require.NoError(t, err)
+				payload.SessionID = 2
+				token, err := tokenManager.SignToken(payload)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+				req.RemoteAddr = "203.0.113.7:4321"
+			},
+			buildStubs: func(repository *mockdb.SessionMockRepository) {
+				repository.EXPECT().TouchSession(gomock.Any(), 2, 1, "203.0.113.7").Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
 		{
 			name: "ok",
 			setAuthHeader: func(t *testing.T, tokenManager token.Manager, req *http.Request) {
-				token, err := tokenManager.GenerateToken(1, c.App.TokenDuration)
+				token, err := tokenManager.GenerateToken(1, user.RoleGuest, c.App.TokenDuration)
 				require.NoError(t, err)
 				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
 			},
@@ -71,14 +141,22 @@ func TestAuthMiddleware(t *testing.T) {
 
 	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
 	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
 	r := gin.Default()
 	authUrl := "/auth"
-	r.GET(authUrl, middlewares.AuthMiddleware(tokenManager), func(ctx *gin.Context) {
+	r.GET(authUrl, middlewares.AuthMiddleware(tokenManager, revocations, sessionRepository), func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, gin.H{})
 	})
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			if tc.buildStubs != nil {
+				tc.buildStubs(sessionRepository)
+			}
 
 			recorder := httptest.NewRecorder()
 			request := httptest.NewRequest(http.MethodGet, authUrl, nil)
@@ -89,3 +167,69 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionalAuthMiddleware(t *testing.T) {
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		setAuthHeader func(t *testing.T, req *http.Request)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:          "without authorization header",
+			setAuthHeader: func(t *testing.T, _ *http.Request) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"authenticated": false}`, recorder.Body.String())
+			},
+		},
+		{
+			// a header that is sent has to be valid
+			name: "with expired token",
+			setAuthHeader: func(t *testing.T, req *http.Request) {
+				token, err := tokenManager.GenerateToken(1, user.RoleGuest, -c.App.TokenDuration)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ok",
+			setAuthHeader: func(t *testing.T, req *http.Request) {
+				token, err := tokenManager.GenerateToken(1, user.RoleGuest, c.App.TokenDuration)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"authenticated": true}`, recorder.Body.String())
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessionRepository := mockdb.NewSessionMockRepository(ctrl)
+	r := gin.Default()
+	authUrl := "/auth"
+	r.GET(authUrl, middlewares.OptionalAuthMiddleware(tokenManager, token.NewMemoryRevocationList(), sessionRepository), func(ctx *gin.Context) {
+		_, authenticated := ctx.Get(middlewares.AuthUserIDKey)
+		ctx.JSON(http.StatusOK, gin.H{"authenticated": authenticated})
+	})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, authUrl, nil)
+
+			tc.setAuthHeader(t, request)
+			r.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
+
+// ErrorCodeEmailNotVerified is the code of the error responses refusing users who did not verify their email yet
+const ErrorCodeEmailNotVerified = "email_not_verified"
+
+// EmailVerifiedMiddleware is a Gin middleware that only lets users who verified their email through, it must run
+// after AuthMiddleware. The user is looked up on every request so that a verification counts right away.
+func EmailVerifiedMiddleware(userRepo user.Repository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		u, err := userRepo.FindByID(ctx, ctx.MustGet(AuthUserIDKey).(int))
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !u.IsEmailVerified() {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "verify your email address first, a new verification email can be requested",
+				"code":  ErrorCodeEmailNotVerified,
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
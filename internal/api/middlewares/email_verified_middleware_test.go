@@ -0,0 +1,86 @@
+package middlewares_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEmailVerifiedMiddleware(t *testing.T) {
+	verifiedAt := time.Now()
+	testCases := []struct {
+		name          string
+		buildStubs    func(userRepository *mockdb.UserMockRepository)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "verified",
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), 1).Times(1).
+					Return(&user.User{ID: 1, Email: "guest@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "not verified",
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), 1).Times(1).
+					Return(&user.User{ID: 1, Email: "guest@example.com"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+
+				var response map[string]string
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, middlewares.ErrorCodeEmailNotVerified, response["code"])
+			},
+		},
+		{
+			name: "user not found",
+			buildStubs: func(userRepository *mockdb.UserMockRepository) {
+				userRepository.EXPECT().FindByID(gomock.Any(), 1).Times(1).Return(nil, user.ErrUserNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepository := mockdb.NewUserMockRepository(ctrl)
+			tc.buildStubs(userRepository)
+
+			r := gin.Default()
+			r.POST("/book", middlewares.AuthMiddleware(tokenManager, token.NewMemoryRevocationList(), mockdb.NewSessionMockRepository(ctrl)), middlewares.EmailVerifiedMiddleware(userRepository), func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+
+			token, err := tokenManager.GenerateToken(1, user.RoleGuest, c.App.TokenDuration)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/book", nil)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			r.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
+
+// PermissionMiddleware is a Gin middleware that only lets users whose role is granted the permission through,
+// it must run after AuthMiddleware
+func PermissionMiddleware(permission user.Permission) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role, _ := ctx.MustGet(AuthUserRoleKey).(user.Role)
+
+		if !role.Can(permission) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "your role is not allowed to access this resource",
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package middlewares_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPermissionMiddleware(t *testing.T) {
+	testCases := []struct {
+		name         string
+		role         user.Role
+		permission   user.Permission
+		expectedCode int
+	}{
+		{"guest can not manage reservations", user.RoleGuest, user.PermissionManageReservations, http.StatusForbidden},
+		{"host can manage reservations", user.RoleHost, user.PermissionManageReservations, http.StatusOK},
+		{"host can not manage tables", user.RoleHost, user.PermissionManageTables, http.StatusForbidden},
+		{"manager can manage tables", user.RoleManager, user.PermissionManageTables, http.StatusOK},
+		{"manager can manage settings", user.RoleManager, user.PermissionManageSettings, http.StatusOK},
+		{"manager can not manage users", user.RoleManager, user.PermissionManageUsers, http.StatusForbidden},
+		{"admin can manage users", user.RoleAdmin, user.PermissionManageUsers, http.StatusOK},
+		{"token without role", user.Role(""), user.PermissionManageReservations, http.StatusForbidden},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+	r := gin.Default()
+	for _, permission := range []user.Permission{user.PermissionManageReservations, user.PermissionManageTables, user.PermissionManageSettings, user.PermissionManageUsers} {
+		r.GET("/"+string(permission), middlewares.AuthMiddleware(tokenManager, token.NewMemoryRevocationList(), mockdb.NewSessionMockRepository(gomock.NewController(t))), middlewares.PermissionMiddleware(permission), func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, gin.H{})
+		})
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/"+string(tc.permission), nil)
+
+			token, err := tokenManager.GenerateToken(1, tc.role, c.App.TokenDuration)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			r.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// TwoFactorMiddleware is a Gin middleware that only lets users whose role the policy requires a second factor of
+// through when they confirmed their login with one, it must run after AuthMiddleware
+func TwoFactorMiddleware(policy twofactor.Policy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(AuthPayloadKey).(*token.Payload)
+
+		if policy.IsRequired(payload.Role) && !payload.TwoFactor {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "your role requires two-factor authentication, enable it and log in again",
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
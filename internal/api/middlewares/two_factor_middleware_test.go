@@ -0,0 +1,56 @@
+package middlewares_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/mohammad19khodaei/restaurant_reservation/db/mock"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTwoFactorMiddleware(t *testing.T) {
+	testCases := []struct {
+		name         string
+		role         user.Role
+		twoFactor    bool
+		expectedCode int
+	}{
+		{"guest without second factor", user.RoleGuest, false, http.StatusOK},
+		{"manager without second factor", user.RoleManager, false, http.StatusForbidden},
+		{"manager with second factor", user.RoleManager, true, http.StatusOK},
+		{"admin without second factor", user.RoleAdmin, false, http.StatusForbidden},
+	}
+
+	tokenManager, err := token.NewJWTManger(c.App.SecretKey)
+	require.NoError(t, err)
+	policy := twofactor.Policy{RequiredRoles: []user.Role{user.RoleManager, user.RoleAdmin}}
+	r := gin.Default()
+	r.GET("/staff", middlewares.AuthMiddleware(tokenManager, token.NewMemoryRevocationList(), mockdb.NewSessionMockRepository(gomock.NewController(t))), middlewares.TwoFactorMiddleware(policy), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/staff", nil)
+
+			payload, err := token.NewPayload(1, tc.role, c.App.TokenDuration)
+			require.NoError(t, err)
+			payload.TwoFactor = tc.twoFactor
+			token, err := tokenManager.SignToken(payload)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, token))
+
+			r.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
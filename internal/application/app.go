@@ -8,10 +8,19 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/repositories"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/allocation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/notification"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -26,9 +35,23 @@ type Application struct {
 		UserRepository        user.Repository
 		TableRepository       table.Repository
 		ReservationRepository reservation.Repository
+		SessionRepository     session.Repository
+		TwoFactorRepository   twofactor.Repository
+		IdentityRepository    identity.Repository
 	}
 	Services struct {
-		TokenManger token.Manager
+		TokenManger     token.Manager
+		RevocationList  token.RevocationList
+		LoginGuard      *lockout.Guard
+		Notifier        notification.Notifier
+		TwoFactorPolicy twofactor.Policy
+		PasswordHasher  *password.Hasher
+		PasswordPolicy  password.Policy
+		// EmailResendPolicy limits how often users are sent verification emails
+		EmailResendPolicy user.ResendPolicy
+		// OIDCProviders are the OpenID Connect providers users sign in with, by their names
+		OIDCProviders map[string]*oidc.Provider
+		OpeningHours  reservation.OpeningHours
 	}
 }
 
@@ -40,8 +63,13 @@ func New(config *config.Config) (*Application, error) {
 		return nil, err
 	}
 	app.registerRepositories()
+	if err := app.checkSettingsCurrency(context.Background()); err != nil {
+		return nil, err
+	}
 	app.registerServices()
-	app.registerRouter()
+	if err := app.registerRouter(); err != nil {
+		return nil, err
+	}
 	return app, nil
 }
 
@@ -71,11 +99,31 @@ func (a *Application) SetUserRepository(repository user.Repository) {
 	a.Repositories.UserRepository = repository
 }
 
+// SetTableRepository sets the table repository for testing
+func (a *Application) SetTableRepository(repository table.Repository) {
+	a.Repositories.TableRepository = repository
+}
+
 // SetReservationRepository sets the user repository for testing
 func (a *Application) SetReservationRepository(repository reservation.Repository) {
 	a.Repositories.ReservationRepository = repository
 }
 
+// SetSessionRepository sets the session repository for testing
+func (a *Application) SetSessionRepository(repository session.Repository) {
+	a.Repositories.SessionRepository = repository
+}
+
+// SetTwoFactorRepository sets the two-factor repository for testing
+func (a *Application) SetTwoFactorRepository(repository twofactor.Repository) {
+	a.Repositories.TwoFactorRepository = repository
+}
+
+// SetIdentityRepository sets the identity repository for testing
+func (a *Application) SetIdentityRepository(repository identity.Repository) {
+	a.Repositories.IdentityRepository = repository
+}
+
 // InitDB initializes the database with some data
 func (a *Application) InitDB(ctx context.Context) {
 	if a.Repositories.TableRepository.GetTotalCount(ctx) > 0 {
@@ -114,24 +162,66 @@ func (a *Application) InitDB(ctx context.Context) {
 			SeatsCount: 10,
 		},
 	}
-	for _, table := range tables {
-		err := a.Repositories.TableRepository.CreateTable(ctx, &table)
+	for i := range tables {
+		tables[i].Name = fmt.Sprintf("Table %d", i+1)
+		err := a.Repositories.TableRepository.CreateTable(ctx, &tables[i])
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
 
-	err := a.Repositories.TableRepository.CreateTableSettings(ctx, 10)
+	// neighbouring tables that can be joined for parties no single table fits
+	groups := []table.Group{
+		{
+			Name:   "window 4-tops",
+			Tables: []table.Table{tables[0], tables[1]},
+		},
+		{
+			Name:   "patio 4-tops",
+			Tables: []table.Table{tables[2], tables[3]},
+		},
+		{
+			Name:   "main hall 6-tops",
+			Tables: []table.Table{tables[5], tables[6]},
+		},
+		{
+			Name:   "banquet 8-tops",
+			Tables: []table.Table{tables[7], tables[8]},
+		},
+		{
+			Name:   "banquet hall",
+			Tables: []table.Table{tables[7], tables[8], tables[9]},
+		},
+	}
+	for i := range groups {
+		err := a.Repositories.TableRepository.CreateGroup(ctx, &groups[i])
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	seatPrice, err := money.Parse("10", a.Config.Restaurant.Currency)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = a.Repositories.TableRepository.CreateTableSettings(ctx, seatPrice)
 	if err != nil {
 		log.Fatal(err)
 	}
 }
 
-func (a *Application) registerRouter() {
+func (a *Application) registerRouter() error {
 	router := gin.New()
 	router.Use(gin.Logger())
 
+	// the client IP login throttling counts failures by is only read from the headers set by trusted proxies
+	if err := router.SetTrustedProxies(a.Config.App.TrustedProxies); err != nil {
+		return err
+	}
+
 	a.Router = router
+	return nil
 }
 
 func (a *Application) registerDatabase() error {
@@ -155,14 +245,80 @@ func (a *Application) registerRepositories() {
 	}
 	a.Repositories.UserRepository = repositories.NewGormUserRepository(a.DB)
 	a.Repositories.TableRepository = repositories.NewGormTableRepository(a.DB)
-	a.Repositories.ReservationRepository = repositories.NewGormReservationRepository(a.DB)
+	a.Repositories.SessionRepository = repositories.NewGormSessionRepository(a.DB)
+	a.Repositories.TwoFactorRepository = repositories.NewGormTwoFactorRepository(a.DB)
+	a.Repositories.IdentityRepository = repositories.NewGormIdentityRepository(a.DB)
+
+	allocator, err := allocation.New(a.Config.Restaurant.AllocationStrategy)
+	if err != nil {
+		log.Fatalf("could not create table allocator: %v", err)
+	}
+	pricingEngine, err := newPricingEngine(a.Config)
+	if err != nil {
+		log.Fatalf("could not create pricing engine: %v", err)
+	}
+	a.Repositories.ReservationRepository = repositories.NewGormReservationRepository(a.DB, allocator, pricingEngine)
 }
 
 func (a *Application) registerServices() {
-	tokenManager, err := token.NewJWTManger(a.Config.App.SecretKey)
+	tokenManager, err := newTokenManager(a.Config)
 	if err != nil {
 		log.Fatalf("could not create token manager: %v", err)
 	}
 
 	a.Services.TokenManger = tokenManager
+
+	// without a database in testing mode the revoked tokens are only kept in memory
+	if a.Config.App.TestingMode {
+		a.Services.RevocationList = token.NewMemoryRevocationList()
+	} else {
+		a.Services.RevocationList = repositories.NewGormRevocationList(a.DB)
+	}
+
+	loginGuard, err := newLoginGuard(a.Config, a.DB)
+	if err != nil {
+		log.Fatalf("could not create login guard: %v", err)
+	}
+	a.Services.LoginGuard = loginGuard
+
+	notifier, err := newNotifier(a.Config)
+	if err != nil {
+		log.Fatalf("could not create notifier: %v", err)
+	}
+	a.Services.Notifier = notifier
+
+	twoFactorPolicy, err := newTwoFactorPolicy(a.Config)
+	if err != nil {
+		log.Fatalf("could not create two-factor policy: %v", err)
+	}
+	a.Services.TwoFactorPolicy = twoFactorPolicy
+	passwordHasher, err := newPasswordHasher(a.Config)
+	if err != nil {
+		log.Fatalf("could not create password hasher: %v", err)
+	}
+	a.Services.PasswordHasher = passwordHasher
+
+	passwordPolicy, err := newPasswordPolicy(a.Config)
+	if err != nil {
+		log.Fatalf("could not create password policy: %v", err)
+	}
+	a.Services.PasswordPolicy = passwordPolicy
+
+	a.Services.EmailResendPolicy = user.ResendPolicy{
+		Interval: a.Config.EmailVerification.ResendInterval,
+		Limit:    a.Config.EmailVerification.ResendLimit,
+		Window:   a.Config.EmailVerification.ResendWindow,
+	}
+
+	oidcProviders, err := newOIDCProviders(a.Config)
+	if err != nil {
+		log.Fatalf("could not create oidc providers: %v", err)
+	}
+	a.Services.OIDCProviders = oidcProviders
+
+	openingHours, err := newOpeningHours(a.Config)
+	if err != nil {
+		log.Fatalf("could not create opening hours: %v", err)
+	}
+	a.Services.OpeningHours = openingHours
 }
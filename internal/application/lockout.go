@@ -0,0 +1,40 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/repositories"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"gorm.io/gorm"
+)
+
+// newLoginGuard creates the login guard of the login throttling config, counting failed logins in memory or in
+// postgres so that every instance of the application sees them
+func newLoginGuard(c *config.Config, db *gorm.DB) (*lockout.Guard, error) {
+	var store lockout.Store
+	switch c.LoginThrottling.Store {
+	case "", "memory":
+		store = lockout.NewMemoryStore()
+	case "postgres":
+		if db == nil {
+			return nil, fmt.Errorf("login throttling store postgres needs a database")
+		}
+		store = repositories.NewGormLoginAttemptStore(db)
+	default:
+		return nil, fmt.Errorf("unknown login throttling store %q", c.LoginThrottling.Store)
+	}
+
+	return lockout.NewGuard(store, newLoginThrottlingPolicy(c.LoginThrottling.Account), newLoginThrottlingPolicy(c.LoginThrottling.IP)), nil
+}
+
+func newLoginThrottlingPolicy(p config.LoginThrottlingPolicy) lockout.Policy {
+	return lockout.Policy{
+		FreeFailures:    p.FreeFailures,
+		BaseDelay:       p.BaseDelay,
+		MaxDelay:        p.MaxDelay,
+		MaxFailures:     p.MaxFailures,
+		LockoutDuration: p.LockoutDuration,
+		ResetAfter:      p.ResetAfter,
+	}
+}
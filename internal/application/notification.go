@@ -0,0 +1,30 @@
+package application
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/notification"
+)
+
+// newNotifier creates the notifier of the notifications config
+func newNotifier(c *config.Config) (notification.Notifier, error) {
+	switch c.Notifications.Driver {
+	case "", "log":
+		if c.Notifications.LogFile == "" {
+			return notification.NewLogNotifier(os.Stderr), nil
+		}
+
+		file, err := os.OpenFile(c.Notifications.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		return notification.NewLogNotifier(file), nil
+	case "smtp":
+		smtpConfig := c.Notifications.SMTP
+		return notification.NewSMTPNotifier(smtpConfig.Host, smtpConfig.Port, smtpConfig.Username, smtpConfig.Password, smtpConfig.From)
+	default:
+		return nil, fmt.Errorf("unknown notifications driver %q", c.Notifications.Driver)
+	}
+}
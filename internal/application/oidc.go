@@ -0,0 +1,39 @@
+package application
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc"
+)
+
+// oidcRequestTimeout is how long a request to an OpenID Connect provider may take
+const oidcRequestTimeout = 10 * time.Second
+
+// newOIDCProviders creates the OpenID Connect providers of the oidc config by their names
+func newOIDCProviders(c *config.Config) (map[string]*oidc.Provider, error) {
+	client := &http.Client{Timeout: oidcRequestTimeout}
+
+	providers := make(map[string]*oidc.Provider, len(c.OIDC.Providers))
+	for _, p := range c.OIDC.Providers {
+		if p.Name == "" || p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "" {
+			return nil, fmt.Errorf("oidc provider %q needs a name, issuer, client_id and redirect_url", p.Name)
+		}
+		if _, ok := providers[p.Name]; ok {
+			return nil, fmt.Errorf("oidc provider %q is configured twice", p.Name)
+		}
+
+		providers[p.Name] = oidc.NewProvider(oidc.ProviderConfig{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, client)
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,31 @@
+package application
+
+import (
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+// newOpeningHours creates the opening hours of the restaurant config, the restaurant is open around the clock when
+// they are not configured
+func newOpeningHours(c *config.Config) (reservation.OpeningHours, error) {
+	location, err := time.LoadLocation(c.Restaurant.TimeZone)
+	if err != nil {
+		return reservation.OpeningHours{}, err
+	}
+
+	hours := reservation.OpeningHours{Location: location}
+	if c.Restaurant.OpensAt != "" {
+		if hours.Opens, err = parseTimeOfDay(c.Restaurant.OpensAt); err != nil {
+			return reservation.OpeningHours{}, err
+		}
+	}
+	if c.Restaurant.ClosesAt != "" {
+		if hours.Closes, err = parseTimeOfDay(c.Restaurant.ClosesAt); err != nil {
+			return reservation.OpeningHours{}, err
+		}
+	}
+
+	return hours, nil
+}
@@ -0,0 +1,67 @@
+package application
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
+)
+
+// bcryptMaxLength is the number of bytes of a password bcrypt hashes, the rest is ignored
+const bcryptMaxLength = 72
+
+// newPasswordHasher creates the hasher of the algorithm of the password config, verifying the hashes of the other
+// algorithm as well
+func newPasswordHasher(c *config.Config) (*password.Hasher, error) {
+	b, err := password.NewBcrypt(c.Password.Bcrypt.Cost)
+	if err != nil {
+		return nil, err
+	}
+
+	argon2id, err := password.NewArgon2id(password.Argon2idParams{
+		Memory:      c.Password.Argon2id.Memory,
+		Iterations:  c.Password.Argon2id.Iterations,
+		Parallelism: c.Password.Argon2id.Parallelism,
+		SaltLength:  c.Password.Argon2id.SaltLength,
+		KeyLength:   c.Password.Argon2id.KeyLength,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.Password.Algorithm {
+	case "argon2id":
+		return password.NewHasher(argon2id, b), nil
+	case "bcrypt":
+		if c.Password.MaxLength <= 0 || c.Password.MaxLength > bcryptMaxLength {
+			return nil, fmt.Errorf("max_length must be between 1 and %d with bcrypt", bcryptMaxLength)
+		}
+		return password.NewHasher(b, argon2id), nil
+	default:
+		return nil, fmt.Errorf("unknown password algorithm %q", c.Password.Algorithm)
+	}
+}
+
+// newPasswordPolicy creates the policy of the password config, refusing the passwords of the breached list file
+// along with the built-in ones
+func newPasswordPolicy(c *config.Config) (password.Policy, error) {
+	breached := password.NewBreachedList()
+	if c.Password.BreachedListFile != "" {
+		file, err := os.Open(c.Password.BreachedListFile)
+		if err != nil {
+			return password.Policy{}, err
+		}
+		defer file.Close()
+
+		if err := breached.Load(file); err != nil {
+			return password.Policy{}, fmt.Errorf("could not read breached list: %w", err)
+		}
+	}
+
+	return password.Policy{
+		MinLength: c.Password.MinLength,
+		MaxLength: c.Password.MaxLength,
+		Breached:  breached,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/pricing"
+)
+
+// newPricingEngine creates the pricing engine of the pricing rules and charges of the config, amounts of the
+// config are in the restaurant currency. Seats are charged first, then the full table discount, the day of week
+// and peak hours multipliers and the party minimums apply, and the tax and service charge come on top.
+func newPricingEngine(c *config.Config) (*pricing.Engine, error) {
+	currency, err := money.ValidateCurrency(c.Restaurant.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(c.Pricing.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	seatPrices := pricing.SeatPrices{BySize: make(map[int]money.Money, len(c.Pricing.TableSizeSeatPrices))}
+	for _, p := range c.Pricing.TableSizeSeatPrices {
+		seatPrice, err := money.Parse(p.SeatPrice, c.Restaurant.Currency)
+		if err != nil {
+			return nil, err
+		}
+		seatPrices.BySize[p.SeatsCount] = seatPrice
+	}
+
+	rules := []pricing.Rule{seatPrices}
+	if c.Pricing.FullTableDiscount {
+		rules = append(rules, pricing.FullTableDiscount{SeatPrices: seatPrices})
+	}
+
+	rules = append(rules, pricing.DayOfWeekMultiplier{
+		Weekday:  c.Pricing.WeekdayMultiplier,
+		Weekend:  c.Pricing.WeekendMultiplier,
+		Location: location,
+	})
+
+	for _, p := range c.Pricing.PeakHours {
+		from, err := parseTimeOfDay(p.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseTimeOfDay(p.To)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, pricing.PeakHours{From: from, To: to, Multiplier: p.Multiplier, Location: location})
+	}
+
+	var minimums pricing.PartyMinimums
+	for _, m := range c.Pricing.PartyMinimums {
+		amount, err := money.Parse(m.Amount, c.Restaurant.Currency)
+		if err != nil {
+			return nil, err
+		}
+		minimums = append(minimums, pricing.PartyMinimum{SeatsCount: m.SeatsCount, Amount: amount})
+	}
+	if len(minimums) > 0 {
+		rules = append(rules, minimums)
+	}
+
+	charges := pricing.Charges{
+		TaxRate:           c.Pricing.TaxRate,
+		TaxInclusive:      c.Pricing.TaxInclusive,
+		ServiceChargeRate: c.Pricing.ServiceChargeRate,
+	}
+
+	return pricing.NewEngine(currency, charges, rules...), nil
+}
+
+// checkSettingsCurrency makes sure the seat prices of the stored settings are in the restaurant currency, the
+// amounts of the pricing config are in it and can not be mixed with prices of another currency
+func (a *Application) checkSettingsCurrency(ctx context.Context) error {
+	if a.Config.App.TestingMode {
+		return nil
+	}
+
+	currency, err := money.ValidateCurrency(a.Config.Restaurant.Currency)
+	if err != nil {
+		return err
+	}
+
+	history, err := a.Repositories.TableRepository.ListSettings(ctx)
+	if err != nil {
+		return err
+	}
+	for _, settings := range history {
+		if settings.SeatPrice.Currency != currency {
+			return fmt.Errorf("seat price of the settings %d is in %s but restaurant.currency is %s", settings.ID, settings.SeatPrice.Currency, currency)
+		}
+	}
+
+	return nil
+}
+
+// parseTimeOfDay parses a time of day such as 19:30 into the duration since midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -3,14 +3,76 @@ package application
 import (
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/actions"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/api/middlewares"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
 )
 
 func (a *Application) RegisterRoutes() {
-	a.Router.POST("users", actions.RegisterUserAction(a.Repositories.UserRepository))
-	a.Router.POST("users/login", actions.LoginAction(a.Repositories.UserRepository, a.Services.TokenManger, a.Config.App.TokenDuration))
+	a.Router.POST("users", actions.RegisterUserAction(a.Repositories.UserRepository, a.Services.PasswordHasher, a.Services.PasswordPolicy, a.Services.Notifier, a.Config.EmailVerification.TokenDuration, a.Config.EmailVerification.URL))
+	a.Router.POST("users/login", actions.LoginAction(a.Repositories.UserRepository, a.Repositories.SessionRepository, a.Repositories.TwoFactorRepository, a.Services.TokenManger, a.Services.LoginGuard, a.Services.PasswordHasher, a.Config.App.TokenDuration, a.Config.App.RefreshTokenDuration, a.Config.TwoFactor.ChallengeDuration))
+	a.Router.POST("users/login/2fa", actions.VerifyTwoFactorAction(a.Repositories.UserRepository, a.Repositories.SessionRepository, a.Repositories.TwoFactorRepository, a.Services.TokenManger, a.Services.RevocationList, a.Services.LoginGuard, a.Config.App.TokenDuration, a.Config.App.RefreshTokenDuration))
+	a.Router.GET("users/oidc/:provider", actions.OIDCLoginAction(a.Repositories.IdentityRepository, a.Services.OIDCProviders, a.Config.OIDC.StateDuration))
+	a.Router.GET("users/oidc/:provider/callback", middlewares.OptionalAuthMiddleware(a.Services.TokenManger, a.Services.RevocationList, a.Repositories.SessionRepository), actions.OIDCCallbackAction(a.Repositories.UserRepository, a.Repositories.SessionRepository, a.Repositories.TwoFactorRepository, a.Repositories.IdentityRepository, a.Services.OIDCProviders, a.Services.TokenManger, a.Config.App.TokenDuration, a.Config.App.RefreshTokenDuration, a.Config.TwoFactor.ChallengeDuration))
+	a.Router.POST("users/email/verify", actions.VerifyEmailAction(a.Repositories.UserRepository))
+	a.Router.POST("users/password/forgot", actions.ForgotPasswordAction(a.Repositories.UserRepository, a.Services.Notifier, a.Config.PasswordReset.TokenDuration, a.Config.PasswordReset.URL))
+	a.Router.POST("users/password/reset", actions.ResetPasswordAction(a.Repositories.UserRepository, a.Repositories.SessionRepository, a.Services.RevocationList, a.Services.LoginGuard, a.Services.PasswordHasher, a.Services.PasswordPolicy))
+	a.Router.POST("users/refresh", actions.RefreshAction(a.Repositories.UserRepository, a.Repositories.SessionRepository, a.Services.TokenManger, a.Services.RevocationList, a.Config.App.TokenDuration, a.Config.App.RefreshTokenDuration))
+	if publisher, ok := a.Services.TokenManger.(token.KeyPublisher); ok {
+		a.Router.GET(".well-known/jwks.json", actions.JWKSAction(publisher))
+	}
+	a.Router.GET("availability", actions.AvailabilityAction(a.Repositories.ReservationRepository, a.Config.Restaurant.SittingDuration, a.Config.Restaurant.SlotInterval, a.Services.OpeningHours))
 
-	authRoute := a.Router.Group("/").Use(middlewares.AuthMiddleware(a.Services.TokenManger))
+	authRoute := a.Router.Group("/").Use(middlewares.AuthMiddleware(a.Services.TokenManger, a.Services.RevocationList, a.Repositories.SessionRepository))
 
-	authRoute.POST("book", actions.BookAction(a.Repositories.ReservationRepository))
-	authRoute.POST("cancel", actions.CancelAction(a.Repositories.ReservationRepository))
+	authRoute.POST("users/logout", actions.LogoutAction(a.Repositories.SessionRepository, a.Services.RevocationList))
+	authRoute.GET("users/me", actions.GetProfileAction(a.Repositories.UserRepository))
+	authRoute.PATCH("users/me", actions.UpdateProfileAction(a.Repositories.UserRepository, a.Services.Notifier, a.Services.LoginGuard, a.Services.PasswordHasher, a.Config.EmailVerification.TokenDuration, a.Config.EmailVerification.URL, a.Services.EmailResendPolicy))
+	authRoute.DELETE("users/me", actions.DeleteAccountAction(a.Repositories.UserRepository, a.Repositories.SessionRepository, a.Services.RevocationList, a.Services.LoginGuard, a.Services.PasswordHasher))
+	authRoute.POST("users/me/email/verification", actions.ResendEmailVerificationAction(a.Repositories.UserRepository, a.Services.Notifier, a.Config.EmailVerification.TokenDuration, a.Config.EmailVerification.URL, a.Services.EmailResendPolicy))
+	authRoute.POST("users/me/password", actions.ChangePasswordAction(a.Repositories.UserRepository, a.Repositories.SessionRepository, a.Services.TokenManger, a.Services.RevocationList, a.Services.LoginGuard, a.Services.PasswordHasher, a.Services.PasswordPolicy, a.Config.App.TokenDuration, a.Config.App.RefreshTokenDuration))
+	authRoute.GET("users/me/sessions", actions.ListSessionsAction(a.Repositories.SessionRepository))
+	authRoute.DELETE("users/me/sessions", actions.RevokeOtherSessionsAction(a.Repositories.SessionRepository, a.Services.RevocationList))
+	authRoute.DELETE("users/me/sessions/:id", actions.RevokeSessionAction(a.Repositories.SessionRepository, a.Services.RevocationList))
+	authRoute.GET("users/me/identities", actions.ListIdentitiesAction(a.Repositories.IdentityRepository))
+	authRoute.POST("users/me/identities/:provider", actions.LinkIdentityAction(a.Repositories.IdentityRepository, a.Services.OIDCProviders, a.Config.OIDC.StateDuration))
+	authRoute.POST("users/me/2fa", actions.EnrolTwoFactorAction(a.Repositories.UserRepository, a.Repositories.TwoFactorRepository, a.Services.LoginGuard, a.Services.PasswordHasher, a.Config.TwoFactor.Issuer))
+	authRoute.POST("users/me/2fa/confirm", actions.ConfirmTwoFactorAction(a.Repositories.TwoFactorRepository))
+	authRoute.POST("users/me/2fa/backup-codes", actions.RegenerateBackupCodesAction(a.Repositories.UserRepository, a.Repositories.TwoFactorRepository, a.Services.LoginGuard))
+	authRoute.DELETE("users/me/2fa", actions.DisableTwoFactorAction(a.Repositories.UserRepository, a.Repositories.TwoFactorRepository, a.Services.LoginGuard, a.Services.PasswordHasher, a.Services.TwoFactorPolicy))
+	authRoute.POST("book", middlewares.EmailVerifiedMiddleware(a.Repositories.UserRepository), actions.BookAction(a.Repositories.ReservationRepository, a.Config.Restaurant.SittingDuration))
+	authRoute.POST("cancel", actions.CancelAction(a.Repositories.ReservationRepository, a.Services.TwoFactorPolicy))
+	authRoute.GET("reservations", actions.ListReservationsAction(a.Repositories.ReservationRepository))
+	authRoute.GET("reservations/:id", actions.GetReservationAction(a.Repositories.ReservationRepository))
+	authRoute.PATCH("reservations/:id", actions.ModifyReservationAction(a.Repositories.ReservationRepository))
+
+	hostRoute := a.Router.Group("/").Use(middlewares.AuthMiddleware(a.Services.TokenManger, a.Services.RevocationList, a.Repositories.SessionRepository), middlewares.PermissionMiddleware(user.PermissionManageReservations), middlewares.TwoFactorMiddleware(a.Services.TwoFactorPolicy))
+
+	hostRoute.POST("reservations/:id/confirm", actions.UpdateStatusAction(a.Repositories.ReservationRepository, reservation.StatusConfirmed))
+	hostRoute.POST("reservations/:id/seat", actions.UpdateStatusAction(a.Repositories.ReservationRepository, reservation.StatusSeated))
+	hostRoute.POST("reservations/:id/complete", actions.UpdateStatusAction(a.Repositories.ReservationRepository, reservation.StatusCompleted))
+	hostRoute.POST("reservations/:id/no-show", actions.UpdateStatusAction(a.Repositories.ReservationRepository, reservation.StatusNoShow))
+
+	tablesRoute := a.Router.Group("/admin/tables").Use(middlewares.AuthMiddleware(a.Services.TokenManger, a.Services.RevocationList, a.Repositories.SessionRepository), middlewares.PermissionMiddleware(user.PermissionManageTables), middlewares.TwoFactorMiddleware(a.Services.TwoFactorPolicy))
+
+	tablesRoute.GET("", actions.ListTablesAction(a.Repositories.TableRepository))
+	tablesRoute.POST("", actions.CreateTableAction(a.Repositories.TableRepository))
+	tablesRoute.PATCH(":id", actions.UpdateTableAction(a.Repositories.TableRepository))
+	tablesRoute.DELETE(":id", actions.RetireTableAction(a.Repositories.TableRepository))
+	tablesRoute.POST(":id/reassign", actions.ReassignTableAction(a.Repositories.TableRepository, a.Repositories.ReservationRepository))
+
+	settingsRoute := a.Router.Group("/admin/settings").Use(middlewares.AuthMiddleware(a.Services.TokenManger, a.Services.RevocationList, a.Repositories.SessionRepository), middlewares.PermissionMiddleware(user.PermissionManageSettings), middlewares.TwoFactorMiddleware(a.Services.TwoFactorPolicy))
+
+	settingsRoute.GET("", actions.GetSettingsAction(a.Repositories.TableRepository))
+	settingsRoute.POST("", actions.ScheduleSettingsAction(a.Repositories.TableRepository, a.Config.Restaurant.Currency))
+	settingsRoute.DELETE(":id", actions.DeleteScheduledSettingsAction(a.Repositories.TableRepository))
+
+	usersRoute := a.Router.Group("/admin/users").Use(middlewares.AuthMiddleware(a.Services.TokenManger, a.Services.RevocationList, a.Repositories.SessionRepository), middlewares.PermissionMiddleware(user.PermissionManageUsers), middlewares.TwoFactorMiddleware(a.Services.TwoFactorPolicy))
+
+	usersRoute.PUT(":id/role", actions.UpdateUserRoleAction(a.Repositories.UserRepository))
+
+	lockoutsRoute := a.Router.Group("/admin/lockouts").Use(middlewares.AuthMiddleware(a.Services.TokenManger, a.Services.RevocationList, a.Repositories.SessionRepository), middlewares.PermissionMiddleware(user.PermissionManageUsers), middlewares.TwoFactorMiddleware(a.Services.TwoFactorPolicy))
+
+	lockoutsRoute.GET("", actions.ListLockoutsAction(a.Services.LoginGuard))
+	lockoutsRoute.DELETE(":kind/*subject", actions.ClearLockoutAction(a.Services.LoginGuard))
 }
@@ -0,0 +1,25 @@
+package application
+
+import (
+	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+)
+
+// newTokenManager creates the token manager of the signing keys of the config, falling back to the HS256 secret
+// key when no signing key is configured
+func newTokenManager(c *config.Config) (token.Manager, error) {
+	if len(c.App.SigningKeys.Keys) == 0 {
+		return token.NewJWTManger(c.App.SecretKey)
+	}
+
+	keys := make([]token.Key, 0, len(c.App.SigningKeys.Keys))
+	for _, k := range c.App.SigningKeys.Keys {
+		key, err := token.LoadKey(k.ID, k.PrivateKeyFile, k.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return token.NewKeyManager(keys, c.App.SigningKeys.ActiveKeyID)
+}
@@ -0,0 +1,23 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/config"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
+
+// newTwoFactorPolicy creates the policy of the roles the two-factor config requires a second factor of
+func newTwoFactorPolicy(c *config.Config) (twofactor.Policy, error) {
+	var policy twofactor.Policy
+	for _, name := range c.TwoFactor.RequiredRoles {
+		role := user.Role(name)
+		if !role.IsValid() {
+			return twofactor.Policy{}, fmt.Errorf("unknown role %q", name)
+		}
+		policy.RequiredRoles = append(policy.RequiredRoles, role)
+	}
+
+	return policy, nil
+}
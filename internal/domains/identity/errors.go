@@ -0,0 +1,11 @@
+package identity
+
+import "errors"
+
+var (
+	ErrIdentityNotFound      = errors.New("identity not found")
+	ErrInvalidState          = errors.New("sign-in state is invalid or expired")
+	ErrIdentityAlreadyLinked = errors.New("identity is already linked to an account")
+	ErrProviderAlreadyLinked = errors.New("an identity of this provider is already linked to the account")
+	ErrUnverifiedAccount     = errors.New("an account with the email exists but did not verify it, log in to link the identity")
+)
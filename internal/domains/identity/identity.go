@@ -0,0 +1,45 @@
+package identity
+
+import "time"
+
+// Identity is an account at an OpenID Connect provider a user signs in with, the provider knows the account by the
+// subject
+type Identity struct {
+	ID       int    `gorm:"type:bigserial;primaryKey"`
+	UserID   int    `gorm:"type:bigint,NOT NULL"`
+	Provider string `gorm:"type:varchar,NOT NULL"`
+	Subject  string `gorm:"type:varchar,NOT NULL"`
+	// Email is the email the provider told when the identity was linked
+	Email     string    `gorm:"type:varchar,NOT NULL"`
+	CreatedAt time.Time `gorm:"type:timestamptz"`
+}
+
+// TableName returns the table name
+func (i Identity) TableName() string {
+	return "user_identities"
+}
+
+// LoginState is a sign-in started at a provider, the provider sends the user back with the state whose hash it keeps
+// and it can be completed once, until it expires
+type LoginState struct {
+	ID        int    `gorm:"type:bigserial;primaryKey"`
+	StateHash string `gorm:"type:varchar,NOT NULL"`
+	Provider  string `gorm:"type:varchar,NOT NULL"`
+	// Nonce is what the ID token of the sign-in has to carry and CodeVerifier the PKCE secret of the code
+	Nonce        string `gorm:"type:varchar,NOT NULL"`
+	CodeVerifier string `gorm:"type:varchar,NOT NULL"`
+	// UserID is the user linking the identity to their account, nil for a login
+	UserID    *int      `gorm:"type:bigint"`
+	ExpiresAt time.Time `gorm:"type:timestamptz,NOT NULL"`
+	CreatedAt time.Time `gorm:"type:timestamptz"`
+}
+
+// TableName returns the table name
+func (s LoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// IsLinking reports whether the sign-in links the identity to the account of a signed in user
+func (s LoginState) IsLinking() bool {
+	return s.UserID != nil
+}
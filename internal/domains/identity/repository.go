@@ -0,0 +1,16 @@
+package identity
+
+import (
+	"context"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
+
+type Repository interface {
+	CreateLoginState(ctx context.Context, state *LoginState) error
+	ConsumeLoginState(ctx context.Context, stateHash string) (*LoginState, error)
+	FindIdentity(ctx context.Context, provider string, subject string) (*Identity, error)
+	ListUserIdentities(ctx context.Context, userID int) ([]Identity, error)
+	LinkIdentity(ctx context.Context, identity *Identity) error
+	RegisterUser(ctx context.Context, u *user.User, identity *Identity) error
+}
@@ -0,0 +1,38 @@
+package money
+
+import "strings"
+
+// exponents are the number of minor unit digits of the supported ISO 4217 currencies
+var exponents = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"CAD": 2,
+	"CHF": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"IRR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"SEK": 2,
+	"TRY": 2,
+	"USD": 2,
+}
+
+// ValidateCurrency returns the currency code in upper case if it is a supported ISO 4217 currency
+func ValidateCurrency(currency string) (string, error) {
+	code := strings.ToUpper(currency)
+	if _, ok := exponents[code]; !ok {
+		return "", ErrUnknownCurrency
+	}
+	return code, nil
+}
+
+// exponent returns the number of minor unit digits of the currency, two for unknown currencies
+func exponent(currency string) int {
+	if e, ok := exponents[currency]; ok {
+		return e
+	}
+	return 2
+}
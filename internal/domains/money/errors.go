@@ -0,0 +1,9 @@
+package money
+
+import "errors"
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+)
@@ -0,0 +1,202 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount of a currency, in the minor units of the currency, e.g. cents
+type Money struct {
+	Amount   int64  `gorm:"type:bigint,NOT NULL"`
+	Currency string `gorm:"type:char(3),NOT NULL"`
+}
+
+// New creates an amount of minor units of the currency
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Zero returns no money of the currency
+func Zero(currency string) Money {
+	return Money{Currency: currency}
+}
+
+// Parse parses a decimal amount in major units, e.g. "12.50", of the currency. Amounts with more decimal digits
+// than the currency has minor unit digits are rejected rather than rounded.
+func Parse(amount string, currency string) (Money, error) {
+	code, err := ValidateCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	minor := value.Mul(value, new(big.Rat).SetInt(scale(code)))
+	if !minor.IsInt() || !minor.Num().IsInt64() {
+		return Money{}, fmt.Errorf("%w: %q has too many decimal digits for %s", ErrInvalidAmount, amount, code)
+	}
+
+	return New(minor.Num().Int64(), code), nil
+}
+
+// MustParse is like Parse but panics when the amount can not be parsed
+func MustParse(amount string, currency string) Money {
+	m, err := Parse(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Add returns the sum of both amounts. Zero money without a currency can be added to any currency,
+// adding amounts of different currencies panics.
+func (m Money) Add(other Money) Money {
+	currency := m.sameCurrency(other)
+	return New(m.Amount+other.Amount, currency)
+}
+
+// Sub returns the difference of both amounts, like Add
+func (m Money) Sub(other Money) Money {
+	return m.Add(other.Neg())
+}
+
+// Neg returns the negated amount
+func (m Money) Neg() Money {
+	return New(-m.Amount, m.Currency)
+}
+
+// Cmp compares both amounts and returns -1, 0 or 1, like Add it panics for different currencies
+func (m Money) Cmp(other Money) int {
+	m.sameCurrency(other)
+	switch {
+	case m.Amount < other.Amount:
+		return -1
+	case m.Amount > other.Amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Multiply returns the amount multiplied by factor, e.g. 1.25 for a 25% surcharge. The factor is taken as the
+// decimal it is written as, so 0.1 is exactly a tenth, and the result is rounded to the nearest minor unit with
+// ties rounded to the even one, which keeps the rounding of many discounts and taxes unbiased.
+func (m Money) Multiply(factor float64) Money {
+	rate := decimal(factor)
+	return New(roundHalfEven(rate.Mul(rate, new(big.Rat).SetInt64(m.Amount))), m.Currency)
+}
+
+// Divide returns the amount divided by divisor, e.g. 1.09 to take a 9% tax out of a price including it,
+// rounded like Multiply. Dividing by zero panics.
+func (m Money) Divide(divisor float64) Money {
+	if divisor == 0 {
+		panic(fmt.Errorf("%w: can not divide by zero", ErrInvalidAmount))
+	}
+	amount := new(big.Rat).SetInt64(m.Amount)
+	return New(roundHalfEven(amount.Quo(amount, decimal(divisor))), m.Currency)
+}
+
+// Decimal returns the amount in major units, e.g. "12.50"
+func (m Money) Decimal() string {
+	e := exponent(m.Currency)
+	if e == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	digits := fmt.Sprintf("%0*d", e+1, amount)
+	return sign + digits[:len(digits)-e] + "." + digits[len(digits)-e:]
+}
+
+// String returns the amount in major units followed by the currency, e.g. "12.50 USD"
+func (m Money) String() string {
+	return strings.TrimSpace(m.Decimal() + " " + m.Currency)
+}
+
+type moneyJSON struct {
+	Amount     string `json:"amount"`
+	MinorUnits int64  `json:"minor_units"`
+	Currency   string `json:"currency"`
+}
+
+// MarshalJSON encodes the amount both in major units, as a decimal string, and in minor units
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), MinorUnits: m.Amount, Currency: m.Currency})
+}
+
+// UnmarshalJSON decodes the amount from its minor units
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var value moneyJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*m = New(value.MinorUnits, value.Currency)
+	return nil
+}
+
+// sameCurrency returns the currency of both amounts and panics when they differ
+func (m Money) sameCurrency(other Money) string {
+	switch {
+	case m.Currency == other.Currency:
+		return m.Currency
+	case m.Currency == "" && m.Amount == 0:
+		return other.Currency
+	case other.Currency == "" && other.Amount == 0:
+		return m.Currency
+	default:
+		panic(fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency))
+	}
+}
+
+// decimal returns the exact value of the decimal the float is written as
+func decimal(value float64) *big.Rat {
+	rat, ok := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	if !ok {
+		panic(fmt.Errorf("%w: %v is not a number", ErrInvalidAmount, value))
+	}
+	return rat
+}
+
+// scale returns the number of minor units in a major unit of the currency
+func scale(currency string) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent(currency))), nil)
+}
+
+// roundHalfEven rounds the value to the nearest integer, ties to the even one
+func roundHalfEven(value *big.Rat) int64 {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+
+	// compare twice the remainder with the denominator to find out if the value is past the half
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+	switch twice.Cmp(value.Denom()) {
+	case 1:
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	case 0:
+		if quotient.Bit(0) == 1 {
+			quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+		}
+	}
+
+	return quotient.Int64()
+}
@@ -0,0 +1,129 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		amount   string
+		currency string
+		expected money.Money
+		err      error
+	}{
+		{amount: "12.50", currency: "USD", expected: money.New(1250, "USD")},
+		{amount: "12.5", currency: "eur", expected: money.New(1250, "EUR")},
+		{amount: "10", currency: "USD", expected: money.New(1000, "USD")},
+		{amount: "-0.01", currency: "USD", expected: money.New(-1, "USD")},
+		{amount: "1500", currency: "JPY", expected: money.New(1500, "JPY")},
+		{amount: "1.234", currency: "KWD", expected: money.New(1234, "KWD")},
+		{amount: "0.001", currency: "USD", err: money.ErrInvalidAmount},
+		{amount: "1.5", currency: "JPY", err: money.ErrInvalidAmount},
+		{amount: "ten", currency: "USD", err: money.ErrInvalidAmount},
+		{amount: "10", currency: "XYZ", err: money.ErrUnknownCurrency},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.amount+" "+tc.currency, func(t *testing.T) {
+			m, err := money.Parse(tc.amount, tc.currency)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestMultiplyRoundsHalfToEven(t *testing.T) {
+	testCases := []struct {
+		amount   int64
+		factor   float64
+		expected int64
+	}{
+		{amount: 1000, factor: 1.25, expected: 1250},
+		// 0.1 is taken as exactly a tenth, not as its binary approximation
+		{amount: 1005, factor: 0.1, expected: 100},
+		{amount: 1015, factor: 0.1, expected: 102},
+		{amount: 1001, factor: 0.5, expected: 500},
+		{amount: 1003, factor: 0.5, expected: 502},
+		{amount: 333, factor: 0.09, expected: 30},
+		{amount: -1005, factor: 0.1, expected: -100},
+		{amount: -1015, factor: 0.1, expected: -102},
+		{amount: 1000, factor: 0, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, money.New(tc.expected, "USD"), money.New(tc.amount, "USD").Multiply(tc.factor), "%d x %v", tc.amount, tc.factor)
+	}
+}
+
+func TestDivideRoundsHalfToEven(t *testing.T) {
+	testCases := []struct {
+		amount   int64
+		divisor  float64
+		expected int64
+	}{
+		{amount: 1090, divisor: 1.09, expected: 1000},
+		{amount: 1000, divisor: 1.09, expected: 917},
+		{amount: 5, divisor: 2, expected: 2},
+		{amount: 7, divisor: 2, expected: 4},
+		{amount: -7, divisor: 2, expected: -4},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, money.New(tc.expected, "USD"), money.New(tc.amount, "USD").Divide(tc.divisor), "%d / %v", tc.amount, tc.divisor)
+	}
+
+	require.Panics(t, func() {
+		money.New(100, "USD").Divide(0)
+	})
+}
+
+func TestArithmetic(t *testing.T) {
+	a := money.New(1250, "USD")
+	b := money.New(300, "USD")
+
+	require.Equal(t, money.New(1550, "USD"), a.Add(b))
+	require.Equal(t, money.New(950, "USD"), a.Sub(b))
+	require.Equal(t, money.New(-1250, "USD"), a.Neg())
+	require.Equal(t, 1, a.Cmp(b))
+	require.Equal(t, -1, b.Cmp(a))
+	require.Equal(t, 0, a.Cmp(a))
+	require.True(t, money.Zero("USD").IsZero())
+	require.True(t, b.Sub(a).IsNegative())
+
+	// zero money without a currency adds to any currency
+	require.Equal(t, a, money.Money{}.Add(a))
+	require.Equal(t, a, a.Add(money.Money{}))
+
+	require.PanicsWithError(t, "currencies do not match: USD and EUR", func() {
+		a.Add(money.New(100, "EUR"))
+	})
+}
+
+func TestDecimal(t *testing.T) {
+	require.Equal(t, "12.50", money.New(1250, "USD").Decimal())
+	require.Equal(t, "0.05", money.New(5, "USD").Decimal())
+	require.Equal(t, "-0.05", money.New(-5, "USD").Decimal())
+	require.Equal(t, "1500", money.New(1500, "JPY").Decimal())
+	require.Equal(t, "1.234", money.New(1234, "KWD").Decimal())
+	require.Equal(t, "12.50 USD", money.New(1250, "USD").String())
+}
+
+func TestJSON(t *testing.T) {
+	m := money.New(1250, "USD")
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount": "12.50", "minor_units": 1250, "currency": "USD"}`, string(data))
+
+	var decoded money.Money
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, m, decoded)
+}
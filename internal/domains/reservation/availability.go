@@ -0,0 +1,17 @@
+package reservation
+
+import (
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+)
+
+// Availability is a table, or a group of joinable tables, that can host a party in a time slot,
+// with the price quoted for it
+type Availability struct {
+	StartAt        time.Time
+	EndAt          time.Time
+	TableIDs       []uint
+	AvailableSeats int
+	Price          money.Money
+}
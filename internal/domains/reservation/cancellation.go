@@ -0,0 +1,19 @@
+package reservation
+
+import "time"
+
+// Cancellation is the audit record of a canceled reservation
+type Cancellation struct {
+	ID              int       `gorm:"type:bigserial;primaryKey"`
+	ReservationID   int       `gorm:"type:int,NOT NULL"`
+	ReservedBy      uint      `gorm:"type:int"`
+	CancelledBy     int       `gorm:"type:int,NOT NULL"`
+	OnBehalfOfGuest bool      `gorm:"type:boolean,NOT NULL"`
+	Reason          string    `gorm:"type:varchar"`
+	CancelledAt     time.Time `gorm:"type:timestamptz,NOT NULL"`
+}
+
+// TableName returns the table name
+func (c Cancellation) TableName() string {
+	return "reservation_cancellations"
+}
@@ -3,6 +3,9 @@ package reservation
 import "errors"
 
 var (
-	ErrNoTablesAreAvailable = errors.New("no tables are available")
-	ErrReservationNotFound  = errors.New("reservation not found")
+	ErrNoTablesAreAvailable     = errors.New("no tables are available")
+	ErrReservationNotFound      = errors.New("reservation not found")
+	ErrReservationNotOwned      = errors.New("reservation belongs to another user")
+	ErrInvalidStatusTransition  = errors.New("reservation can not move to the requested status")
+	ErrReservationNotModifiable = errors.New("reservation can not be modified in its current status")
 )
@@ -0,0 +1,32 @@
+package reservation
+
+import "time"
+
+// SortField is a reservation attribute the reservations can be ordered by
+type SortField string
+
+const (
+	SortByStartAt    SortField = "start_at"
+	SortByPrice      SortField = "price"
+	SortBySeatsCount SortField = "seats_count"
+)
+
+// ListFilter narrows down, orders and paginates the reservations of a user
+type ListFilter struct {
+	// From and To bound the start time of the reservations, zero values mean unbounded
+	From     time.Time
+	To       time.Time
+	Statuses []Status
+	SortBy   SortField
+	SortDesc bool
+	Page     int
+	PerPage  int
+}
+
+// Offset returns the number of reservations to skip for the filter page
+func (f ListFilter) Offset() int {
+	if f.Page < 1 {
+		return 0
+	}
+	return (f.Page - 1) * f.PerPage
+}
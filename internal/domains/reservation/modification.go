@@ -0,0 +1,10 @@
+package reservation
+
+import "time"
+
+// Modification holds the requested changes of a reservation, zero values keep the current value
+type Modification struct {
+	SeatsCount int
+	StartAt    time.Time
+	Duration   time.Duration
+}
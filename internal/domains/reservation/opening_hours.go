@@ -0,0 +1,35 @@
+package reservation
+
+import "time"
+
+// OpeningHours is when the restaurant is open every day, as times of day in its location. A closing time that is
+// not after the opening time is on the next day, so equal times keep the restaurant open around the clock.
+type OpeningHours struct {
+	Opens    time.Duration
+	Closes   time.Duration
+	Location *time.Location
+}
+
+// Day returns when the restaurant opens and closes on the calendar date of the given time
+func (h OpeningHours) Day(date time.Time) (opensAt, closesAt time.Time) {
+	location := h.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	year, month, day := date.Date()
+	opensAt = atTimeOfDay(year, month, day, h.Opens, location)
+	if h.Closes <= h.Opens {
+		day++
+	}
+	closesAt = atTimeOfDay(year, month, day, h.Closes, location)
+
+	return opensAt, closesAt
+}
+
+// atTimeOfDay returns the time of day on the date, keeping the wall clock time when daylight saving time changes
+func atTimeOfDay(year int, month time.Month, day int, timeOfDay time.Duration, location *time.Location) time.Time {
+	hours := int(timeOfDay / time.Hour)
+	minutes := int(timeOfDay % time.Hour / time.Minute)
+	return time.Date(year, month, day, hours, minutes, 0, 0, location)
+}
@@ -0,0 +1,61 @@
+package reservation_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpeningHoursDay(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		hours    reservation.OpeningHours
+		date     time.Time
+		opensAt  time.Time
+		closesAt time.Time
+	}{
+		{
+			name:     "same day",
+			hours:    reservation.OpeningHours{Opens: 12 * time.Hour, Closes: 23 * time.Hour, Location: amsterdam},
+			date:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			opensAt:  time.Date(2025, 1, 1, 12, 0, 0, 0, amsterdam),
+			closesAt: time.Date(2025, 1, 1, 23, 0, 0, 0, amsterdam),
+		},
+		{
+			name:     "closing after midnight",
+			hours:    reservation.OpeningHours{Opens: 18 * time.Hour, Closes: 2*time.Hour + 30*time.Minute, Location: amsterdam},
+			date:     time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+			opensAt:  time.Date(2025, 1, 31, 18, 0, 0, 0, amsterdam),
+			closesAt: time.Date(2025, 2, 1, 2, 30, 0, 0, amsterdam),
+		},
+		{
+			name:     "around the clock without a location",
+			hours:    reservation.OpeningHours{},
+			date:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			opensAt:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			closesAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// the day daylight saving time starts is an hour shorter, the wall clock times are kept
+			name:     "daylight saving time",
+			hours:    reservation.OpeningHours{Opens: 0, Closes: 23 * time.Hour, Location: amsterdam},
+			date:     time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC),
+			opensAt:  time.Date(2025, 3, 30, 0, 0, 0, 0, amsterdam),
+			closesAt: time.Date(2025, 3, 30, 23, 0, 0, 0, amsterdam),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opensAt, closesAt := tc.hours.Day(tc.date)
+			require.True(t, tc.opensAt.Equal(opensAt), "opens at %s", opensAt)
+			require.True(t, tc.closesAt.Equal(closesAt), "closes at %s", closesAt)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package reservation
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+)
+
+// PriceLine is the amount a pricing rule adds to, or takes off, the price of a reservation
+type PriceLine struct {
+	Rule        string      `json:"rule"`
+	Description string      `json:"description"`
+	Amount      money.Money `json:"amount"`
+}
+
+// PriceBreakdown is the lines of the pricing rules applied to a reservation, its price is their total
+type PriceBreakdown []PriceLine
+
+// Total returns the sum of the amounts of all the lines
+func (b PriceBreakdown) Total() money.Money {
+	var total money.Money
+	for _, line := range b {
+		total = total.Add(line.Amount)
+	}
+	return total
+}
+
+// Value stores the breakdown as JSON
+func (b PriceBreakdown) Value() (driver.Value, error) {
+	if b == nil {
+		return "[]", nil
+	}
+
+	value, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	return string(value), nil
+}
+
+// Scan reads the breakdown from JSON
+func (b *PriceBreakdown) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*b = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, b)
+	case string:
+		return json.Unmarshal([]byte(v), b)
+	default:
+		return fmt.Errorf("can not scan %T into a price breakdown", value)
+	}
+}
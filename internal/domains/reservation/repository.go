@@ -6,6 +6,12 @@ import (
 )
 
 type Repository interface {
-	BookTable(ctx context.Context, userID int, seatsNeeded int, date time.Time) (*Reservation, error)
-	CancelReservation(ctx context.Context, reservationID int) error
+	BookTable(ctx context.Context, userID int, seatsNeeded int, startAt time.Time, duration time.Duration) (*Reservation, error)
+	ModifyReservation(ctx context.Context, reservationID int, userID int, modification Modification) (*Reservation, error)
+	CancelReservation(ctx context.Context, reservationID int, userID int, canCancelAny bool, reason string) error
+	UpdateStatus(ctx context.Context, reservationID int, status Status) (*Reservation, error)
+	FindAvailability(ctx context.Context, seatsNeeded int, from, to time.Time, duration, interval time.Duration) ([]Availability, error)
+	FindByID(ctx context.Context, reservationID int) (*Reservation, error)
+	ListByUser(ctx context.Context, userID int, filter ListFilter) ([]Reservation, int, error)
+	ReassignTable(ctx context.Context, tableID uint) ([]Reservation, error)
 }
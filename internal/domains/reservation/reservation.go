@@ -1,12 +1,42 @@
 package reservation
 
-import "time"
+import (
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+)
 
 type Reservation struct {
-	ID         int       `gorm:"type:bigserial;primaryKey"`
-	UserID     uint      `gorm:"type:int,NOT NULL"`
-	TableID    uint      `gorm:"type:int,NOT NULL"`
-	SeatsCount int       `gorm:"type:int,NOT NULL"`
-	Price      float64   `gorm:"type:number,not null"`
-	Date       time.Time `gorm:"type:timestamp,NOT NULL"`
+	ID         int         `gorm:"type:bigserial;primaryKey"`
+	UserID     uint        `gorm:"type:int,NOT NULL"`
+	TableID    uint        `gorm:"type:int,NOT NULL"`
+	SeatsCount int         `gorm:"type:int,NOT NULL"`
+	Price      money.Money `gorm:"embedded;embeddedPrefix:price_"`
+	StartAt    time.Time   `gorm:"type:timestamptz,NOT NULL"`
+	EndAt      time.Time   `gorm:"type:timestamptz,NOT NULL"`
+	Status     Status      `gorm:"type:varchar,NOT NULL"`
+	// PriceBreakdown is the pricing rules applied to the reservation, their total is the price before tax and
+	// service charge, Totals itemizes the price and Price is its gross
+	PriceBreakdown PriceBreakdown `gorm:"type:jsonb,NOT NULL"`
+	Totals         Totals         `gorm:"embedded"`
+	// Tables are all the tables taken by the reservation, TableID is the first one
+	Tables []ReservationTable `gorm:"foreignKey:ReservationID"`
+}
+
+// Duration returns how long the table is occupied by the reservation
+func (r Reservation) Duration() time.Duration {
+	return r.EndAt.Sub(r.StartAt)
+}
+
+// TableIDs returns the IDs of all the tables taken by the reservation
+func (r Reservation) TableIDs() []uint {
+	if len(r.Tables) == 0 {
+		return []uint{r.TableID}
+	}
+
+	ids := make([]uint, 0, len(r.Tables))
+	for _, t := range r.Tables {
+		ids = append(ids, t.TableID)
+	}
+	return ids
 }
@@ -0,0 +1,13 @@
+package reservation
+
+// ReservationTable is a table taken by a reservation and the seats taken at it
+type ReservationTable struct {
+	ReservationID int  `gorm:"type:int;primaryKey"`
+	TableID       uint `gorm:"type:int;primaryKey"`
+	SeatsCount    int  `gorm:"type:int,NOT NULL"`
+}
+
+// TableName returns the table name
+func (t ReservationTable) TableName() string {
+	return "reservation_tables"
+}
@@ -0,0 +1,60 @@
+package reservation
+
+// Status is the lifecycle state of a reservation
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusConfirmed Status = "confirmed"
+	StatusSeated    Status = "seated"
+	StatusCompleted Status = "completed"
+	StatusCancelled Status = "cancelled"
+	StatusNoShow    Status = "no_show"
+)
+
+// transitions holds the statuses a reservation can move to from each status
+var transitions = map[Status][]Status{
+	StatusPending:   {StatusConfirmed, StatusCancelled},
+	StatusConfirmed: {StatusSeated, StatusCancelled, StatusNoShow},
+	StatusSeated:    {StatusCompleted},
+}
+
+// ActiveStatuses returns the statuses of reservations that occupy their table
+func ActiveStatuses() []Status {
+	return []Status{StatusPending, StatusConfirmed, StatusSeated}
+}
+
+// IsActive reports whether a reservation in this status occupies its table
+func (s Status) IsActive() bool {
+	for _, active := range ActiveStatuses() {
+		if s == active {
+			return true
+		}
+	}
+	return false
+}
+
+// ModifiableStatuses returns the statuses of reservations whose party size, time slot and tables can still change
+func ModifiableStatuses() []Status {
+	return []Status{StatusPending, StatusConfirmed}
+}
+
+// IsModifiable reports whether the party size and time slot of a reservation in this status can still change
+func (s Status) IsModifiable() bool {
+	for _, modifiable := range ModifiableStatuses() {
+		if s == modifiable {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a reservation can move from s to next
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package reservation_test
+
+import (
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCanTransitionTo(t *testing.T) {
+	testCases := []struct {
+		from    reservation.Status
+		to      reservation.Status
+		allowed bool
+	}{
+		{reservation.StatusPending, reservation.StatusConfirmed, true},
+		{reservation.StatusPending, reservation.StatusCancelled, true},
+		{reservation.StatusPending, reservation.StatusSeated, false},
+		{reservation.StatusConfirmed, reservation.StatusSeated, true},
+		{reservation.StatusConfirmed, reservation.StatusNoShow, true},
+		{reservation.StatusConfirmed, reservation.StatusCancelled, true},
+		{reservation.StatusConfirmed, reservation.StatusCompleted, false},
+		{reservation.StatusSeated, reservation.StatusCompleted, true},
+		{reservation.StatusSeated, reservation.StatusCancelled, false},
+		{reservation.StatusCompleted, reservation.StatusConfirmed, false},
+		{reservation.StatusCancelled, reservation.StatusConfirmed, false},
+		{reservation.StatusNoShow, reservation.StatusSeated, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.from)+"->"+string(tc.to), func(t *testing.T) {
+			require.Equal(t, tc.allowed, tc.from.CanTransitionTo(tc.to))
+		})
+	}
+}
+
+func TestStatusIsActive(t *testing.T) {
+	require.True(t, reservation.StatusPending.IsActive())
+	require.True(t, reservation.StatusConfirmed.IsActive())
+	require.True(t, reservation.StatusSeated.IsActive())
+	require.False(t, reservation.StatusCompleted.IsActive())
+	require.False(t, reservation.StatusCancelled.IsActive())
+	require.False(t, reservation.StatusNoShow.IsActive())
+}
+
+func TestStatusIsModifiable(t *testing.T) {
+	require.True(t, reservation.StatusPending.IsModifiable())
+	require.True(t, reservation.StatusConfirmed.IsModifiable())
+	require.False(t, reservation.StatusSeated.IsModifiable())
+	require.False(t, reservation.StatusCompleted.IsModifiable())
+	require.False(t, reservation.StatusCancelled.IsModifiable())
+	require.False(t, reservation.StatusNoShow.IsModifiable())
+}
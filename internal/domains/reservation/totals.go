@@ -0,0 +1,15 @@
+package reservation
+
+import "github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+
+// Totals is the itemized price of a reservation. Net is the price before tax and service charge, Gross is what
+// the guest pays, i.e. Net + ServiceCharge + Tax, and the rates are kept so invoices can be reproduced.
+type Totals struct {
+	Net               money.Money `gorm:"embedded;embeddedPrefix:net_"`
+	ServiceCharge     money.Money `gorm:"embedded;embeddedPrefix:service_charge_"`
+	Tax               money.Money `gorm:"embedded;embeddedPrefix:tax_"`
+	Gross             money.Money `gorm:"embedded;embeddedPrefix:gross_"`
+	TaxRate           float64     `gorm:"type:numeric,NOT NULL"`
+	TaxInclusive      bool        `gorm:"type:boolean,NOT NULL"`
+	ServiceChargeRate float64     `gorm:"type:numeric,NOT NULL"`
+}
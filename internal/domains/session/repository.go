@@ -0,0 +1,15 @@
+package session
+
+import "context"
+
+type Repository interface {
+	CreateSession(ctx context.Context, session *Session) error
+	FindByRefreshToken(ctx context.Context, refreshTokenHash string) (*Session, error)
+	RotateRefreshToken(ctx context.Context, session *Session) error
+	RevokeSession(ctx context.Context, sessionID int) error
+	RevokeUserSessions(ctx context.Context, userID int) ([]Session, error)
+	FindByID(ctx context.Context, sessionID int) (*Session, error)
+	ListActiveUserSessions(ctx context.Context, userID int) ([]Session, error)
+	RevokeOtherUserSessions(ctx context.Context, userID int, sessionID int) ([]Session, error)
+	TouchSession(ctx context.Context, sessionID int, userID int, ipAddress string) error
+}
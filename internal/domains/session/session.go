@@ -0,0 +1,44 @@
+package session
+
+import "time"
+
+// Session is a login of a user, kept alive by exchanging its refresh token for a new one
+type Session struct {
+	ID     int `gorm:"type:bigserial;primaryKey"`
+	UserID int `gorm:"type:bigint,NOT NULL"`
+	// RefreshTokenHash is the hash of the only refresh token of the session that can be exchanged
+	RefreshTokenHash string `gorm:"type:varchar,NOT NULL"`
+	// PreviousRefreshTokenHash is the hash of the refresh token exchanged last, it is kept among the exchanged
+	// refresh tokens of the session when the new one is stored
+	PreviousRefreshTokenHash string `gorm:"type:varchar,NOT NULL"`
+	// AccessTokenID is the jti of the latest access token issued for the session
+	AccessTokenID        string    `gorm:"type:varchar,NOT NULL"`
+	AccessTokenExpiresAt time.Time `gorm:"type:timestamptz,NOT NULL"`
+	ExpiresAt            time.Time `gorm:"type:timestamptz,NOT NULL"`
+	// TwoFactor tells whether the login of the session was confirmed with a second factor
+	TwoFactor bool `gorm:"type:boolean,NOT NULL"`
+	// UserAgent is the client the session was started with, IPAddress the address it was used from last
+	UserAgent  string     `gorm:"type:varchar,NOT NULL"`
+	IPAddress  string     `gorm:"type:varchar,NOT NULL"`
+	LastSeenAt time.Time  `gorm:"type:timestamptz,NOT NULL"`
+	RevokedAt  *time.Time `gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"type:timestamptz"`
+	UpdatedAt  time.Time  `gorm:"type:timestamptz"`
+}
+
+// IsActive reports whether the refresh token of the session can still be exchanged at the given time
+func (s Session) IsActive(at time.Time) bool {
+	return s.RevokedAt == nil && at.Before(s.ExpiresAt)
+}
+
+// ExchangedRefreshToken is a refresh token a session exchanged already, presenting it again means it leaked
+type ExchangedRefreshToken struct {
+	TokenHash   string    `gorm:"type:varchar;primaryKey"`
+	SessionID   int       `gorm:"type:bigint,NOT NULL"`
+	ExchangedAt time.Time `gorm:"type:timestamptz,NOT NULL"`
+}
+
+// TableName returns the table name
+func (t ExchangedRefreshToken) TableName() string {
+	return "exchanged_refresh_tokens"
+}
@@ -0,0 +1,20 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionIsActive(t *testing.T) {
+	now := time.Now()
+
+	s := session.Session{ExpiresAt: now.Add(time.Hour)}
+	require.True(t, s.IsActive(now))
+	require.False(t, s.IsActive(now.Add(time.Hour)))
+
+	s.RevokedAt = &now
+	require.False(t, s.IsActive(now))
+}
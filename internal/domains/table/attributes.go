@@ -0,0 +1,38 @@
+package table
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Attributes are free form features of a table, e.g. "window" or "wheelchair_accessible"
+type Attributes []string
+
+// Value stores the attributes as JSON
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+
+	value, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(value), nil
+}
+
+// Scan reads the attributes from JSON
+func (a *Attributes) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("can not scan %T into table attributes", value)
+	}
+}
@@ -0,0 +1,13 @@
+package table
+
+import "errors"
+
+var (
+	ErrTableNotFound              = errors.New("table not found")
+	ErrTableRetired               = errors.New("table is retired")
+	ErrTableHasFutureReservations = errors.New("table has future reservations, reassign them first")
+	ErrTableHasSeatedParty        = errors.New("table has a seated party, wait until it leaves")
+	ErrSettingsNotFound           = errors.New("settings not found")
+	ErrSettingsAlreadyScheduled   = errors.New("settings are already scheduled to take effect at that time")
+	ErrSettingsAlreadyEffective   = errors.New("settings have already taken effect")
+)
@@ -0,0 +1,22 @@
+package table
+
+// Group is a set of tables that can be joined to host a party no single table fits
+type Group struct {
+	ID     int     `gorm:"type:bigserial;primaryKey"`
+	Name   string  `gorm:"type:varchar,NOT NULL"`
+	Tables []Table `gorm:"many2many:table_group_tables;"`
+}
+
+// TableName returns the table name
+func (g Group) TableName() string {
+	return "table_groups"
+}
+
+// SeatsCount returns the seats of all the tables of the group
+func (g Group) SeatsCount() int {
+	seats := 0
+	for _, t := range g.Tables {
+		seats += t.SeatsCount
+	}
+	return seats
+}
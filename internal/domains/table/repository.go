@@ -1,9 +1,21 @@
 package table
 
-import "context"
+import (
+	"context"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+)
 
 type Repository interface {
 	CreateTable(ctx context.Context, table *Table) error
 	GetTotalCount(ctx context.Context) int
-	CreateTableSettings(ctx context.Context, seatPrice int) error
+	CreateTableSettings(ctx context.Context, seatPrice money.Money) error
+	CreateGroup(ctx context.Context, group *Group) error
+	ListTables(ctx context.Context, includeRetired bool) ([]Table, error)
+	FindTableByID(ctx context.Context, tableID int) (*Table, error)
+	UpdateTable(ctx context.Context, table *Table) error
+	RetireTable(ctx context.Context, tableID int) (*Table, error)
+	ListSettings(ctx context.Context) (SettingsHistory, error)
+	ScheduleSettings(ctx context.Context, settings *Settings) error
+	DeleteScheduledSettings(ctx context.Context, settingsID int) error
 }
@@ -1,12 +1,39 @@
 package table
 
-// Settings struct
+import (
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+)
+
+// Settings is a version of the restaurant settings, it is in effect from EffectiveFrom until the next version
+// takes effect, so future prices can be scheduled
 type Settings struct {
-	ID        int `gorm:"type:bigserial;primaryKey"`
-	SeatPrice int `gorm:"type:int,NOT NULL"`
+	ID            int         `gorm:"type:bigserial;primaryKey"`
+	SeatPrice     money.Money `gorm:"embedded;embeddedPrefix:seat_price_"`
+	EffectiveFrom time.Time   `gorm:"type:timestamptz;uniqueIndex,NOT NULL"`
+	CreatedAt     time.Time   `gorm:"type:timestamptz,NOT NULL"`
 }
 
 // TableName returns the table name
 func (t Settings) TableName() string {
 	return "table_settings"
 }
+
+// IsEffective reports whether the settings have taken effect at the given time
+func (t Settings) IsEffective(at time.Time) bool {
+	return !t.EffectiveFrom.After(at)
+}
+
+// SettingsHistory is the versions of the settings ordered by the time they take effect
+type SettingsHistory []Settings
+
+// At returns the settings in effect at the given time
+func (h SettingsHistory) At(at time.Time) (Settings, error) {
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].IsEffective(at) {
+			return h[i], nil
+		}
+	}
+	return Settings{}, ErrSettingsNotFound
+}
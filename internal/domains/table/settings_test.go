@@ -0,0 +1,45 @@
+package table_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsHistoryAt(t *testing.T) {
+	now := time.Now()
+	history := table.SettingsHistory{
+		{ID: 1, SeatPrice: money.New(1000, "USD"), EffectiveFrom: time.Unix(0, 0)},
+		{ID: 2, SeatPrice: money.New(1200, "USD"), EffectiveFrom: now.AddDate(0, 0, -1)},
+		{ID: 3, SeatPrice: money.New(1500, "USD"), EffectiveFrom: now.AddDate(0, 1, 0)},
+	}
+
+	testCases := []struct {
+		name string
+		at   time.Time
+		id   int
+	}{
+		{"before the latest change", now.AddDate(0, 0, -2), 1},
+		{"now", now, 2},
+		{"right before the scheduled change", now.AddDate(0, 1, 0).Add(-time.Second), 2},
+		{"when the scheduled change takes effect", now.AddDate(0, 1, 0), 3},
+		{"after the scheduled change", now.AddDate(1, 0, 0), 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings, err := history.At(tc.at)
+			require.NoError(t, err)
+			require.Equal(t, tc.id, settings.ID)
+		})
+	}
+
+	_, err := history.At(time.Unix(0, 0).Add(-time.Second))
+	require.ErrorIs(t, err, table.ErrSettingsNotFound)
+
+	_, err = table.SettingsHistory{}.At(now)
+	require.ErrorIs(t, err, table.ErrSettingsNotFound)
+}
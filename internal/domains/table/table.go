@@ -1,6 +1,18 @@
 package table
 
+import "time"
+
 type Table struct {
-	ID         int `gorm:"type:bigserial;primaryKey"`
-	SeatsCount int `gorm:"type:int,NOT NULL"`
+	ID         int        `gorm:"type:bigserial;primaryKey"`
+	SeatsCount int        `gorm:"type:int,NOT NULL"`
+	Name       string     `gorm:"type:varchar,NOT NULL"`
+	Zone       string     `gorm:"type:varchar,NOT NULL"`
+	Attributes Attributes `gorm:"type:jsonb,NOT NULL"`
+	// RetiredAt is when the table was taken out of service, nil while it can be booked
+	RetiredAt *time.Time `gorm:"type:timestamptz"`
+}
+
+// IsRetired reports whether the table is out of service
+func (t Table) IsRetired() bool {
+	return t.RetiredAt != nil
 }
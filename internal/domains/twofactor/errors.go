@@ -0,0 +1,10 @@
+package twofactor
+
+import "errors"
+
+var (
+	ErrTwoFactorNotFound = errors.New("two-factor authentication is not set up")
+	ErrAlreadyEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrInvalidCode       = errors.New("two-factor authentication code is invalid")
+	ErrCodeAlreadyUsed   = errors.New("two-factor authentication code was already used")
+)
@@ -0,0 +1,18 @@
+package twofactor
+
+import "github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+
+// Policy is which roles have to confirm their logins with a second factor, the zero Policy requires it of none
+type Policy struct {
+	RequiredRoles []user.Role
+}
+
+// IsRequired reports whether users of the role have to confirm their logins with a second factor
+func (p Policy) IsRequired(role user.Role) bool {
+	for _, required := range p.RequiredRoles {
+		if required == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package twofactor
+
+import "context"
+
+type Repository interface {
+	FindByUserID(ctx context.Context, userID int) (*TwoFactor, error)
+	CreateEnrolment(ctx context.Context, twoFactor *TwoFactor) error
+	Enable(ctx context.Context, twoFactor *TwoFactor, step int64, backupCodeHashes []string) error
+	UseStep(ctx context.Context, userID int, step int64) error
+	UseBackupCode(ctx context.Context, userID int, codeHash string) error
+	ReplaceBackupCodes(ctx context.Context, userID int, codeHashes []string) error
+	Disable(ctx context.Context, userID int) error
+}
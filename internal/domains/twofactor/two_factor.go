@@ -0,0 +1,39 @@
+package twofactor
+
+import "time"
+
+// TwoFactor is the authenticator app a user confirms logins with, it is pending until the user confirms it with a
+// first code
+type TwoFactor struct {
+	UserID int `gorm:"type:bigint;primaryKey"`
+	// Secret is the base32 encoded TOTP secret shared with the authenticator app
+	Secret    string     `gorm:"type:varchar,NOT NULL"`
+	EnabledAt *time.Time `gorm:"type:timestamptz"`
+	// LastUsedStep is the time step of the last code used, codes of it or earlier steps are refused
+	LastUsedStep int64     `gorm:"type:bigint,NOT NULL"`
+	CreatedAt    time.Time `gorm:"type:timestamptz"`
+}
+
+// TableName returns the table name
+func (t TwoFactor) TableName() string {
+	return "two_factors"
+}
+
+// IsEnabled reports whether the user confirmed the authenticator app, logins need a code from then on
+func (t TwoFactor) IsEnabled() bool {
+	return t.EnabledAt != nil
+}
+
+// BackupCode is a single-use code that replaces a code of the authenticator app, e.g. when the phone is lost
+type BackupCode struct {
+	ID        int        `gorm:"type:bigserial;primaryKey"`
+	UserID    int        `gorm:"type:bigint,NOT NULL"`
+	CodeHash  string     `gorm:"type:varchar,NOT NULL"`
+	UsedAt    *time.Time `gorm:"type:timestamptz"`
+	CreatedAt time.Time  `gorm:"type:timestamptz"`
+}
+
+// TableName returns the table name
+func (c BackupCode) TableName() string {
+	return "backup_codes"
+}
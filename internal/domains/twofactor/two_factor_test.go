@@ -0,0 +1,29 @@
+package twofactor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorIsEnabled(t *testing.T) {
+	tf := twofactor.TwoFactor{Secret: "JBSWY3DPEHPK3PXP"}
+	require.False(t, tf.IsEnabled())
+
+	now := time.Now()
+	tf.EnabledAt = &now
+	require.True(t, tf.IsEnabled())
+}
+
+func TestPolicyIsRequired(t *testing.T) {
+	policy := twofactor.Policy{RequiredRoles: []user.Role{user.RoleManager, user.RoleAdmin}}
+	require.False(t, policy.IsRequired(user.RoleGuest))
+	require.False(t, policy.IsRequired(user.RoleHost))
+	require.True(t, policy.IsRequired(user.RoleManager))
+	require.True(t, policy.IsRequired(user.RoleAdmin))
+
+	require.False(t, twofactor.Policy{}.IsRequired(user.RoleAdmin))
+}
@@ -0,0 +1,56 @@
+package user
+
+import (
+	"sort"
+	"time"
+)
+
+// EmailVerification lets a user confirm an email address once, until it expires, with the token whose hash it keeps.
+// It only verifies the address it was sent to, not one the user changed to since.
+type EmailVerification struct {
+	ID        int        `gorm:"type:bigserial;primaryKey"`
+	UserID    int        `gorm:"type:bigint,NOT NULL"`
+	Email     string     `gorm:"type:varchar,NOT NULL"`
+	TokenHash string     `gorm:"type:varchar,NOT NULL"`
+	ExpiresAt time.Time  `gorm:"type:timestamptz,NOT NULL"`
+	UsedAt    *time.Time `gorm:"type:timestamptz"`
+	CreatedAt time.Time  `gorm:"type:timestamptz"`
+}
+
+// TableName returns the table name
+func (v EmailVerification) TableName() string {
+	return "email_verifications"
+}
+
+// ResendPolicy limits how often verification emails are sent to a user: Interval apart and at most Limit of them
+// within Window
+type ResendPolicy struct {
+	Interval time.Duration
+	Limit    int
+	Window   time.Duration
+}
+
+// RetryAfter returns how long to wait before another verification email can be sent to a user who was sent ones
+// at the given times, zero when one can be sent now
+func (p ResendPolicy) RetryAfter(sentAt []time.Time, now time.Time) time.Duration {
+	sorted := append([]time.Time(nil), sentAt...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var wait time.Duration
+	if len(sorted) > 0 {
+		wait = max(wait, sorted[len(sorted)-1].Add(p.Interval).Sub(now))
+	}
+
+	var recent []time.Time
+	for _, at := range sorted {
+		if at.After(now.Add(-p.Window)) {
+			recent = append(recent, at)
+		}
+	}
+	// the oldest emails within the window have to fall out of it first
+	if p.Limit > 0 && len(recent) >= p.Limit {
+		wait = max(wait, recent[len(recent)-p.Limit].Add(p.Window).Sub(now))
+	}
+
+	return max(wait, 0)
+}
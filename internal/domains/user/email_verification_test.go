@@ -0,0 +1,40 @@
+package user_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResendPolicyRetryAfter(t *testing.T) {
+	policy := user.ResendPolicy{Interval: time.Minute, Limit: 3, Window: time.Hour}
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		sentAt []time.Time
+		want   time.Duration
+	}{
+		{"never sent", nil, 0},
+		{"sent a while ago", []time.Time{now.Add(-2 * time.Minute)}, 0},
+		{"sent moments ago", []time.Time{now.Add(-20 * time.Second)}, 40 * time.Second},
+		{
+			name:   "limit reached within the window",
+			sentAt: []time.Time{now.Add(-10 * time.Minute), now.Add(-50 * time.Minute), now.Add(-30 * time.Minute)},
+			want:   10 * time.Minute,
+		},
+		{
+			name:   "older ones left the window",
+			sentAt: []time.Time{now.Add(-2 * time.Hour), now.Add(-90 * time.Minute), now.Add(-10 * time.Minute)},
+			want:   0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, policy.RetryAfter(tc.sentAt, now))
+		})
+	}
+}
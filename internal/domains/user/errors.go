@@ -5,4 +5,11 @@ import "errors"
 var (
 	ErrUsernameAlreadyExists = errors.New("username already exists")
 	ErrUserNotFound          = errors.New("user not found")
+	ErrEmailAlreadyExists    = errors.New("email already exists")
+	ErrUpcomingReservations  = errors.New("user has upcoming reservations, cancel them first")
+	ErrInvalidResetToken     = errors.New("password reset token is invalid or expired")
+	ErrEmailNotVerified      = errors.New("email address is not verified")
+	ErrInvalidVerifyToken    = errors.New("email verification token is invalid or expired")
+	ErrEmailAlreadyVerified  = errors.New("email address is already verified")
+	ErrNoEmail               = errors.New("user has no email address")
 )
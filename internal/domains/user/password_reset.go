@@ -0,0 +1,18 @@
+package user
+
+import "time"
+
+// PasswordReset lets a user choose a new password once, until it expires, with the token whose hash it keeps
+type PasswordReset struct {
+	ID        int        `gorm:"type:bigserial;primaryKey"`
+	UserID    int        `gorm:"type:bigint,NOT NULL"`
+	TokenHash string     `gorm:"type:varchar,NOT NULL"`
+	ExpiresAt time.Time  `gorm:"type:timestamptz,NOT NULL"`
+	UsedAt    *time.Time `gorm:"type:timestamptz"`
+	CreatedAt time.Time  `gorm:"type:timestamptz"`
+}
+
+// TableName returns the table name
+func (r PasswordReset) TableName() string {
+	return "password_resets"
+}
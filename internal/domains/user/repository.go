@@ -1,8 +1,22 @@
 package user
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	Register(ctx context.Context, user *User) error
 	FindByUsername(ctx context.Context, username string) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindByID(ctx context.Context, id int) (*User, error)
+	UpdateRole(ctx context.Context, id int, role Role) (*User, error)
+	UpdateProfile(ctx context.Context, user *User) error
+	UpdatePassword(ctx context.Context, id int, password string) error
+	DeleteUser(ctx context.Context, id int) error
+	CreatePasswordReset(ctx context.Context, reset *PasswordReset) error
+	ResetPassword(ctx context.Context, tokenHash string, password string) (*User, error)
+	CreateEmailVerification(ctx context.Context, verification *EmailVerification) error
+	ListEmailVerificationsSince(ctx context.Context, userID int, since time.Time) ([]EmailVerification, error)
+	VerifyEmail(ctx context.Context, tokenHash string) (*User, error)
 }
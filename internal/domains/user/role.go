@@ -0,0 +1,65 @@
+package user
+
+// Role is what a user does at the restaurant, every role can do whatever the roles below it can
+type Role string
+
+const (
+	// RoleGuest books and manages their own reservations
+	RoleGuest Role = "guest"
+	// RoleHost seats guests and manages the reservations of every guest
+	RoleHost Role = "host"
+	// RoleManager manages the tables and settings of the restaurant
+	RoleManager Role = "manager"
+	// RoleAdmin manages the roles of the users
+	RoleAdmin Role = "admin"
+)
+
+// Permission is an action restricted to some roles
+type Permission string
+
+const (
+	PermissionManageReservations Permission = "manage_reservations"
+	PermissionManageTables       Permission = "manage_tables"
+	PermissionManageSettings     Permission = "manage_settings"
+	PermissionManageUsers        Permission = "manage_users"
+)
+
+// roles is every role ordered from the least to the most privileged
+var roles = []Role{RoleGuest, RoleHost, RoleManager, RoleAdmin}
+
+// permissionRoles is the least privileged role granted each permission
+var permissionRoles = map[Permission]Role{
+	PermissionManageReservations: RoleHost,
+	PermissionManageTables:       RoleManager,
+	PermissionManageSettings:     RoleManager,
+	PermissionManageUsers:        RoleAdmin,
+}
+
+// Roles returns every role ordered from the least to the most privileged
+func Roles() []Role {
+	return append([]Role(nil), roles...)
+}
+
+// IsValid reports whether the role is a known one
+func (r Role) IsValid() bool {
+	return r.rank() >= 0
+}
+
+// Can reports whether the role is granted the permission
+func (r Role) Can(permission Permission) bool {
+	minimum, ok := permissionRoles[permission]
+	if !ok {
+		return false
+	}
+	return r.IsValid() && r.rank() >= minimum.rank()
+}
+
+// rank returns the position of the role from the least privileged one, -1 for unknown roles
+func (r Role) rank() int {
+	for i, role := range roles {
+		if role == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,50 @@
+package user_test
+
+import (
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleCan(t *testing.T) {
+	testCases := []struct {
+		role       user.Role
+		permission user.Permission
+		allowed    bool
+	}{
+		{user.RoleGuest, user.PermissionManageReservations, false},
+		{user.RoleGuest, user.PermissionManageTables, false},
+		{user.RoleGuest, user.PermissionManageSettings, false},
+		{user.RoleGuest, user.PermissionManageUsers, false},
+		{user.RoleHost, user.PermissionManageReservations, true},
+		{user.RoleHost, user.PermissionManageTables, false},
+		{user.RoleHost, user.PermissionManageSettings, false},
+		{user.RoleHost, user.PermissionManageUsers, false},
+		{user.RoleManager, user.PermissionManageReservations, true},
+		{user.RoleManager, user.PermissionManageTables, true},
+		{user.RoleManager, user.PermissionManageSettings, true},
+		{user.RoleManager, user.PermissionManageUsers, false},
+		{user.RoleAdmin, user.PermissionManageReservations, true},
+		{user.RoleAdmin, user.PermissionManageTables, true},
+		{user.RoleAdmin, user.PermissionManageSettings, true},
+		{user.RoleAdmin, user.PermissionManageUsers, true},
+		{user.Role("owner"), user.PermissionManageReservations, false},
+		{user.Role(""), user.PermissionManageReservations, false},
+		{user.RoleAdmin, user.Permission("unknown"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.role)+" "+string(tc.permission), func(t *testing.T) {
+			require.Equal(t, tc.allowed, tc.role.Can(tc.permission))
+		})
+	}
+}
+
+func TestRoleIsValid(t *testing.T) {
+	for _, role := range user.Roles() {
+		require.True(t, role.IsValid())
+	}
+	require.False(t, user.Role("owner").IsValid())
+	require.False(t, user.Role("").IsValid())
+}
@@ -1,10 +1,30 @@
 package user
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
-	ID        int       `gorm:"type:bigserial;primaryKey"`
-	Username  string    `gorm:"type:varchar;uniqueIndex,NOT NULL"`
-	Password  string    `gorm:"type:varchar,NOT NULL"`
-	CreatedAt time.Time `gorm:"type:timestamp"`
+	ID       int    `gorm:"type:bigserial;primaryKey"`
+	Username string `gorm:"type:varchar;uniqueIndex,NOT NULL"`
+	Password string `gorm:"type:varchar,NOT NULL"`
+	Role     Role   `gorm:"type:varchar;default:guest"`
+	// Name, Email, Phone and Language are the contact details of the user, empty until the user sets them
+	Name     string `gorm:"type:varchar,NOT NULL"`
+	Email    string `gorm:"type:varchar,NOT NULL"`
+	Phone    string `gorm:"type:varchar,NOT NULL"`
+	Language string `gorm:"type:varchar,NOT NULL"`
+	// EmailVerifiedAt is when the user confirmed owning the email, it is cleared when the email changes
+	EmailVerifiedAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt       time.Time  `gorm:"type:timestamp"`
+	// DeletedAt is when the account was deleted, deleted accounts are kept without their details for the
+	// reservations they made
+	DeletedAt gorm.DeletedAt `gorm:"type:timestamptz;index"`
+}
+
+// IsEmailVerified reports whether the user confirmed owning the email of the account
+func (u User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
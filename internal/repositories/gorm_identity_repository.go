@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewGormIdentityRepository(db *gorm.DB) identity.Repository {
+	return &GormIdentityRepository{
+		db: db,
+	}
+}
+
+// CreateLoginState stores a new sign-in started at a provider
+func (r *GormIdentityRepository) CreateLoginState(ctx context.Context, state *identity.LoginState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+// ConsumeLoginState removes the sign-in of the state hash and returns it unless it expired, so that it can only be
+// completed once. The expired sign-ins nobody completed are removed along with it.
+func (r *GormIdentityRepository) ConsumeLoginState(ctx context.Context, stateHash string) (*identity.LoginState, error) {
+	var states []identity.LoginState
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state_hash = ? OR expires_at <= ?", stateHash, now).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, state := range states {
+		if state.StateHash == stateHash && state.ExpiresAt.After(now) {
+			return &state, nil
+		}
+	}
+
+	return nil, identity.ErrInvalidState
+}
+
+// FindIdentity finds the identity a provider knows by the subject
+func (r *GormIdentityRepository) FindIdentity(ctx context.Context, provider string, subject string) (*identity.Identity, error) {
+	var i identity.Identity
+	result := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&i)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, identity.ErrIdentityNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &i, nil
+}
+
+// ListUserIdentities lists the identities linked to the account of a user
+func (r *GormIdentityRepository) ListUserIdentities(ctx context.Context, userID int) ([]identity.Identity, error) {
+	var identities []identity.Identity
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("provider").
+		Find(&identities).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// LinkIdentity links an identity to the account of its user, an identity is linked to a single account and an
+// account has a single identity of each provider
+func (r *GormIdentityRepository) LinkIdentity(ctx context.Context, i *identity.Identity) error {
+	if err := r.db.WithContext(ctx).Create(i).Error; err != nil {
+		return linkError(err)
+	}
+
+	return nil
+}
+
+// RegisterUser registers a new user along with the identity the user signed in with
+func (r *GormIdentityRepository) RegisterUser(ctx context.Context, u *user.User, i *identity.Identity) error {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := tx.Create(u).Error; err != nil {
+		tx.Rollback()
+		return registrationError(err)
+	}
+
+	i.UserID = u.ID
+	if err := tx.Create(i).Error; err != nil {
+		tx.Rollback()
+		return linkError(err)
+	}
+
+	return tx.Commit().Error
+}
+
+// linkError tells whether the identity or the provider is already linked when an identity can not be stored
+func linkError(err error) error {
+	if !isUniqueViolation(err) {
+		return err
+	}
+	if violatedConstraint(err) == "user_identities_user_id_provider_key" {
+		return identity.ErrProviderAlreadyLinked
+	}
+	return identity.ErrIdentityAlreadyLinked
+}
@@ -0,0 +1,88 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/repositories"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginStates(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	identityRepository := repositories.NewGormIdentityRepository(db)
+	expired := identity.LoginState{
+		StateHash: faker.UUIDDigit(), Provider: "google", Nonce: "nonce", CodeVerifier: "verifier",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+	pending := identity.LoginState{
+		StateHash: faker.UUIDDigit(), Provider: "google", Nonce: "nonce", CodeVerifier: "verifier",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	for _, state := range []*identity.LoginState{&expired, &pending} {
+		require.NoError(t, identityRepository.CreateLoginState(ctx, state))
+	}
+
+	_, err := identityRepository.ConsumeLoginState(ctx, expired.StateHash)
+	require.ErrorIs(t, err, identity.ErrInvalidState)
+
+	state, err := identityRepository.ConsumeLoginState(ctx, pending.StateHash)
+	require.NoError(t, err)
+	require.Equal(t, pending.ID, state.ID)
+	require.Equal(t, "verifier", state.CodeVerifier)
+	require.False(t, state.IsLinking())
+
+	// a sign-in is completed once
+	_, err = identityRepository.ConsumeLoginState(ctx, pending.StateHash)
+	require.ErrorIs(t, err, identity.ErrInvalidState)
+}
+
+func TestIdentities(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	userRepository := repositories.NewGormUserRepository(db)
+	identityRepository := repositories.NewGormIdentityRepository(db)
+
+	verifiedAt := time.Now()
+	u := user.User{Username: "google-1234567890ab", Email: faker.Email(), EmailVerifiedAt: &verifiedAt}
+	googleIdentity := identity.Identity{Provider: "google", Subject: "1234567890", Email: u.Email}
+	require.NoError(t, identityRepository.RegisterUser(ctx, &u, &googleIdentity))
+	require.Equal(t, u.ID, googleIdentity.UserID)
+
+	found, err := identityRepository.FindIdentity(ctx, "google", "1234567890")
+	require.NoError(t, err)
+	require.Equal(t, u.ID, found.UserID)
+	_, err = identityRepository.FindIdentity(ctx, "microsoft", "1234567890")
+	require.ErrorIs(t, err, identity.ErrIdentityNotFound)
+
+	byEmail, err := userRepository.FindByEmail(ctx, u.Email)
+	require.NoError(t, err)
+	require.Equal(t, u.ID, byEmail.ID)
+
+	// the user is not registered when the identity belongs to another account
+	another := user.User{Username: faker.Username(), Email: faker.Email()}
+	require.ErrorIs(t, identityRepository.RegisterUser(ctx, &another, &identity.Identity{Provider: "google", Subject: "1234567890"}), identity.ErrIdentityAlreadyLinked)
+	_, err = userRepository.FindByEmail(ctx, another.Email)
+	require.ErrorIs(t, err, user.ErrUserNotFound)
+
+	require.ErrorIs(t, identityRepository.LinkIdentity(ctx, &identity.Identity{UserID: u.ID, Provider: "google", Subject: "another"}), identity.ErrProviderAlreadyLinked)
+	require.NoError(t, identityRepository.LinkIdentity(ctx, &identity.Identity{UserID: u.ID, Provider: "microsoft", Subject: "abcdef"}))
+
+	identities, err := identityRepository.ListUserIdentities(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, identities, 2)
+	require.Equal(t, "google", identities[0].Provider)
+	require.Equal(t, "microsoft", identities[1].Provider)
+
+	// a deleted account gives up its identities, which can sign up again
+	require.NoError(t, userRepository.DeleteUser(ctx, u.ID))
+	_, err = identityRepository.FindIdentity(ctx, "google", "1234567890")
+	require.ErrorIs(t, err, identity.ErrIdentityNotFound)
+}
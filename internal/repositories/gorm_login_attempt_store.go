@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormLoginAttemptStore is a lockout Store shared by every instance of the application
+type GormLoginAttemptStore struct {
+	db *gorm.DB
+}
+
+func NewGormLoginAttemptStore(db *gorm.DB) lockout.Store {
+	return &GormLoginAttemptStore{
+		db: db,
+	}
+}
+
+// Get returns the attempts of the subject, without any failure when there are none
+func (s *GormLoginAttemptStore) Get(ctx context.Context, kind lockout.Kind, subject string) (lockout.Attempts, error) {
+	var attempts []lockout.Attempts
+	err := s.db.WithContext(ctx).Where("kind = ? AND subject = ?", kind, subject).Limit(1).Find(&attempts).Error
+	if err != nil {
+		return lockout.Attempts{}, err
+	}
+	if len(attempts) == 0 {
+		return lockout.Attempts{Kind: kind, Subject: subject}, nil
+	}
+
+	return attempts[0], nil
+}
+
+// Update changes the attempts of the subject while holding a lock on its row
+func (s *GormLoginAttemptStore) Update(ctx context.Context, kind lockout.Kind, subject string, update func(a *lockout.Attempts)) error {
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	// the row is created first so that there is always a row to lock
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&lockout.Attempts{Kind: kind, Subject: subject, LastFailureAt: time.Now()}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var a lockout.Attempts
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("kind = ? AND subject = ?", kind, subject).
+		First(&a).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	update(&a)
+
+	err = tx.Model(&lockout.Attempts{}).
+		Where("kind = ? AND subject = ?", kind, subject).
+		Updates(map[string]interface{}{
+			"failures":        a.Failures,
+			"last_failure_at": a.LastFailureAt,
+			"locked_until":    a.LockedUntil,
+		}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Delete clears the attempts of the subject
+func (s *GormLoginAttemptStore) Delete(ctx context.Context, kind lockout.Kind, subject string) error {
+	result := s.db.WithContext(ctx).Where("kind = ? AND subject = ?", kind, subject).Delete(&lockout.Attempts{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return lockout.ErrAttemptsNotFound
+	}
+
+	return nil
+}
+
+// List returns the attempts of every subject ordered by kind and subject
+func (s *GormLoginAttemptStore) List(ctx context.Context) ([]lockout.Attempts, error) {
+	var attempts []lockout.Attempts
+	err := s.db.WithContext(ctx).Order("kind, subject").Find(&attempts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+// Prune clears the attempts whose last failure is before the given time
+func (s *GormLoginAttemptStore) Prune(ctx context.Context, before time.Time) error {
+	return s.db.WithContext(ctx).Where("last_failure_at < ?", before).Delete(&lockout.Attempts{}).Error
+}
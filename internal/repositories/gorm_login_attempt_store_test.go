@@ -0,0 +1,61 @@
+package repositories_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/repositories"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGormLoginAttemptStore(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	store := repositories.NewGormLoginAttemptStore(db)
+
+	a, err := store.Get(ctx, lockout.KindAccount, "alice")
+	require.NoError(t, err)
+	require.Zero(t, a.Failures)
+
+	// concurrent failures are all counted
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, store.Update(ctx, lockout.KindAccount, "alice", func(a *lockout.Attempts) {
+				a.Failures++
+				a.LastFailureAt = time.Now()
+			}))
+		}()
+	}
+	wg.Wait()
+
+	a, err = store.Get(ctx, lockout.KindAccount, "alice")
+	require.NoError(t, err)
+	require.Equal(t, 10, a.Failures)
+
+	lockedUntil := time.Now().Add(time.Hour)
+	require.NoError(t, store.Update(ctx, lockout.KindIP, "192.0.2.1", func(a *lockout.Attempts) {
+		a.Failures++
+		a.LastFailureAt = time.Now().Add(-2 * time.Hour)
+		a.LockedUntil = &lockedUntil
+	}))
+
+	list, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, lockout.KindAccount, list[0].Kind)
+	require.NotNil(t, list[1].LockedUntil)
+
+	require.NoError(t, store.Prune(ctx, time.Now().Add(-time.Hour)))
+	require.ErrorIs(t, store.Delete(ctx, lockout.KindIP, "192.0.2.1"), lockout.ErrAttemptsNotFound)
+	require.NoError(t, store.Delete(ctx, lockout.KindAccount, "alice"))
+
+	list, err = store.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
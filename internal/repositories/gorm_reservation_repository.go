@@ -3,25 +3,40 @@ package repositories
 import (
 	"context"
 	"errors"
+	"sort"
 	"time"
 
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/allocation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/pricing"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormReservationRepository is a repository for reservation operations
 type GormReservationRepository struct {
-	db *gorm.DB
+	db            *gorm.DB
+	allocator     allocation.Allocator
+	pricingEngine *pricing.Engine
 }
 
-// NewGormReservationRepository creates a new instance of GormReservationRepository
-func NewGormReservationRepository(db *gorm.DB) reservation.Repository {
-	return &GormReservationRepository{db: db}
+// NewGormReservationRepository creates a new instance of GormReservationRepository that seats parties
+// at the tables picked by allocator and prices reservations with pricingEngine
+func NewGormReservationRepository(db *gorm.DB, allocator allocation.Allocator, pricingEngine *pricing.Engine) reservation.Repository {
+	return &GormReservationRepository{db: db, allocator: allocator, pricingEngine: pricingEngine}
 }
 
-// BookTable books a table for a user on a specific date
-func (r *GormReservationRepository) BookTable(ctx context.Context, userID int, seatsNeeded int, date time.Time) (*reservation.Reservation, error) {
-	tx := r.db.Begin()
+// BookTable books a table, or a group of joinable tables when no single table fits the party,
+// for a user for the time slot starting at startAt and lasting for duration
+func (r *GormReservationRepository) BookTable(ctx context.Context, userID int, seatsNeeded int, startAt time.Time, duration time.Duration) (*reservation.Reservation, error) {
+	return retryOnConflict(func() (*reservation.Reservation, error) {
+		return r.bookTable(ctx, userID, seatsNeeded, startAt, duration)
+	})
+}
+
+func (r *GormReservationRepository) bookTable(ctx context.Context, userID int, seatsNeeded int, startAt time.Time, duration time.Duration) (*reservation.Reservation, error) {
+	tx := r.db.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -31,52 +46,34 @@ func (r *GormReservationRepository) BookTable(ctx context.Context, userID int, s
 		}
 	}()
 
-	var tableID uint
-	var seatPrice, totalPrice float64
-
-	query := `
-		WITH table_availability AS (
-			SELECT t.id AS table_id, t.seats_count AS total_seats,
-				COALESCE(SUM(r.seats_count), 0) AS reserved_seats,
-				(t.seats_count - COALESCE(SUM(r.seats_count), 0)) AS available_seats
-			FROM tables t
-			LEFT JOIN reservations r 
-				ON t.id = r.table_id AND r.date = ?
-			GROUP BY t.id, t.seats_count
-		),
-		selected_table AS (
-			SELECT table_id FROM table_availability
-			WHERE available_seats >= ? 
-			ORDER BY available_seats ASC 
-			LIMIT 1
-		),
-		seat_price AS (
-			SELECT seat_price FROM table_settings LIMIT 1
-		)
-		SELECT 
-			t.id AS table_id,
-			sp.seat_price,
-			CASE 
-				WHEN ? = t.seats_count THEN (t.seats_count - 1) * sp.seat_price 
-				ELSE ? * sp.seat_price
-			END AS total_price
-		FROM tables t
-		JOIN selected_table st ON t.id = st.table_id
-		JOIN seat_price sp ON true;
-	`
+	endAt := startAt.Add(duration)
+	tables, err := reserveTables(tx, r.allocator, tableRequest{
+		seatsNeeded: seatsNeeded,
+		startAt:     startAt,
+		endAt:       endAt,
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
-	row := tx.Raw(query, date, seatsNeeded, seatsNeeded, seatsNeeded).Row()
-	if err := row.Scan(&tableID, &seatPrice, &totalPrice); err != nil {
+	breakdown, totals, err := r.price(tx, seatsNeeded, startAt, tables)
+	if err != nil {
 		tx.Rollback()
-		return nil, reservation.ErrNoTablesAreAvailable
+		return nil, err
 	}
 
 	newReservation := reservation.Reservation{
-		UserID:     uint(userID),
-		TableID:    tableID,
-		SeatsCount: seatsNeeded,
-		Price:      totalPrice,
-		Date:       date,
+		UserID:         uint(userID),
+		TableID:        tables.tables[0].TableID,
+		SeatsCount:     seatsNeeded,
+		Price:          totals.Gross,
+		StartAt:        startAt,
+		EndAt:          endAt,
+		Status:         reservation.StatusConfirmed,
+		PriceBreakdown: breakdown,
+		Totals:         totals,
+		Tables:         tables.tables,
 	}
 	if err := tx.Create(&newReservation).Error; err != nil {
 		tx.Rollback()
@@ -90,9 +87,16 @@ func (r *GormReservationRepository) BookTable(ctx context.Context, userID int, s
 	return &newReservation, nil
 }
 
-// CancelReservation cancels a reservation by its ID
-func (r *GormReservationRepository) CancelReservation(ctx context.Context, reservationID int) error {
-	tx := r.db.Begin()
+// ModifyReservation changes the party size and time slot of a reservation of userID. The original table is kept
+// when it still fits, otherwise other tables are selected and the reservation is re-priced, all in one transaction.
+func (r *GormReservationRepository) ModifyReservation(ctx context.Context, reservationID int, userID int, modification reservation.Modification) (*reservation.Reservation, error) {
+	return retryOnConflict(func() (*reservation.Reservation, error) {
+		return r.modifyReservation(ctx, reservationID, userID, modification)
+	})
+}
+
+func (r *GormReservationRepository) modifyReservation(ctx context.Context, reservationID int, userID int, modification reservation.Modification) (*reservation.Reservation, error) {
+	tx := r.db.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -103,7 +107,201 @@ func (r *GormReservationRepository) CancelReservation(ctx context.Context, reser
 	}()
 
 	var resv reservation.Reservation
-	if err := tx.First(&resv, reservationID).Error; err != nil {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&resv, reservationID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, reservation.ErrReservationNotFound
+		}
+		return nil, err
+	}
+
+	if int(resv.UserID) != userID {
+		tx.Rollback()
+		return nil, reservation.ErrReservationNotOwned
+	}
+
+	if !resv.Status.IsModifiable() {
+		tx.Rollback()
+		return nil, reservation.ErrReservationNotModifiable
+	}
+
+	seatsNeeded, startAt, duration := resv.SeatsCount, resv.StartAt, resv.Duration()
+	if modification.SeatsCount > 0 {
+		seatsNeeded = modification.SeatsCount
+	}
+	if !modification.StartAt.IsZero() {
+		startAt = modification.StartAt
+	}
+	if modification.Duration > 0 {
+		duration = modification.Duration
+	}
+	endAt := startAt.Add(duration)
+
+	tables, err := reserveTables(tx, r.allocator, tableRequest{
+		seatsNeeded:          seatsNeeded,
+		startAt:              startAt,
+		endAt:                endAt,
+		excludeReservationID: resv.ID,
+		preferredTableID:     resv.TableID,
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	breakdown, totals, err := r.price(tx, seatsNeeded, startAt, tables)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	resv.TableID = tables.tables[0].TableID
+	resv.SeatsCount = seatsNeeded
+	resv.Price = totals.Gross
+	resv.PriceBreakdown = breakdown
+	resv.Totals = totals
+	resv.StartAt = startAt
+	resv.EndAt = endAt
+	if err := tx.Omit("Tables").Save(&resv).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Where("reservation_id = ?", resv.ID).Delete(&reservation.ReservationTable{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	resv.Tables = tables.tables
+	for i := range resv.Tables {
+		resv.Tables[i].ReservationID = resv.ID
+	}
+	if err := tx.Create(&resv.Tables).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &resv, nil
+}
+
+// ReassignTable moves every future reservation off a table to the other tables picked by the allocator, so the
+// table can be shrunk or retired. Reservations keep their price and time slot, seated parties stay where they sit.
+// All the reservations are moved in one transaction, none is moved when any of them fits nowhere else.
+func (r *GormReservationRepository) ReassignTable(ctx context.Context, tableID uint) ([]reservation.Reservation, error) {
+	return retryOnConflict(func() ([]reservation.Reservation, error) {
+		return r.reassignTable(ctx, tableID)
+	})
+}
+
+func (r *GormReservationRepository) reassignTable(ctx context.Context, tableID uint) ([]reservation.Reservation, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if tx.Error != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// locking the table keeps new bookings off it until the reservations are moved
+	if err := lockTables(tx, []uint{tableID}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var reservations []reservation.Reservation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN (?)", tx.Model(&reservation.ReservationTable{}).Select("reservation_id").Where("table_id = ?", tableID)).
+		Where("status IN ? AND end_at > ?", reservation.ModifiableStatuses(), time.Now()).
+		Order("start_at").
+		Order("id").
+		Find(&reservations).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	for i := range reservations {
+		resv := &reservations[i]
+		tables, err := reserveTables(tx, r.allocator, tableRequest{
+			seatsNeeded:          resv.SeatsCount,
+			startAt:              resv.StartAt,
+			endAt:                resv.EndAt,
+			excludeReservationID: resv.ID,
+			unavailableTableID:   tableID,
+		})
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := tx.Model(resv).Update("table_id", tables.tables[0].TableID).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := tx.Where("reservation_id = ?", resv.ID).Delete(&reservation.ReservationTable{}).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		resv.Tables = tables.tables
+		for j := range resv.Tables {
+			resv.Tables[j].ReservationID = resv.ID
+		}
+		if err := tx.Create(&resv.Tables).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// price prices a reservation of seatsNeeded starting at startAt at the allocated tables, by the seat price of the
+// settings in effect at startAt
+func (r *GormReservationRepository) price(tx *gorm.DB, seatsNeeded int, startAt time.Time, tables allocatedTables) (reservation.PriceBreakdown, reservation.Totals, error) {
+	history, err := loadSettingsHistory(tx)
+	if err != nil {
+		return nil, reservation.Totals{}, err
+	}
+	settings, err := history.At(startAt)
+	if err != nil {
+		return nil, reservation.Totals{}, err
+	}
+
+	return r.pricingEngine.Price(pricing.Quote{
+		SeatsCount: seatsNeeded,
+		Tables:     tables.pricingTables(),
+		StartAt:    startAt,
+		SeatPrice:  settings.SeatPrice,
+	})
+}
+
+// CancelReservation marks a reservation as cancelled on behalf of userID and records the cancellation.
+// Only the owner of the reservation can cancel it, unless canCancelAny is set for a user managing reservations.
+func (r *GormReservationRepository) CancelReservation(ctx context.Context, reservationID int, userID int, canCancelAny bool, reason string) error {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if tx.Error != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var resv reservation.Reservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&resv, reservationID).Error; err != nil {
 		tx.Rollback()
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return reservation.ErrReservationNotFound
@@ -111,7 +309,31 @@ func (r *GormReservationRepository) CancelReservation(ctx context.Context, reser
 		return err
 	}
 
-	if err := tx.Delete(&resv).Error; err != nil {
+	onBehalfOfGuest := int(resv.UserID) != userID
+	if onBehalfOfGuest && !canCancelAny {
+		tx.Rollback()
+		return reservation.ErrReservationNotOwned
+	}
+
+	if !resv.Status.CanTransitionTo(reservation.StatusCancelled) {
+		tx.Rollback()
+		return reservation.ErrInvalidStatusTransition
+	}
+
+	cancellation := reservation.Cancellation{
+		ReservationID:   resv.ID,
+		ReservedBy:      resv.UserID,
+		CancelledBy:     userID,
+		OnBehalfOfGuest: onBehalfOfGuest,
+		Reason:          reason,
+		CancelledAt:     time.Now(),
+	}
+	if err := tx.Create(&cancellation).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(&resv).Update("status", reservation.StatusCancelled).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -122,3 +344,284 @@ func (r *GormReservationRepository) CancelReservation(ctx context.Context, reser
 
 	return nil
 }
+
+// UpdateStatus moves a reservation to the given status if the transition is allowed
+func (r *GormReservationRepository) UpdateStatus(ctx context.Context, reservationID int, status reservation.Status) (*reservation.Reservation, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if tx.Error != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var resv reservation.Reservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&resv, reservationID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, reservation.ErrReservationNotFound
+		}
+		return nil, err
+	}
+
+	if !resv.Status.CanTransitionTo(status) {
+		tx.Rollback()
+		return nil, reservation.ErrInvalidStatusTransition
+	}
+
+	if err := tx.Model(&resv).Update("status", status).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// the reservation is returned as stored, along with its tables
+	if err := tx.Preload("Tables").First(&resv, reservationID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &resv, nil
+}
+
+// FindAvailability returns the tables that fit seatsNeeded for every time slot of the given duration starting
+// from "from" up to "to" every interval, with the price quoted for each by the settings in effect at the slot.
+// Tables of a slot are ordered the way the allocator prefers them, groups of joinable tables are only offered for
+// slots no single table fits and slots without any fitting table are left out.
+func (r *GormReservationRepository) FindAvailability(ctx context.Context, seatsNeeded int, from, to time.Time, duration, interval time.Duration) ([]reservation.Availability, error) {
+	slots := `
+		WITH slots AS (
+			SELECT start_at, start_at + ? * interval '1 second' AS end_at
+			FROM generate_series(?::timestamptz, ?::timestamptz, ? * interval '1 second') AS start_at
+		),`
+	slotsArgs := []interface{}{int(duration.Seconds()), from, to, int(interval.Seconds()), 0, reservation.ActiveStatuses()}
+
+	tablesQuery := slots + tableAvailabilityCTE + `
+		SELECT
+			ta.start_at,
+			ta.end_at,
+			ta.table_id,
+			ta.total_seats,
+			ta.reserved_seats,
+			ta.available_seats
+		FROM table_availability ta
+		WHERE ta.available_seats >= ?
+		ORDER BY ta.start_at ASC, ta.table_id ASC;
+	`
+
+	var tableRows []struct {
+		StartAt        time.Time
+		EndAt          time.Time
+		TableID        uint
+		TotalSeats     int
+		ReservedSeats  int
+		AvailableSeats int
+	}
+	err := r.db.WithContext(ctx).Raw(tablesQuery, append(slotsArgs, seatsNeeded)...).Scan(&tableRows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	groupsQuery := slots + tableAvailabilityCTE + `
+		SELECT
+			ga.start_at,
+			ga.end_at,
+			ga.group_id,
+			gt.table_id,
+			t.seats_count AS table_seats,
+			ga.total_seats AS available_seats
+		FROM group_availability ga
+		JOIN table_group_tables gt ON gt.table_group_id = ga.group_id
+		JOIN tables t ON t.id = gt.table_id
+		WHERE ga.total_seats >= ?
+			AND NOT EXISTS (
+				SELECT 1 FROM table_availability ta
+				WHERE ta.start_at = ga.start_at AND ta.available_seats >= ?
+			)
+		ORDER BY ga.start_at ASC, ga.total_seats ASC, ga.tables_count ASC, ga.group_id ASC, gt.table_id ASC;
+	`
+
+	var groupRows []struct {
+		StartAt        time.Time
+		EndAt          time.Time
+		GroupID        int
+		TableID        uint
+		TableSeats     int
+		AvailableSeats int
+	}
+	err = r.db.WithContext(ctx).Raw(groupsQuery, append(slotsArgs, seatsNeeded, seatsNeeded)...).Scan(&groupRows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := loadSettingsHistory(r.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	quote := func(startAt time.Time, tables []pricing.Table) (money.Money, error) {
+		settings, err := history.At(startAt)
+		if err != nil {
+			return money.Money{}, err
+		}
+		_, totals, err := r.pricingEngine.Price(pricing.Quote{
+			SeatsCount: seatsNeeded,
+			Tables:     tables,
+			StartAt:    startAt,
+			SeatPrice:  settings.SeatPrice,
+		})
+		if err != nil {
+			return money.Money{}, err
+		}
+		return totals.Gross, nil
+	}
+
+	availabilities := make([]reservation.Availability, 0, len(tableRows)+len(groupRows))
+	var slotTables []allocation.Table
+	for i, row := range tableRows {
+		price, err := quote(row.StartAt, []pricing.Table{{TotalSeats: row.TotalSeats, SeatsTaken: seatsNeeded}})
+		if err != nil {
+			return nil, err
+		}
+		availabilities = append(availabilities, reservation.Availability{
+			StartAt:        row.StartAt,
+			EndAt:          row.EndAt,
+			TableIDs:       []uint{row.TableID},
+			AvailableSeats: row.AvailableSeats,
+			Price:          price,
+		})
+		slotTables = append(slotTables, allocation.Table{ID: row.TableID, SeatsCount: row.TotalSeats, ReservedSeats: row.ReservedSeats})
+
+		// tables come ordered by slot, rank them once all the tables of the slot are read
+		if i == len(tableRows)-1 || !tableRows[i+1].StartAt.Equal(row.StartAt) {
+			slot := availabilities[len(availabilities)-len(slotTables):]
+			if err := r.rankTables(seatsNeeded, slot, slotTables); err != nil {
+				return nil, err
+			}
+			slotTables = slotTables[:0]
+		}
+	}
+
+	// tables of a group come one per row, ordered by slot and group
+	lastGroupID := 0
+	var groupTables []pricing.Table
+	for i, row := range groupRows {
+		last := len(availabilities) - 1
+		if lastGroupID == row.GroupID && availabilities[last].StartAt.Equal(row.StartAt) {
+			availabilities[last].TableIDs = append(availabilities[last].TableIDs, row.TableID)
+		} else {
+			availabilities = append(availabilities, reservation.Availability{
+				StartAt:        row.StartAt,
+				EndAt:          row.EndAt,
+				TableIDs:       []uint{row.TableID},
+				AvailableSeats: row.AvailableSeats,
+			})
+			lastGroupID = row.GroupID
+			groupTables = groupTables[:0]
+		}
+
+		// the group is priced once all of its tables are read, every table of a group is taken as a whole
+		groupTables = append(groupTables, pricing.Table{TotalSeats: row.TableSeats, SeatsTaken: row.TableSeats})
+		if i == len(groupRows)-1 || groupRows[i+1].GroupID != row.GroupID || !groupRows[i+1].StartAt.Equal(row.StartAt) {
+			price, err := quote(row.StartAt, groupTables)
+			if err != nil {
+				return nil, err
+			}
+			availabilities[len(availabilities)-1].Price = price
+		}
+	}
+
+	// slots have either single tables or groups, so sorting by slot keeps the order inside each slot
+	sort.SliceStable(availabilities, func(i, j int) bool {
+		return availabilities[i].StartAt.Before(availabilities[j].StartAt)
+	})
+
+	return availabilities, nil
+}
+
+// rankTables orders the availabilities of the tables of a slot the way the allocator prefers the tables, by
+// letting it pick a table and then pick again among the tables left until every table is picked
+func (r *GormReservationRepository) rankTables(seatsNeeded int, availabilities []reservation.Availability, tables []allocation.Table) error {
+	byTableID := make(map[uint]reservation.Availability, len(availabilities))
+	for _, a := range availabilities {
+		byTableID[a.TableIDs[0]] = a
+	}
+
+	left := append([]allocation.Table(nil), tables...)
+	for i := range availabilities {
+		picked, err := r.allocator.Allocate(seatsNeeded, allocation.Floor{Tables: left})
+		if err != nil {
+			return err
+		}
+
+		availabilities[i] = byTableID[picked[0].TableID]
+		for j, t := range left {
+			if t.ID == picked[0].TableID {
+				left = append(left[:j], left[j+1:]...)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// FindByID finds a reservation by its ID
+func (r *GormReservationRepository) FindByID(ctx context.Context, reservationID int) (*reservation.Reservation, error) {
+	var resv reservation.Reservation
+	result := r.db.WithContext(ctx).Preload("Tables").First(&resv, reservationID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, reservation.ErrReservationNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &resv, nil
+}
+
+// ListByUser returns a page of the reservations of a user matching the filter and the total number of matches
+func (r *GormReservationRepository) ListByUser(ctx context.Context, userID int, filter reservation.ListFilter) ([]reservation.Reservation, int, error) {
+	query := r.db.WithContext(ctx).Model(&reservation.Reservation{}).Where("user_id = ?", userID)
+	if !filter.From.IsZero() {
+		query = query.Where("start_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("start_at < ?", filter.To)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = reservation.SortByStartAt
+	}
+	column := string(sortBy)
+	if sortBy == reservation.SortByPrice {
+		column = "price_amount"
+	}
+
+	var reservations []reservation.Reservation
+	err := query.
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: filter.SortDesc}).
+		Order("id").
+		Offset(filter.Offset()).
+		Limit(filter.PerPage).
+		Preload("Tables").
+		Find(&reservations).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reservations, int(total), nil
+}
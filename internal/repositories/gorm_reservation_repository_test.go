@@ -0,0 +1,107 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/repositories"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/allocation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookTableConcurrently(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	tableRepository := repositories.NewGormTableRepository(db)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, tableRepository.CreateTable(ctx, &table.Table{SeatsCount: 4}))
+	}
+	require.NoError(t, tableRepository.CreateTableSettings(ctx, money.New(1000, "USD")))
+
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, repositories.NewGormUserRepository(db).Register(ctx, &u))
+
+	reservationRepository := repositories.NewGormReservationRepository(db, allocation.NewBestFitAllocator(), newTestPricingEngine())
+	startAt := time.Now().AddDate(0, 0, 1).Truncate(time.Hour)
+
+	// two tables of four seats can host four parties of two
+	const requests = 30
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := reservationRepository.BookTable(ctx, u.ID, 2, startAt, 2*time.Hour)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	booked := 0
+	for err := range errs {
+		if err == nil {
+			booked++
+			continue
+		}
+		require.True(t, errors.Is(err, reservation.ErrNoTablesAreAvailable), err)
+	}
+	require.Equal(t, 4, booked)
+
+	var seatsPerTable []struct {
+		TableID int
+		Seats   int
+	}
+	err := db.Table("reservation_tables").
+		Select("table_id, SUM(seats_count) AS seats").
+		Group("table_id").
+		Scan(&seatsPerTable).Error
+	require.NoError(t, err)
+	for _, tableSeats := range seatsPerTable {
+		require.LessOrEqual(t, tableSeats.Seats, 4, "table %d is overbooked", tableSeats.TableID)
+	}
+}
+
+func TestBookTableGroupForLargeParty(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	tableRepository := repositories.NewGormTableRepository(db)
+	tables := []table.Table{{SeatsCount: 8}, {SeatsCount: 8}, {SeatsCount: 4}}
+	for i := range tables {
+		require.NoError(t, tableRepository.CreateTable(ctx, &tables[i]))
+	}
+	require.NoError(t, tableRepository.CreateTableSettings(ctx, money.New(1000, "USD")))
+	require.NoError(t, tableRepository.CreateGroup(ctx, &table.Group{Name: "8-tops", Tables: tables[:2]}))
+
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, repositories.NewGormUserRepository(db).Register(ctx, &u))
+
+	reservationRepository := repositories.NewGormReservationRepository(db, allocation.NewBestFitAllocator(), newTestPricingEngine())
+	startAt := time.Now().AddDate(0, 0, 1).Truncate(time.Hour)
+
+	resv, err := reservationRepository.BookTable(ctx, u.ID, 14, startAt, 2*time.Hour)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint{uint(tables[0].ID), uint(tables[1].ID)}, resv.TableIDs())
+	require.Equal(t, money.New(14*1000, "USD"), resv.Price)
+	require.Equal(t, resv.Price, resv.PriceBreakdown.Total())
+	require.Equal(t, resv.Price, resv.Totals.Gross)
+
+	// the joined tables are taken as a whole, so even a party of two does not fit them anymore
+	resv, err = reservationRepository.BookTable(ctx, u.ID, 2, startAt, 2*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []uint{uint(tables[2].ID)}, resv.TableIDs())
+
+	_, err = reservationRepository.BookTable(ctx, u.ID, 4, startAt, 2*time.Hour)
+	require.ErrorIs(t, err, reservation.ErrNoTablesAreAvailable)
+}
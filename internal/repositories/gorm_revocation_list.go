@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// revokedToken is an access token revoked before it expires
+type revokedToken struct {
+	TokenID   string    `gorm:"type:varchar;primaryKey"`
+	ExpiresAt time.Time `gorm:"type:timestamptz,NOT NULL"`
+}
+
+// TableName returns the table name
+func (t revokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+// GormRevocationList is a RevocationList shared by every instance of the application
+type GormRevocationList struct {
+	db *gorm.DB
+}
+
+func NewGormRevocationList(db *gorm.DB) token.RevocationList {
+	return &GormRevocationList{
+		db: db,
+	}
+}
+
+// Revoke adds a token to the list until it expires
+func (l *GormRevocationList) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	tx := l.db.WithContext(ctx)
+
+	// expired tokens are rejected anyway, so they are dropped to keep the list small
+	if err := tx.Where("expires_at <= ?", time.Now()).Delete(&revokedToken{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&revokedToken{TokenID: tokenID, ExpiresAt: expiresAt}).Error
+}
+
+// IsRevoked reports whether the token was revoked
+func (l *GormRevocationList) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var count int64
+	err := l.db.WithContext(ctx).Model(&revokedToken{}).Where("token_id = ?", tokenID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
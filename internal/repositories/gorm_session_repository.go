@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSessionRepository(db *gorm.DB) session.Repository {
+	return &GormSessionRepository{
+		db: db,
+	}
+}
+
+// CreateSession stores a new session
+func (r *GormSessionRepository) CreateSession(ctx context.Context, s *session.Session) error {
+	if s.LastSeenAt.IsZero() {
+		s.LastSeenAt = time.Now()
+	}
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+// FindByRefreshToken finds the session a refresh token was issued for, whether it is the current refresh token of
+// the session or one it exchanged before
+func (r *GormSessionRepository) FindByRefreshToken(ctx context.Context, refreshTokenHash string) (*session.Session, error) {
+	var s session.Session
+	result := r.db.WithContext(ctx).
+		Where("refresh_token_hash = ? OR id IN (?)", refreshTokenHash,
+			r.db.Model(&session.ExchangedRefreshToken{}).Select("session_id").Where("token_hash = ?", refreshTokenHash)).
+		First(&s)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, session.ErrSessionNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &s, nil
+}
+
+// RotateRefreshToken stores the new tokens of a session and keeps the refresh token it replaces among the exchanged
+// ones, only when that refresh token has not been exchanged by a concurrent request in the meantime
+func (r *GormSessionRepository) RotateRefreshToken(ctx context.Context, s *session.Session) error {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if tx.Error != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	result := tx.
+		Model(&session.Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", s.ID, s.PreviousRefreshTokenHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":          s.RefreshTokenHash,
+			"previous_refresh_token_hash": s.PreviousRefreshTokenHash,
+			"access_token_id":             s.AccessTokenID,
+			"access_token_expires_at":     s.AccessTokenExpiresAt,
+			"expires_at":                  s.ExpiresAt,
+			"ip_address":                  s.IPAddress,
+			"last_seen_at":                now,
+			"updated_at":                  now,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return session.ErrSessionNotFound
+	}
+
+	exchanged := session.ExchangedRefreshToken{
+		TokenHash:   s.PreviousRefreshTokenHash,
+		SessionID:   s.ID,
+		ExchangedAt: now,
+	}
+	if err := tx.Create(&exchanged).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// RevokeSession revokes a session, so its refresh tokens can not be exchanged anymore
+func (r *GormSessionRepository) RevokeSession(ctx context.Context, sessionID int) error {
+	result := r.db.WithContext(ctx).
+		Model(&session.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now())
+	return result.Error
+}
+
+// RevokeUserSessions revokes every session of a user, returning the sessions it revoked
+func (r *GormSessionRepository) RevokeUserSessions(ctx context.Context, userID int) ([]session.Session, error) {
+	var sessions []session.Session
+	result := r.db.WithContext(ctx).
+		Model(&sessions).
+		Clauses(clause.Returning{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return sessions, nil
+}
+
+// FindByID finds a session, whether or not it is active
+func (r *GormSessionRepository) FindByID(ctx context.Context, sessionID int) (*session.Session, error) {
+	var s session.Session
+	result := r.db.WithContext(ctx).Where("id = ?", sessionID).First(&s)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, session.ErrSessionNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &s, nil
+}
+
+// ListActiveUserSessions lists the sessions of a user that are neither revoked nor expired, the most recently used
+// first
+func (r *GormSessionRepository) ListActiveUserSessions(ctx context.Context, userID int) ([]session.Session, error) {
+	var sessions []session.Session
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC, id DESC").
+		Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return sessions, nil
+}
+
+// RevokeOtherUserSessions revokes every session of a user but the given one, returning the sessions it revoked
+func (r *GormSessionRepository) RevokeOtherUserSessions(ctx context.Context, userID int, sessionID int) ([]session.Session, error) {
+	var sessions []session.Session
+	result := r.db.WithContext(ctx).
+		Model(&sessions).
+		Clauses(clause.Returning{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, sessionID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return sessions, nil
+}
+
+// TouchSession records that a session of the user is used from the IP address now, it is not found once the session
+// is revoked or expired
+func (r *GormSessionRepository) TouchSession(ctx context.Context, sessionID int, userID int, ipAddress string) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&session.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, userID, now).
+		Updates(map[string]interface{}{
+			"ip_address":   ipAddress,
+			"last_seen_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return session.ErrSessionNotFound
+	}
+
+	return nil
+}
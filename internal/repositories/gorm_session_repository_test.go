@@ -0,0 +1,180 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/session"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/repositories"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateRefreshToken(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, repositories.NewGormUserRepository(db).Register(ctx, &u))
+
+	sessionRepository := repositories.NewGormSessionRepository(db)
+	s := session.Session{
+		UserID:               u.ID,
+		RefreshTokenHash:     "first",
+		AccessTokenID:        "first-access-token",
+		AccessTokenExpiresAt: time.Now().Add(time.Minute),
+		ExpiresAt:            time.Now().Add(time.Hour),
+	}
+	require.NoError(t, sessionRepository.CreateSession(ctx, &s))
+
+	rotated := s
+	rotated.PreviousRefreshTokenHash = "first"
+	rotated.RefreshTokenHash = "second"
+	rotated.AccessTokenID = "second-access-token"
+	require.NoError(t, sessionRepository.RotateRefreshToken(ctx, &rotated))
+
+	// a concurrent request exchanging the same refresh token loses
+	concurrent := s
+	concurrent.PreviousRefreshTokenHash = "first"
+	concurrent.RefreshTokenHash = "third"
+	require.ErrorIs(t, sessionRepository.RotateRefreshToken(ctx, &concurrent), session.ErrSessionNotFound)
+
+	// both the current and the exchanged refresh token lead to the session
+	for _, hash := range []string{"first", "second"} {
+		found, err := sessionRepository.FindByRefreshToken(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, s.ID, found.ID)
+		require.Equal(t, "second", found.RefreshTokenHash)
+		require.Equal(t, "second-access-token", found.AccessTokenID)
+	}
+	_, err := sessionRepository.FindByRefreshToken(ctx, "third")
+	require.ErrorIs(t, err, session.ErrSessionNotFound)
+
+	rotatedAgain := rotated
+	rotatedAgain.PreviousRefreshTokenHash = "second"
+	rotatedAgain.RefreshTokenHash = "fourth"
+	require.NoError(t, sessionRepository.RotateRefreshToken(ctx, &rotatedAgain))
+
+	// every refresh token exchanged before still leads to the session, so replaying any of them is detected
+	for _, hash := range []string{"first", "second", "fourth"} {
+		found, err := sessionRepository.FindByRefreshToken(ctx, hash)
+		require.NoError(t, err)
+		require.Equal(t, s.ID, found.ID)
+		require.Equal(t, "fourth", found.RefreshTokenHash)
+	}
+
+	require.NoError(t, sessionRepository.RevokeSession(ctx, s.ID))
+	found, err := sessionRepository.FindByRefreshToken(ctx, "first")
+	require.NoError(t, err)
+	require.False(t, found.IsActive(time.Now()))
+
+	next := *found
+	next.PreviousRefreshTokenHash = "fourth"
+	next.RefreshTokenHash = "fifth"
+	require.ErrorIs(t, sessionRepository.RotateRefreshToken(ctx, &next), session.ErrSessionNotFound)
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, repositories.NewGormUserRepository(db).Register(ctx, &u))
+
+	sessionRepository := repositories.NewGormSessionRepository(db)
+	active := session.Session{
+		UserID:               u.ID,
+		RefreshTokenHash:     faker.UUIDDigit(),
+		AccessTokenID:        faker.UUIDDigit(),
+		AccessTokenExpiresAt: time.Now().Add(time.Minute),
+		ExpiresAt:            time.Now().Add(time.Hour),
+	}
+	revoked := active
+	revoked.RefreshTokenHash = faker.UUIDDigit()
+	revoked.AccessTokenID = faker.UUIDDigit()
+	for _, s := range []*session.Session{&active, &revoked} {
+		require.NoError(t, sessionRepository.CreateSession(ctx, s))
+	}
+	require.NoError(t, sessionRepository.RevokeSession(ctx, revoked.ID))
+
+	// only the sessions that were still active are returned
+	sessions, err := sessionRepository.RevokeUserSessions(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, active.ID, sessions[0].ID)
+	require.Equal(t, active.AccessTokenID, sessions[0].AccessTokenID)
+
+	found, err := sessionRepository.FindByRefreshToken(ctx, active.RefreshTokenHash)
+	require.NoError(t, err)
+	require.False(t, found.IsActive(time.Now()))
+}
+
+func TestGormRevocationList(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	list := repositories.NewGormRevocationList(db)
+	require.NoError(t, list.Revoke(ctx, "a", time.Now().Add(time.Minute)))
+	// revoking twice is fine
+	require.NoError(t, list.Revoke(ctx, "a", time.Now().Add(time.Minute)))
+
+	revoked, err := list.IsRevoked(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	revoked, err = list.IsRevoked(ctx, "b")
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
+
+func TestUserSessions(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, repositories.NewGormUserRepository(db).Register(ctx, &u))
+
+	sessionRepository := repositories.NewGormSessionRepository(db)
+	sessions := make([]session.Session, 3)
+	for i := range sessions {
+		sessions[i] = session.Session{
+			UserID:               u.ID,
+			RefreshTokenHash:     faker.UUIDDigit(),
+			AccessTokenID:        faker.UUIDDigit(),
+			AccessTokenExpiresAt: time.Now().Add(time.Minute),
+			ExpiresAt:            time.Now().Add(time.Hour),
+			UserAgent:            "agent",
+			IPAddress:            "192.0.2.1",
+			LastSeenAt:           time.Now().Add(-time.Duration(i+1) * time.Hour),
+		}
+		require.NoError(t, sessionRepository.CreateSession(ctx, &sessions[i]))
+	}
+
+	// using a session makes it the most recently seen one and records the address it was used from
+	require.NoError(t, sessionRepository.TouchSession(ctx, sessions[2].ID, u.ID, "192.0.2.9"))
+	require.ErrorIs(t, sessionRepository.TouchSession(ctx, sessions[2].ID, u.ID+1, "192.0.2.9"), session.ErrSessionNotFound)
+
+	active, err := sessionRepository.ListActiveUserSessions(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, active, 3)
+	require.Equal(t, sessions[2].ID, active[0].ID)
+	require.Equal(t, "192.0.2.9", active[0].IPAddress)
+	require.Equal(t, sessions[0].ID, active[1].ID)
+
+	revoked, err := sessionRepository.RevokeOtherUserSessions(ctx, u.ID, sessions[0].ID)
+	require.NoError(t, err)
+	require.Len(t, revoked, 2)
+
+	active, err = sessionRepository.ListActiveUserSessions(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	require.Equal(t, sessions[0].ID, active[0].ID)
+
+	// a revoked session can not be used anymore
+	require.ErrorIs(t, sessionRepository.TouchSession(ctx, sessions[1].ID, u.ID, "192.0.2.9"), session.ErrSessionNotFound)
+	found, err := sessionRepository.FindByID(ctx, sessions[1].ID)
+	require.NoError(t, err)
+	require.False(t, found.IsActive(time.Now()))
+}
@@ -2,9 +2,15 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormTableRepository struct
@@ -29,7 +35,235 @@ func (r *GormTableRepository) GetTotalCount(ctx context.Context) int {
 	return int(count)
 }
 
-// CreateTableSettings creates a new table settings
-func (r *GormTableRepository) CreateTableSettings(ctx context.Context, seatPrice int) error {
-	return r.db.WithContext(ctx).Create(&table.Settings{SeatPrice: seatPrice}).Error
+// CreateTableSettings creates the initial table settings, which are in effect for any date
+func (r *GormTableRepository) CreateTableSettings(ctx context.Context, seatPrice money.Money) error {
+	return r.db.WithContext(ctx).Create(&table.Settings{SeatPrice: seatPrice, EffectiveFrom: time.Unix(0, 0).UTC()}).Error
+}
+
+// CreateGroup creates a new group of joinable tables
+func (r *GormTableRepository) CreateGroup(ctx context.Context, group *table.Group) error {
+	return r.db.WithContext(ctx).Omit("Tables.*").Create(group).Error
+}
+
+// ListTables returns the tables ordered by their IDs, the retired ones only when includeRetired is set
+func (r *GormTableRepository) ListTables(ctx context.Context, includeRetired bool) ([]table.Table, error) {
+	query := r.db.WithContext(ctx).Order("id")
+	if !includeRetired {
+		query = query.Where("retired_at IS NULL")
+	}
+
+	var tables []table.Table
+	if err := query.Find(&tables).Error; err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// FindTableByID finds a table by its ID
+func (r *GormTableRepository) FindTableByID(ctx context.Context, tableID int) (*table.Table, error) {
+	var t table.Table
+	result := r.db.WithContext(ctx).First(&t, tableID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, table.ErrTableNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &t, nil
+}
+
+// UpdateTable updates the seats count, name, zone and attributes of a table. A table can not lose seats while it
+// has future reservations, which must be reassigned to other tables first, nor while a party is seated at it.
+func (r *GormTableRepository) UpdateTable(ctx context.Context, t *table.Table) error {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if tx.Error != nil {
+			tx.Rollback()
+		}
+	}()
+
+	current, err := lockTable(tx, t.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if current.IsRetired() {
+		tx.Rollback()
+		return table.ErrTableRetired
+	}
+
+	if t.SeatsCount < current.SeatsCount {
+		if err := checkTableIsFree(tx, t.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Model(t).Select("seats_count", "name", "zone", "attributes").Updates(t).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	t.RetiredAt = current.RetiredAt
+	return nil
+}
+
+// RetireTable takes a table out of service, so it is not offered to any party anymore. A table with future
+// reservations can not be retired until they are reassigned to other tables, nor while a party is seated at it.
+func (r *GormTableRepository) RetireTable(ctx context.Context, tableID int) (*table.Table, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if tx.Error != nil {
+			tx.Rollback()
+		}
+	}()
+
+	t, err := lockTable(tx, tableID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if t.IsRetired() {
+		tx.Rollback()
+		return nil, table.ErrTableRetired
+	}
+
+	if err := checkTableIsFree(tx, tableID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := tx.Model(t).Update("retired_at", now).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// ListSettings returns every version of the settings, the past ones included, ordered by the time they take effect
+func (r *GormTableRepository) ListSettings(ctx context.Context) (table.SettingsHistory, error) {
+	return loadSettingsHistory(r.db.WithContext(ctx))
+}
+
+// ScheduleSettings adds a version of the settings that takes effect at its EffectiveFrom
+func (r *GormTableRepository) ScheduleSettings(ctx context.Context, settings *table.Settings) error {
+	if err := r.db.WithContext(ctx).Create(settings).Error; err != nil {
+		// handling unique_violation error
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return table.ErrSettingsAlreadyScheduled
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeleteScheduledSettings deletes a version of the settings that has not taken effect yet, the versions that have
+// are kept since reservations were priced by them
+func (r *GormTableRepository) DeleteScheduledSettings(ctx context.Context, settingsID int) error {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		} else if tx.Error != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var settings table.Settings
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&settings, settingsID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return table.ErrSettingsNotFound
+		}
+		return err
+	}
+
+	if settings.IsEffective(time.Now()) {
+		tx.Rollback()
+		return table.ErrSettingsAlreadyEffective
+	}
+
+	if err := tx.Delete(&settings).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// lockTable loads a table and locks its row until the transaction ends, bookings lock the rows of the tables
+// they take too, so no reservation can be added to the table meanwhile
+func lockTable(tx *gorm.DB, tableID int) (*table.Table, error) {
+	var t table.Table
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&t, tableID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, table.ErrTableNotFound
+		}
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// checkTableIsFree returns an error when a party is seated at the table, which can not be moved, or when any other
+// active reservation takes the table and has not ended yet, which has to be reassigned first
+func checkTableIsFree(tx *gorm.DB, tableID int) error {
+	hasSeatedParty, err := hasReservations(tx, tableID, []reservation.Status{reservation.StatusSeated})
+	if err != nil {
+		return err
+	}
+	if hasSeatedParty {
+		return table.ErrTableHasSeatedParty
+	}
+
+	hasFutureReservations, err := hasReservations(tx, tableID, reservation.ModifiableStatuses())
+	if err != nil {
+		return err
+	}
+	if hasFutureReservations {
+		return table.ErrTableHasFutureReservations
+	}
+
+	return nil
+}
+
+// hasReservations reports whether any reservation in one of the statuses takes the table and has not ended yet
+func hasReservations(tx *gorm.DB, tableID int, statuses []reservation.Status) (bool, error) {
+	var exists bool
+	err := tx.Raw(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM reservation_tables rt
+			JOIN reservations r ON r.id = rt.reservation_id
+			WHERE rt.table_id = ? AND r.status IN ? AND r.end_at > now()
+		)
+	`, tableID, statuses).Scan(&exists).Error
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
 }
@@ -0,0 +1,157 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/repositories"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/allocation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetireTableWithFutureReservations(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	tableRepository := repositories.NewGormTableRepository(db)
+	tables := []table.Table{{SeatsCount: 4, Name: "window"}, {SeatsCount: 6, Name: "patio"}}
+	for i := range tables {
+		require.NoError(t, tableRepository.CreateTable(ctx, &tables[i]))
+	}
+	require.NoError(t, tableRepository.CreateTableSettings(ctx, money.New(1000, "USD")))
+
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, repositories.NewGormUserRepository(db).Register(ctx, &u))
+
+	reservationRepository := repositories.NewGormReservationRepository(db, allocation.NewBestFitAllocator(), newTestPricingEngine())
+	startAt := time.Now().AddDate(0, 0, 1).Truncate(time.Hour)
+
+	resv, err := reservationRepository.BookTable(ctx, u.ID, 4, startAt, 2*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []uint{uint(tables[0].ID)}, resv.TableIDs())
+
+	// neither shrinking nor retiring the table is allowed while the reservation is on it
+	shrunk := tables[0]
+	shrunk.SeatsCount = 2
+	require.ErrorIs(t, tableRepository.UpdateTable(ctx, &shrunk), table.ErrTableHasFutureReservations)
+	_, err = tableRepository.RetireTable(ctx, tables[0].ID)
+	require.ErrorIs(t, err, table.ErrTableHasFutureReservations)
+
+	// growing the table is fine
+	grown := tables[0]
+	grown.SeatsCount = 6
+	grown.Attributes = table.Attributes{"window"}
+	require.NoError(t, tableRepository.UpdateTable(ctx, &grown))
+
+	moved, err := reservationRepository.ReassignTable(ctx, uint(tables[0].ID))
+	require.NoError(t, err)
+	require.Len(t, moved, 1)
+	require.Equal(t, []uint{uint(tables[1].ID)}, moved[0].TableIDs())
+	require.Equal(t, resv.Price, moved[0].Price)
+
+	retired, err := tableRepository.RetireTable(ctx, tables[0].ID)
+	require.NoError(t, err)
+	require.True(t, retired.IsRetired())
+
+	_, err = tableRepository.RetireTable(ctx, tables[0].ID)
+	require.ErrorIs(t, err, table.ErrTableRetired)
+
+	inService, err := tableRepository.ListTables(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, inService, 1)
+	require.Equal(t, tables[1].ID, inService[0].ID)
+
+	all, err := tableRepository.ListTables(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	require.Equal(t, table.Attributes{"window"}, all[0].Attributes)
+
+	// the retired table is never booked, even though it is free now
+	_, err = reservationRepository.BookTable(ctx, u.ID, 4, startAt, 2*time.Hour)
+	require.ErrorIs(t, err, reservation.ErrNoTablesAreAvailable)
+}
+
+func TestRetireTableWithSeatedParty(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	tableRepository := repositories.NewGormTableRepository(db)
+	tables := []table.Table{{SeatsCount: 4, Name: "window"}, {SeatsCount: 6, Name: "patio"}}
+	for i := range tables {
+		require.NoError(t, tableRepository.CreateTable(ctx, &tables[i]))
+	}
+	require.NoError(t, tableRepository.CreateTableSettings(ctx, money.New(1000, "USD")))
+
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, repositories.NewGormUserRepository(db).Register(ctx, &u))
+
+	reservationRepository := repositories.NewGormReservationRepository(db, allocation.NewBestFitAllocator(), newTestPricingEngine())
+	startAt := time.Now().Add(time.Hour).Truncate(time.Hour)
+
+	resv, err := reservationRepository.BookTable(ctx, u.ID, 4, startAt, 2*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []uint{uint(tables[0].ID)}, resv.TableIDs())
+	_, err = reservationRepository.UpdateStatus(ctx, resv.ID, reservation.StatusConfirmed)
+	require.NoError(t, err)
+	_, err = reservationRepository.UpdateStatus(ctx, resv.ID, reservation.StatusSeated)
+	require.NoError(t, err)
+
+	// the seated party is not moved to another table
+	moved, err := reservationRepository.ReassignTable(ctx, uint(tables[0].ID))
+	require.NoError(t, err)
+	require.Empty(t, moved)
+
+	// so the table can neither be shrunk nor retired until the party leaves
+	shrunk := tables[0]
+	shrunk.SeatsCount = 2
+	require.ErrorIs(t, tableRepository.UpdateTable(ctx, &shrunk), table.ErrTableHasSeatedParty)
+	_, err = tableRepository.RetireTable(ctx, tables[0].ID)
+	require.ErrorIs(t, err, table.ErrTableHasSeatedParty)
+
+	_, err = reservationRepository.UpdateStatus(ctx, resv.ID, reservation.StatusCompleted)
+	require.NoError(t, err)
+	retired, err := tableRepository.RetireTable(ctx, tables[0].ID)
+	require.NoError(t, err)
+	require.True(t, retired.IsRetired())
+}
+
+func TestBookTableUsesSettingsInEffect(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	tableRepository := repositories.NewGormTableRepository(db)
+	require.NoError(t, tableRepository.CreateTable(ctx, &table.Table{SeatsCount: 6}))
+	require.NoError(t, tableRepository.CreateTableSettings(ctx, money.New(1000, "USD")))
+
+	changeAt := time.Now().AddDate(0, 0, 7).Truncate(time.Hour)
+	scheduled := table.Settings{SeatPrice: money.New(1500, "USD"), EffectiveFrom: changeAt}
+	require.NoError(t, tableRepository.ScheduleSettings(ctx, &scheduled))
+	require.ErrorIs(t, tableRepository.ScheduleSettings(ctx, &table.Settings{SeatPrice: money.New(2000, "USD"), EffectiveFrom: changeAt}), table.ErrSettingsAlreadyScheduled)
+
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, repositories.NewGormUserRepository(db).Register(ctx, &u))
+
+	reservationRepository := repositories.NewGormReservationRepository(db, allocation.NewBestFitAllocator(), newTestPricingEngine())
+
+	resv, err := reservationRepository.BookTable(ctx, u.ID, 2, changeAt.Add(-2*time.Hour), 2*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, money.New(2*1000, "USD"), resv.Price)
+
+	resv, err = reservationRepository.BookTable(ctx, u.ID, 2, changeAt, 2*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, money.New(2*1500, "USD"), resv.Price)
+
+	history, err := tableRepository.ListSettings(ctx)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	require.ErrorIs(t, tableRepository.DeleteScheduledSettings(ctx, history[0].ID), table.ErrSettingsAlreadyEffective)
+	require.NoError(t, tableRepository.DeleteScheduledSettings(ctx, scheduled.ID))
+	require.ErrorIs(t, tableRepository.DeleteScheduledSettings(ctx, scheduled.ID), table.ErrSettingsNotFound)
+}
@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormTwoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTwoFactorRepository(db *gorm.DB) twofactor.Repository {
+	return &GormTwoFactorRepository{
+		db: db,
+	}
+}
+
+// FindByUserID finds the two-factor authentication of a user, whether it is enabled or pending
+func (r *GormTwoFactorRepository) FindByUserID(ctx context.Context, userID int) (*twofactor.TwoFactor, error) {
+	var tf twofactor.TwoFactor
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&tf)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, twofactor.ErrTwoFactorNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &tf, nil
+}
+
+// CreateEnrolment stores a pending two-factor authentication of a user, replacing the pending one the user did not
+// confirm. An enabled one is never replaced.
+func (r *GormTwoFactorRepository) CreateEnrolment(ctx context.Context, tf *twofactor.TwoFactor) error {
+	tf.EnabledAt = nil
+	tf.LastUsedStep = 0
+	tf.CreatedAt = time.Now()
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"secret", "last_used_step", "created_at"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "two_factors.enabled_at IS NULL"}}},
+		}).
+		Create(tf)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return twofactor.ErrAlreadyEnabled
+	}
+
+	return nil
+}
+
+// Enable enables the pending two-factor authentication of a user once the first code is confirmed, along with the
+// backup codes of the user. It is not found when the user started over with another secret in the meantime.
+func (r *GormTwoFactorRepository) Enable(ctx context.Context, tf *twofactor.TwoFactor, step int64, backupCodeHashes []string) error {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	result := tx.Model(&twofactor.TwoFactor{}).
+		Where("user_id = ? AND secret = ? AND enabled_at IS NULL", tf.UserID, tf.Secret).
+		Updates(map[string]interface{}{"enabled_at": time.Now(), "last_used_step": step})
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return twofactor.ErrTwoFactorNotFound
+	}
+
+	if err := replaceBackupCodes(tx, tf.UserID, backupCodeHashes); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UseStep records the time step of a code a user logged in with, a code of that step or an earlier one is refused
+// from then on, so a code can not be replayed even when two requests race
+func (r *GormTwoFactorRepository) UseStep(ctx context.Context, userID int, step int64) error {
+	result := r.db.WithContext(ctx).
+		Model(&twofactor.TwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return twofactor.ErrCodeAlreadyUsed
+	}
+
+	return nil
+}
+
+// UseBackupCode uses up a backup code of a user
+func (r *GormTwoFactorRepository) UseBackupCode(ctx context.Context, userID int, codeHash string) error {
+	result := r.db.WithContext(ctx).
+		Model(&twofactor.BackupCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return twofactor.ErrInvalidCode
+	}
+
+	return nil
+}
+
+// ReplaceBackupCodes replaces every backup code of a user, the used ones included
+func (r *GormTwoFactorRepository) ReplaceBackupCodes(ctx context.Context, userID int, codeHashes []string) error {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := replaceBackupCodes(tx, userID, codeHashes); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Disable removes the two-factor authentication of a user along with the backup codes
+func (r *GormTwoFactorRepository) Disable(ctx context.Context, userID int) error {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	result := tx.Where("user_id = ?", userID).Delete(&twofactor.TwoFactor{})
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return twofactor.ErrTwoFactorNotFound
+	}
+
+	if err := tx.Where("user_id = ?", userID).Delete(&twofactor.BackupCode{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func replaceBackupCodes(tx *gorm.DB, userID int, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&twofactor.BackupCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]twofactor.BackupCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, twofactor.BackupCode{UserID: userID, CodeHash: hash})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
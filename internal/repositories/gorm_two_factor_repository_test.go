@@ -0,0 +1,55 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/repositories"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactor(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, repositories.NewGormUserRepository(db).Register(ctx, &u))
+
+	twoFactorRepository := repositories.NewGormTwoFactorRepository(db)
+	_, err := twoFactorRepository.FindByUserID(ctx, u.ID)
+	require.ErrorIs(t, err, twofactor.ErrTwoFactorNotFound)
+
+	// a pending enrolment is replaced by starting over, so confirming the first secret finds nothing
+	first := twofactor.TwoFactor{UserID: u.ID, Secret: "FIRSTSECRET"}
+	require.NoError(t, twoFactorRepository.CreateEnrolment(ctx, &first))
+	second := twofactor.TwoFactor{UserID: u.ID, Secret: "SECONDSECRET"}
+	require.NoError(t, twoFactorRepository.CreateEnrolment(ctx, &second))
+	require.ErrorIs(t, twoFactorRepository.Enable(ctx, &first, 100, nil), twofactor.ErrTwoFactorNotFound)
+
+	require.NoError(t, twoFactorRepository.Enable(ctx, &second, 100, []string{"first-hash", "second-hash"}))
+	tf, err := twoFactorRepository.FindByUserID(ctx, u.ID)
+	require.NoError(t, err)
+	require.True(t, tf.IsEnabled())
+	require.Equal(t, "SECONDSECRET", tf.Secret)
+	require.ErrorIs(t, twoFactorRepository.CreateEnrolment(ctx, &twofactor.TwoFactor{UserID: u.ID, Secret: "THIRDSECRET"}), twofactor.ErrAlreadyEnabled)
+
+	// the step of the confirming code and the earlier ones can not be used again
+	require.ErrorIs(t, twoFactorRepository.UseStep(ctx, u.ID, 100), twofactor.ErrCodeAlreadyUsed)
+	require.NoError(t, twoFactorRepository.UseStep(ctx, u.ID, 101))
+	require.ErrorIs(t, twoFactorRepository.UseStep(ctx, u.ID, 101), twofactor.ErrCodeAlreadyUsed)
+
+	require.NoError(t, twoFactorRepository.UseBackupCode(ctx, u.ID, "first-hash"))
+	require.ErrorIs(t, twoFactorRepository.UseBackupCode(ctx, u.ID, "first-hash"), twofactor.ErrInvalidCode)
+
+	require.NoError(t, twoFactorRepository.ReplaceBackupCodes(ctx, u.ID, []string{"first-hash"}))
+	require.ErrorIs(t, twoFactorRepository.UseBackupCode(ctx, u.ID, "second-hash"), twofactor.ErrInvalidCode)
+	require.NoError(t, twoFactorRepository.UseBackupCode(ctx, u.ID, "first-hash"))
+
+	require.NoError(t, twoFactorRepository.Disable(ctx, u.ID))
+	require.ErrorIs(t, twoFactorRepository.Disable(ctx, u.ID), twofactor.ErrTwoFactorNotFound)
+	_, err = twoFactorRepository.FindByUserID(ctx, u.ID)
+	require.ErrorIs(t, err, twofactor.ErrTwoFactorNotFound)
+}
@@ -3,10 +3,16 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/identity"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/twofactor"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GormUserRepository struct {
@@ -21,19 +27,24 @@ func NewGormUserRepository(db *gorm.DB) user.Repository {
 
 // Register registers a new user
 func (r *GormUserRepository) Register(ctx context.Context, u *user.User) error {
-	result := r.db.WithContext(ctx).Create(&u)
-	if result.Error != nil {
-		// handling unique_violation error
-		var pgErr *pgconn.PgError
-		if errors.As(result.Error, &pgErr) && pgErr.Code == "23505" {
-			return user.ErrUsernameAlreadyExists
-		}
-		return result.Error
+	if err := r.db.WithContext(ctx).Create(&u).Error; err != nil {
+		return registrationError(err)
 	}
 
 	return nil
 }
 
+// registrationError tells which of the username and email of a new user is taken when the user can not be stored
+func registrationError(err error) error {
+	if !isUniqueViolation(err) {
+		return err
+	}
+	if violatedConstraint(err) == "users_email_key" {
+		return user.ErrEmailAlreadyExists
+	}
+	return user.ErrUsernameAlreadyExists
+}
+
 // FindByUsername finds a user by username
 func (r *GormUserRepository) FindByUsername(ctx context.Context, username string) (*user.User, error) {
 	var u user.User
@@ -47,3 +58,324 @@ func (r *GormUserRepository) FindByUsername(ctx context.Context, username string
 
 	return &u, nil
 }
+
+// FindByEmail finds a user by email, emails are stored in lower case
+func (r *GormUserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	var u user.User
+	result := r.db.WithContext(ctx).Where("email = ?", email).First(&u)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, user.ErrUserNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &u, nil
+}
+
+// FindByID finds a user by id
+func (r *GormUserRepository) FindByID(ctx context.Context, id int) (*user.User, error) {
+	var u user.User
+	result := r.db.WithContext(ctx).First(&u, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, user.ErrUserNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &u, nil
+}
+
+// UpdateRole changes the role of a user
+func (r *GormUserRepository) UpdateRole(ctx context.Context, id int, role user.Role) (*user.User, error) {
+	u, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Model(u).Update("role", role).Error; err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// UpdateProfile saves the contact details of a user
+func (r *GormUserRepository) UpdateProfile(ctx context.Context, u *user.User) error {
+	result := r.db.WithContext(ctx).
+		Model(u).
+		Select("name", "email", "phone", "language", "email_verified_at").
+		Updates(u)
+	if isUniqueViolation(result.Error) {
+		return user.ErrEmailAlreadyExists
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdatePassword sets the password of a user
+func (r *GormUserRepository) UpdatePassword(ctx context.Context, id int, password string) error {
+	result := r.db.WithContext(ctx).Model(&user.User{ID: id}).Update("password", password)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// DeleteUser deletes the account of a user. The user is kept, without any of their details, for the reservations
+// they made, which is why an account with upcoming reservations can not be deleted until they are cancelled.
+func (r *GormUserRepository) DeleteUser(ctx context.Context, id int) error {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	var u user.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&u, id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user.ErrUserNotFound
+		}
+		return err
+	}
+
+	var hasUpcomingReservations bool
+	err := tx.Raw(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM reservations
+			WHERE user_id = ? AND status IN ? AND end_at > now()
+		)
+	`, id, reservation.ActiveStatuses()).Scan(&hasUpcomingReservations).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if hasUpcomingReservations {
+		tx.Rollback()
+		return user.ErrUpcomingReservations
+	}
+
+	err = tx.Model(&u).Updates(map[string]interface{}{
+		"username":          fmt.Sprintf("deleted-%d", u.ID),
+		"password":          "",
+		"name":              "",
+		"email":             "",
+		"phone":             "",
+		"language":          "",
+		"email_verified_at": nil,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&u).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("user_id = ?", u.ID).Delete(&user.PasswordReset{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("user_id = ?", u.ID).Delete(&user.EmailVerification{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("user_id = ?", u.ID).Delete(&identity.Identity{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("user_id = ?", u.ID).Delete(&identity.LoginState{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("user_id = ?", u.ID).Delete(&twofactor.TwoFactor{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("user_id = ?", u.ID).Delete(&twofactor.BackupCode{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// CreatePasswordReset stores a new password reset of a user
+func (r *GormUserRepository) CreatePasswordReset(ctx context.Context, reset *user.PasswordReset) error {
+	return r.db.WithContext(ctx).Create(reset).Error
+}
+
+// ResetPassword sets the password of the user of a password reset that is neither used nor expired, every other
+// pending password reset of the user is used up along with it
+func (r *GormUserRepository) ResetPassword(ctx context.Context, tokenHash string, password string) (*user.User, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	now := time.Now()
+	var reset user.PasswordReset
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		First(&reset).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return nil, user.ErrInvalidResetToken
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var u user.User
+	if err := tx.First(&u, reset.UserID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, user.ErrInvalidResetToken
+		}
+		return nil, err
+	}
+
+	if err := tx.Model(&u).Update("password", password).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Model(&user.PasswordReset{}).
+		Where("user_id = ? AND used_at IS NULL", u.ID).
+		Update("used_at", now).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// CreateEmailVerification stores a new email verification of a user
+func (r *GormUserRepository) CreateEmailVerification(ctx context.Context, verification *user.EmailVerification) error {
+	return r.db.WithContext(ctx).Create(verification).Error
+}
+
+// ListEmailVerificationsSince lists the email verifications sent to a user since the given time
+func (r *GormUserRepository) ListEmailVerificationsSince(
+	ctx context.Context,
+	userID int,
+	since time.Time,
+) ([]user.EmailVerification, error) {
+	var verifications []user.EmailVerification
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Order("created_at").
+		Find(&verifications).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return verifications, nil
+}
+
+// VerifyEmail marks the email of the user of an email verification that is neither used nor expired as verified,
+// as long as the user still has the email it was sent to. Every other pending email verification of the user is used
+// up along with it.
+func (r *GormUserRepository) VerifyEmail(ctx context.Context, tokenHash string) (*user.User, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	now := time.Now()
+	var verification user.EmailVerification
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		First(&verification).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return nil, user.ErrInvalidVerifyToken
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var u user.User
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&u, verification.UserID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return nil, user.ErrInvalidVerifyToken
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if u.Email != verification.Email {
+		tx.Rollback()
+		return nil, user.ErrInvalidVerifyToken
+	}
+
+	if err := tx.Model(&u).Update("email_verified_at", now).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Model(&user.EmailVerification{}).
+		Where("user_id = ? AND used_at IS NULL", u.ID).
+		Update("used_at", now).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// isUniqueViolation reports whether the error is a postgres unique_violation error
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// violatedConstraint returns the name of the constraint a postgres error is about, if any
+func violatedConstraint(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
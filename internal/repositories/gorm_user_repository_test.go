@@ -0,0 +1,170 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/repositories"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/allocation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetPassword(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	userRepository := repositories.NewGormUserRepository(db)
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, userRepository.Register(ctx, &u))
+
+	expired := user.PasswordReset{UserID: u.ID, TokenHash: faker.UUIDDigit(), ExpiresAt: time.Now().Add(-time.Minute)}
+	first := user.PasswordReset{UserID: u.ID, TokenHash: faker.UUIDDigit(), ExpiresAt: time.Now().Add(time.Hour)}
+	second := user.PasswordReset{UserID: u.ID, TokenHash: faker.UUIDDigit(), ExpiresAt: time.Now().Add(time.Hour)}
+	for _, reset := range []*user.PasswordReset{&expired, &first, &second} {
+		require.NoError(t, userRepository.CreatePasswordReset(ctx, reset))
+	}
+
+	_, err := userRepository.ResetPassword(ctx, expired.TokenHash, "expired")
+	require.ErrorIs(t, err, user.ErrInvalidResetToken)
+
+	reset, err := userRepository.ResetPassword(ctx, first.TokenHash, "new password")
+	require.NoError(t, err)
+	require.Equal(t, u.ID, reset.ID)
+	require.Equal(t, "new password", reset.Password)
+
+	// a token is used once, and using one uses up the other pending tokens of the user
+	for _, tokenHash := range []string{first.TokenHash, second.TokenHash} {
+		_, err := userRepository.ResetPassword(ctx, tokenHash, "another password")
+		require.ErrorIs(t, err, user.ErrInvalidResetToken)
+	}
+
+	found, err := userRepository.FindByUsername(ctx, u.Username)
+	require.NoError(t, err)
+	require.Equal(t, "new password", found.Password)
+}
+
+func TestVerifyEmail(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	userRepository := repositories.NewGormUserRepository(db)
+	u := user.User{Username: faker.Username(), Password: faker.Password(), Email: faker.Email()}
+	require.NoError(t, userRepository.Register(ctx, &u))
+
+	duplicate := user.User{Username: faker.Username(), Password: faker.Password(), Email: u.Email}
+	require.ErrorIs(t, userRepository.Register(ctx, &duplicate), user.ErrEmailAlreadyExists)
+
+	expired := user.EmailVerification{
+		UserID: u.ID, Email: u.Email, TokenHash: faker.UUIDDigit(), ExpiresAt: time.Now().Add(-time.Minute),
+	}
+	stale := user.EmailVerification{
+		UserID: u.ID, Email: faker.Email(), TokenHash: faker.UUIDDigit(), ExpiresAt: time.Now().Add(time.Hour),
+	}
+	first := user.EmailVerification{
+		UserID: u.ID, Email: u.Email, TokenHash: faker.UUIDDigit(), ExpiresAt: time.Now().Add(time.Hour),
+	}
+	second := user.EmailVerification{
+		UserID: u.ID, Email: u.Email, TokenHash: faker.UUIDDigit(), ExpiresAt: time.Now().Add(time.Hour),
+	}
+	for _, verification := range []*user.EmailVerification{&expired, &stale, &first, &second} {
+		require.NoError(t, userRepository.CreateEmailVerification(ctx, verification))
+	}
+
+	sent, err := userRepository.ListEmailVerificationsSince(ctx, u.ID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, sent, 4)
+
+	// a token only verifies the email it was sent to
+	for _, tokenHash := range []string{expired.TokenHash, stale.TokenHash} {
+		_, err := userRepository.VerifyEmail(ctx, tokenHash)
+		require.ErrorIs(t, err, user.ErrInvalidVerifyToken)
+	}
+
+	verified, err := userRepository.VerifyEmail(ctx, first.TokenHash)
+	require.NoError(t, err)
+	require.Equal(t, u.ID, verified.ID)
+	require.True(t, verified.IsEmailVerified())
+
+	_, err = userRepository.VerifyEmail(ctx, second.TokenHash)
+	require.ErrorIs(t, err, user.ErrInvalidVerifyToken)
+
+	// changing the email clears the verification
+	verified.Email = faker.Email()
+	verified.EmailVerifiedAt = nil
+	require.NoError(t, userRepository.UpdateProfile(ctx, verified))
+
+	found, err := userRepository.FindByID(ctx, u.ID)
+	require.NoError(t, err)
+	require.False(t, found.IsEmailVerified())
+}
+
+func TestUpdateProfile(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	userRepository := repositories.NewGormUserRepository(db)
+	first := user.User{Username: faker.Username(), Password: faker.Password()}
+	second := user.User{Username: faker.Username(), Password: faker.Password()}
+	for _, u := range []*user.User{&first, &second} {
+		require.NoError(t, userRepository.Register(ctx, u))
+	}
+
+	email := faker.Username() + "@example.com"
+	first.Name = "First Guest"
+	first.Email = email
+	first.Language = "en"
+	require.NoError(t, userRepository.UpdateProfile(ctx, &first))
+
+	// an email belongs to one account only, while every account may leave it empty
+	second.Email = email
+	require.ErrorIs(t, userRepository.UpdateProfile(ctx, &second), user.ErrEmailAlreadyExists)
+	second.Email = ""
+	second.Name = "Second Guest"
+	require.NoError(t, userRepository.UpdateProfile(ctx, &second))
+
+	found, err := userRepository.FindByID(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, "First Guest", found.Name)
+	require.Equal(t, email, found.Email)
+	require.Equal(t, "en", found.Language)
+}
+
+func TestDeleteUser(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	tableRepository := repositories.NewGormTableRepository(db)
+	require.NoError(t, tableRepository.CreateTable(ctx, &table.Table{SeatsCount: 4, Name: "window"}))
+	require.NoError(t, tableRepository.CreateTableSettings(ctx, money.New(1000, "USD")))
+
+	userRepository := repositories.NewGormUserRepository(db)
+	u := user.User{Username: faker.Username(), Password: faker.Password()}
+	require.NoError(t, userRepository.Register(ctx, &u))
+	u.Email = faker.Username() + "@example.com"
+	require.NoError(t, userRepository.UpdateProfile(ctx, &u))
+
+	reservationRepository := repositories.NewGormReservationRepository(db, allocation.NewBestFitAllocator(), newTestPricingEngine())
+	resv, err := reservationRepository.BookTable(ctx, u.ID, 4, time.Now().AddDate(0, 0, 1).Truncate(time.Hour), 2*time.Hour)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, userRepository.DeleteUser(ctx, u.ID), user.ErrUpcomingReservations)
+
+	require.NoError(t, reservationRepository.CancelReservation(ctx, resv.ID, u.ID, false, ""))
+	require.NoError(t, userRepository.DeleteUser(ctx, u.ID))
+	require.ErrorIs(t, userRepository.DeleteUser(ctx, u.ID), user.ErrUserNotFound)
+
+	_, err = userRepository.FindByID(ctx, u.ID)
+	require.ErrorIs(t, err, user.ErrUserNotFound)
+	_, err = userRepository.FindByUsername(ctx, u.Username)
+	require.ErrorIs(t, err, user.ErrUserNotFound)
+
+	// the username and email can be taken by a new account
+	reused := user.User{Username: u.Username, Password: faker.Password(), Email: u.Email}
+	require.NoError(t, userRepository.Register(ctx, &reused))
+	require.NoError(t, userRepository.UpdateProfile(ctx, &reused))
+}
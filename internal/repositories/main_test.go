@@ -0,0 +1,63 @@
+package repositories_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/pricing"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB connects to the postgres database in TEST_DATABASE_DSN and migrates a schema that is dropped when
+// the test ends, the test is skipped when no database is configured
+func newTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set, skipping database test")
+	}
+
+	gormConfig := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	admin, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	require.NoError(t, err)
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	require.NoError(t, admin.Exec("CREATE SCHEMA "+schema).Error)
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema), gormConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrations, err := filepath.Glob("../../db/migrations/*.up.sql")
+	require.NoError(t, err)
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		content, err := os.ReadFile(migration)
+		require.NoError(t, err)
+		require.NoError(t, db.Exec(string(content)).Error, migration)
+	}
+
+	return db
+}
+
+// newTestPricingEngine creates a pricing engine that charges the seat price of the table settings for every seat
+// and takes one seat off parties filling their tables, without tax or service charge
+func newTestPricingEngine() *pricing.Engine {
+	return pricing.NewEngine("USD", pricing.Charges{}, pricing.SeatPrices{}, pricing.FullTableDiscount{})
+}
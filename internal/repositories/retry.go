@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// maxTransactionAttempts is how many times a transaction is run before its conflict error is returned
+const maxTransactionAttempts = 3
+
+// retryOnConflict runs fn again when postgres aborted its transaction
+// because of a serialization failure or a deadlock with a concurrent one
+func retryOnConflict[T any](fn func() (T, error)) (T, error) {
+	var result T
+	var err error
+	for attempt := 0; attempt < maxTransactionAttempts; attempt++ {
+		result, err = fn()
+		if !isConflictError(err) {
+			return result, err
+		}
+	}
+	return result, err
+}
+
+func isConflictError(err error) bool {
+	// handling serialization_failure and deadlock_detected errors
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == "40001" || pgErr.Code == "40P01")
+}
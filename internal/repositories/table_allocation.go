@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/table"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/allocation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/pricing"
+	"gorm.io/gorm"
+)
+
+// tableAvailabilityCTE computes how many seats of every table are still free in every candidate time slot. It
+// must follow a "slots" CTE with start_at and end_at columns and takes the ID of a reservation to leave out and
+// the active statuses as parameters. Seats of a table are taken by every active reservation whose time slot
+// overlaps the candidate one, i.e. it starts before the candidate slot ends and ends after it starts. Retired
+// tables, and the groups they belong to, are left out.
+const tableAvailabilityCTE = `
+		table_availability AS (
+			SELECT s.start_at, s.end_at, t.id AS table_id, t.seats_count AS total_seats,
+				COALESCE(SUM(rt.seats_count), 0) AS reserved_seats,
+				(t.seats_count - COALESCE(SUM(rt.seats_count), 0)) AS available_seats
+			FROM slots s
+			CROSS JOIN tables t
+			LEFT JOIN (
+				reservation_tables rt
+				JOIN reservations r ON r.id = rt.reservation_id
+			) ON t.id = rt.table_id AND r.id <> ? AND r.status IN ? AND r.start_at < s.end_at AND r.end_at > s.start_at
+			WHERE t.retired_at IS NULL
+			GROUP BY s.start_at, s.end_at, t.id, t.seats_count
+		),
+		group_availability AS (
+			SELECT ta.start_at, ta.end_at, gt.table_group_id AS group_id,
+				SUM(ta.total_seats) AS total_seats, COUNT(*) AS tables_count
+			FROM table_group_tables gt
+			JOIN table_availability ta ON ta.table_id = gt.table_id
+			GROUP BY ta.start_at, ta.end_at, gt.table_group_id
+			HAVING BOOL_AND(ta.reserved_seats = 0)
+				AND COUNT(*) = (SELECT COUNT(*) FROM table_group_tables g WHERE g.table_group_id = gt.table_group_id)
+		)`
+
+// allocatedTables is the tables a reservation takes
+type allocatedTables struct {
+	tables []reservation.ReservationTable
+	// totalSeats is the number of seats of each of the tables by table ID
+	totalSeats map[uint]int
+}
+
+// tableIDs returns the IDs of the allocated tables
+func (a allocatedTables) tableIDs() []uint {
+	ids := make([]uint, 0, len(a.tables))
+	for _, t := range a.tables {
+		ids = append(ids, t.TableID)
+	}
+	return ids
+}
+
+// pricingTables returns the allocated tables the way the pricing engine quotes them
+func (a allocatedTables) pricingTables() []pricing.Table {
+	tables := make([]pricing.Table, 0, len(a.tables))
+	for _, t := range a.tables {
+		tables = append(tables, pricing.Table{TotalSeats: a.totalSeats[t.TableID], SeatsTaken: t.SeatsCount})
+	}
+	return tables
+}
+
+// loadFloor loads how many seats of every table in service are taken in the time slot and the groups of joinable
+// tables. The reservation with excludeReservationID is not counted as occupying any table; zero disables it.
+func loadFloor(tx *gorm.DB, startAt, endAt time.Time, excludeReservationID int) (allocation.Floor, error) {
+	query := `
+		WITH slots AS (
+			SELECT ?::timestamptz AS start_at, ?::timestamptz AS end_at
+		),` + tableAvailabilityCTE + `
+		SELECT table_id AS id, total_seats AS seats_count, reserved_seats
+		FROM table_availability
+		ORDER BY table_id ASC;
+	`
+
+	var floor allocation.Floor
+	err := tx.Raw(query, startAt, endAt, excludeReservationID, reservation.ActiveStatuses()).Scan(&floor.Tables).Error
+	if err != nil {
+		return allocation.Floor{}, err
+	}
+
+	var groupTables []struct {
+		TableGroupID int
+		TableID      uint
+	}
+	err = tx.Raw(`
+		SELECT table_group_id, table_id
+		FROM table_group_tables
+		WHERE table_group_id NOT IN (
+			SELECT gt.table_group_id
+			FROM table_group_tables gt
+			JOIN tables t ON t.id = gt.table_id
+			WHERE t.retired_at IS NOT NULL
+		)
+		ORDER BY table_group_id ASC, table_id ASC
+	`).Scan(&groupTables).Error
+	if err != nil {
+		return allocation.Floor{}, err
+	}
+
+	tables := make(map[uint]allocation.Table, len(floor.Tables))
+	for _, t := range floor.Tables {
+		tables[t.ID] = t
+	}
+	for _, gt := range groupTables {
+		last := len(floor.Groups) - 1
+		if last < 0 || floor.Groups[last].ID != gt.TableGroupID {
+			floor.Groups = append(floor.Groups, allocation.Group{ID: gt.TableGroupID})
+			last++
+		}
+		floor.Groups[last].Tables = append(floor.Groups[last].Tables, tables[gt.TableID])
+	}
+
+	return floor, nil
+}
+
+// tableRequest is what a reservation needs from the floor
+type tableRequest struct {
+	seatsNeeded int
+	startAt     time.Time
+	endAt       time.Time
+	// excludeReservationID is not counted as occupying any table, zero disables it
+	excludeReservationID int
+	// preferredTableID wins over the other tables when it fits, zero disables it
+	preferredTableID uint
+	// unavailableTableID and the groups it belongs to are never allocated, zero disables it
+	unavailableTableID uint
+}
+
+// floor loads the floor for the request
+func (req tableRequest) floor(tx *gorm.DB) (allocation.Floor, error) {
+	floor, err := loadFloor(tx, req.startAt, req.endAt, req.excludeReservationID)
+	if err != nil {
+		return allocation.Floor{}, err
+	}
+	if req.unavailableTableID != 0 {
+		floor = floor.WithoutTable(req.unavailableTableID)
+	}
+	floor.PreferredTableID = req.preferredTableID
+	return floor, nil
+}
+
+// reserveTables lets the allocator pick the tables for a reservation and locks them until the transaction ends,
+// so concurrent transactions can not book the same seats. A concurrent transaction may have booked the picked
+// tables before the lock was acquired, so the floor is loaded again under the lock, which sees the reservations
+// committed in the meantime, and the allocation is repeated until the locked tables still fit.
+func reserveTables(tx *gorm.DB, allocator allocation.Allocator, req tableRequest) (allocatedTables, error) {
+	floor, err := req.floor(tx)
+	if err != nil {
+		return allocatedTables{}, err
+	}
+
+	tables, err := allocator.Allocate(req.seatsNeeded, floor)
+	if err != nil {
+		return allocatedTables{}, err
+	}
+
+	for {
+		allocated := allocatedTables{tables: tables}
+		if err := lockTables(tx, allocated.tableIDs()); err != nil {
+			return allocatedTables{}, err
+		}
+
+		floor, err = req.floor(tx)
+		if err != nil {
+			return allocatedTables{}, err
+		}
+
+		if floor.Fits(tables) {
+			allocated.totalSeats = make(map[uint]int, len(floor.Tables))
+			for _, t := range floor.Tables {
+				allocated.totalSeats[t.ID] = t.SeatsCount
+			}
+			return allocated, nil
+		}
+
+		tables, err = allocator.Allocate(req.seatsNeeded, floor)
+		if err != nil {
+			return allocatedTables{}, err
+		}
+	}
+}
+
+// loadSettingsHistory loads every version of the table settings ordered by the time they take effect
+func loadSettingsHistory(tx *gorm.DB) (table.SettingsHistory, error) {
+	var history table.SettingsHistory
+	if err := tx.Order("effective_from").Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// lockTables locks the rows of the given tables, in the order of their IDs to avoid deadlocks
+func lockTables(tx *gorm.DB, tableIDs []uint) error {
+	return tx.Exec("SELECT id FROM tables WHERE id IN ? ORDER BY id FOR UPDATE", tableIDs).Error
+}
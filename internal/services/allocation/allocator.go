@@ -0,0 +1,141 @@
+package allocation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+const (
+	StrategyBestFit             = "best_fit"
+	StrategyFirstFit            = "first_fit"
+	StrategyKeepLargeTablesFree = "keep_large_tables_free"
+	StrategyLeastSharedTable    = "least_shared_table"
+)
+
+// Allocator decides which tables a party takes
+type Allocator interface {
+	// Allocate picks the tables for a party of seatsNeeded on the floor and the seats taken at each of them,
+	// it returns reservation.ErrNoTablesAreAvailable when the party fits nowhere
+	Allocate(seatsNeeded int, floor Floor) ([]reservation.ReservationTable, error)
+}
+
+// New creates the allocator of the given strategy, best fit when the strategy is empty
+func New(strategy string) (Allocator, error) {
+	switch strategy {
+	case StrategyBestFit, "":
+		return NewBestFitAllocator(), nil
+	case StrategyFirstFit:
+		return NewFirstFitAllocator(), nil
+	case StrategyKeepLargeTablesFree:
+		return NewKeepLargeTablesFreeAllocator(), nil
+	case StrategyLeastSharedTable:
+		return NewLeastSharedTableAllocator(), nil
+	default:
+		return nil, fmt.Errorf("unknown allocation strategy %q", strategy)
+	}
+}
+
+// NewBestFitAllocator creates an allocator that picks the table with the fewest available seats that fits
+func NewBestFitAllocator() Allocator {
+	return &singleTableAllocator{less: func(a, b Table) bool {
+		return a.AvailableSeats() < b.AvailableSeats()
+	}}
+}
+
+// NewFirstFitAllocator creates an allocator that picks the first table, by ID, that fits
+func NewFirstFitAllocator() Allocator {
+	return &singleTableAllocator{less: func(a, b Table) bool {
+		return false
+	}}
+}
+
+// NewKeepLargeTablesFreeAllocator creates an allocator that picks the smallest table that fits,
+// keeping large tables free for large parties even when they are partly booked
+func NewKeepLargeTablesFreeAllocator() Allocator {
+	return &singleTableAllocator{less: func(a, b Table) bool {
+		if a.SeatsCount != b.SeatsCount {
+			return a.SeatsCount < b.SeatsCount
+		}
+		return a.AvailableSeats() < b.AvailableSeats()
+	}}
+}
+
+// NewLeastSharedTableAllocator creates an allocator that picks the table with the fewest seats taken
+// by other parties, so parties share tables as little as possible
+func NewLeastSharedTableAllocator() Allocator {
+	return &singleTableAllocator{less: func(a, b Table) bool {
+		if a.ReservedSeats != b.ReservedSeats {
+			return a.ReservedSeats < b.ReservedSeats
+		}
+		return a.AvailableSeats() < b.AvailableSeats()
+	}}
+}
+
+// singleTableAllocator picks the preferred table of the floor when it fits, otherwise the first fitting
+// table ordered by less and then by ID. When no single table fits, the smallest free group of joinable
+// tables that fits is taken as a whole.
+type singleTableAllocator struct {
+	less func(a, b Table) bool
+}
+
+// Allocate picks the tables for a party of seatsNeeded on the floor
+func (a *singleTableAllocator) Allocate(seatsNeeded int, floor Floor) ([]reservation.ReservationTable, error) {
+	var candidates []Table
+	for _, t := range floor.Tables {
+		if t.AvailableSeats() < seatsNeeded {
+			continue
+		}
+		if floor.PreferredTableID != 0 && t.ID == floor.PreferredTableID {
+			return []reservation.ReservationTable{{TableID: t.ID, SeatsCount: seatsNeeded}}, nil
+		}
+		candidates = append(candidates, t)
+	}
+
+	if len(candidates) > 0 {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if a.less(candidates[i], candidates[j]) {
+				return true
+			}
+			if a.less(candidates[j], candidates[i]) {
+				return false
+			}
+			return candidates[i].ID < candidates[j].ID
+		})
+		return []reservation.ReservationTable{{TableID: candidates[0].ID, SeatsCount: seatsNeeded}}, nil
+	}
+
+	return allocateGroup(seatsNeeded, floor.Groups)
+}
+
+// allocateGroup picks the free group of joinable tables with the fewest seats, and then the fewest tables,
+// that fits seatsNeeded. Every table of the group is taken as a whole, so none of its seats can be sold
+// to another party.
+func allocateGroup(seatsNeeded int, groups []Group) ([]reservation.ReservationTable, error) {
+	var candidates []Group
+	for _, g := range groups {
+		if g.IsFree() && g.SeatsCount() >= seatsNeeded {
+			candidates = append(candidates, g)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, reservation.ErrNoTablesAreAvailable
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].SeatsCount() != candidates[j].SeatsCount() {
+			return candidates[i].SeatsCount() < candidates[j].SeatsCount()
+		}
+		if len(candidates[i].Tables) != len(candidates[j].Tables) {
+			return len(candidates[i].Tables) < len(candidates[j].Tables)
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	tables := make([]reservation.ReservationTable, 0, len(candidates[0].Tables))
+	for _, t := range candidates[0].Tables {
+		tables = append(tables, reservation.ReservationTable{TableID: t.ID, SeatsCount: t.SeatsCount})
+	}
+	return tables, nil
+}
@@ -0,0 +1,155 @@
+package allocation_test
+
+import (
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/allocation"
+	"github.com/stretchr/testify/require"
+)
+
+// floor has a partly booked 6-top, a free 4-top, a partly booked 8-top and a free 6-top
+func floor() allocation.Floor {
+	return allocation.Floor{
+		Tables: []allocation.Table{
+			{ID: 1, SeatsCount: 6, ReservedSeats: 2},
+			{ID: 2, SeatsCount: 4},
+			{ID: 3, SeatsCount: 8, ReservedSeats: 4},
+			{ID: 4, SeatsCount: 6},
+		},
+	}
+}
+
+func TestAllocators(t *testing.T) {
+	testCases := []struct {
+		name        string
+		allocator   allocation.Allocator
+		seatsNeeded int
+		tableID     uint
+	}{
+		{
+			name:        "BestFit",
+			allocator:   allocation.NewBestFitAllocator(),
+			seatsNeeded: 4,
+			tableID:     1,
+		},
+		{
+			name:        "FirstFit",
+			allocator:   allocation.NewFirstFitAllocator(),
+			seatsNeeded: 4,
+			tableID:     1,
+		},
+		{
+			name:        "FirstFitSkipsTablesTooSmall",
+			allocator:   allocation.NewFirstFitAllocator(),
+			seatsNeeded: 6,
+			tableID:     4,
+		},
+		{
+			name:        "KeepLargeTablesFree",
+			allocator:   allocation.NewKeepLargeTablesFreeAllocator(),
+			seatsNeeded: 4,
+			tableID:     2,
+		},
+		{
+			name:        "KeepLargeTablesFreePrefersFewerAvailableSeatsAmongSameSize",
+			allocator:   allocation.NewKeepLargeTablesFreeAllocator(),
+			seatsNeeded: 2,
+			tableID:     2,
+		},
+		{
+			name:        "LeastSharedTable",
+			allocator:   allocation.NewLeastSharedTableAllocator(),
+			seatsNeeded: 4,
+			tableID:     2,
+		},
+		{
+			name:        "LeastSharedTablePrefersFewerAvailableSeatsAmongUnshared",
+			allocator:   allocation.NewLeastSharedTableAllocator(),
+			seatsNeeded: 2,
+			tableID:     2,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			tables, err := tc.allocator.Allocate(tc.seatsNeeded, floor())
+			require.NoError(t, err)
+			require.Equal(t, []reservation.ReservationTable{{TableID: tc.tableID, SeatsCount: tc.seatsNeeded}}, tables)
+		})
+	}
+}
+
+func TestAllocatorPrefersPreferredTable(t *testing.T) {
+	f := floor()
+	f.PreferredTableID = 3
+
+	tables, err := allocation.NewBestFitAllocator().Allocate(4, f)
+	require.NoError(t, err)
+	require.Equal(t, []reservation.ReservationTable{{TableID: 3, SeatsCount: 4}}, tables)
+
+	// a preferred table that does not fit is ignored
+	tables, err = allocation.NewBestFitAllocator().Allocate(6, f)
+	require.NoError(t, err)
+	require.Equal(t, []reservation.ReservationTable{{TableID: 4, SeatsCount: 6}}, tables)
+}
+
+func TestAllocatorCombinesGroupOfTables(t *testing.T) {
+	f := floor()
+	f.Groups = []allocation.Group{
+		// table 1 is partly booked, so the group is not free
+		{ID: 1, Tables: []allocation.Table{f.Tables[0], f.Tables[3]}},
+		{ID: 2, Tables: []allocation.Table{f.Tables[1], f.Tables[3]}},
+		{ID: 3, Tables: []allocation.Table{f.Tables[1], f.Tables[3], {ID: 5, SeatsCount: 2}}},
+	}
+
+	tables, err := allocation.NewBestFitAllocator().Allocate(9, f)
+	require.NoError(t, err)
+	require.Equal(t, []reservation.ReservationTable{{TableID: 2, SeatsCount: 4}, {TableID: 4, SeatsCount: 6}}, tables)
+
+	tables, err = allocation.NewBestFitAllocator().Allocate(12, f)
+	require.NoError(t, err)
+	require.Equal(t, []reservation.ReservationTable{{TableID: 2, SeatsCount: 4}, {TableID: 4, SeatsCount: 6}, {TableID: 5, SeatsCount: 2}}, tables)
+
+	_, err = allocation.NewBestFitAllocator().Allocate(14, f)
+	require.ErrorIs(t, err, reservation.ErrNoTablesAreAvailable)
+}
+
+func TestFloorFits(t *testing.T) {
+	f := floor()
+
+	require.True(t, f.Fits([]reservation.ReservationTable{{TableID: 1, SeatsCount: 4}}))
+	require.False(t, f.Fits([]reservation.ReservationTable{{TableID: 1, SeatsCount: 5}}))
+	require.False(t, f.Fits([]reservation.ReservationTable{{TableID: 2, SeatsCount: 4}, {TableID: 3, SeatsCount: 8}}))
+	require.False(t, f.Fits([]reservation.ReservationTable{{TableID: 9, SeatsCount: 1}}))
+}
+
+func TestFloorWithoutTable(t *testing.T) {
+	f := floor()
+	f.Groups = []allocation.Group{
+		{ID: 1, Tables: []allocation.Table{f.Tables[1], f.Tables[3]}},
+		{ID: 2, Tables: []allocation.Table{f.Tables[0], f.Tables[2]}},
+	}
+
+	without := f.WithoutTable(4)
+	require.Equal(t, []allocation.Table{f.Tables[0], f.Tables[1], f.Tables[2]}, without.Tables)
+	require.Equal(t, []allocation.Group{f.Groups[1]}, without.Groups)
+
+	// without the free tables the party has to share a partly booked one
+	tables, err := allocation.NewBestFitAllocator().Allocate(4, without.WithoutTable(2))
+	require.NoError(t, err)
+	require.Equal(t, []reservation.ReservationTable{{TableID: 1, SeatsCount: 4}}, tables)
+}
+
+func TestNew(t *testing.T) {
+	for _, strategy := range []string{"", allocation.StrategyBestFit, allocation.StrategyFirstFit, allocation.StrategyKeepLargeTablesFree, allocation.StrategyLeastSharedTable} {
+		allocator, err := allocation.New(strategy)
+		require.NoError(t, err)
+		require.NotNil(t, allocator)
+	}
+
+	_, err := allocation.New("random")
+	require.Error(t, err)
+}
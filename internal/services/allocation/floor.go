@@ -0,0 +1,91 @@
+package allocation
+
+import "github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+
+// Table is a table and the seats already taken at it in the requested time slot
+type Table struct {
+	ID            uint
+	SeatsCount    int
+	ReservedSeats int
+}
+
+// AvailableSeats returns the seats of the table that are still free
+func (t Table) AvailableSeats() int {
+	return t.SeatsCount - t.ReservedSeats
+}
+
+// Group is a group of joinable tables
+type Group struct {
+	ID     int
+	Tables []Table
+}
+
+// SeatsCount returns the seats of all the tables of the group
+func (g Group) SeatsCount() int {
+	seats := 0
+	for _, t := range g.Tables {
+		seats += t.SeatsCount
+	}
+	return seats
+}
+
+// IsFree reports whether no seat of any table of the group is taken
+func (g Group) IsFree() bool {
+	for _, t := range g.Tables {
+		if t.ReservedSeats > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTable reports whether the table with the given ID belongs to the group
+func (g Group) hasTable(tableID uint) bool {
+	for _, t := range g.Tables {
+		if t.ID == tableID {
+			return true
+		}
+	}
+	return false
+}
+
+// Floor is the state of all the tables in the requested time slot
+type Floor struct {
+	Tables []Table
+	Groups []Group
+	// PreferredTableID is taken whenever it fits the party, zero means no preference
+	PreferredTableID uint
+}
+
+// Fits reports whether the seats allocated at every table are still available on the floor
+func (f Floor) Fits(tables []reservation.ReservationTable) bool {
+	available := make(map[uint]int, len(f.Tables))
+	for _, t := range f.Tables {
+		available[t.ID] = t.AvailableSeats()
+	}
+
+	for _, t := range tables {
+		seats, ok := available[t.TableID]
+		if !ok || seats < t.SeatsCount {
+			return false
+		}
+	}
+	return true
+}
+
+// WithoutTable returns the floor without the table with the given ID and the groups it belongs to
+func (f Floor) WithoutTable(tableID uint) Floor {
+	floor := Floor{PreferredTableID: f.PreferredTableID}
+	for _, t := range f.Tables {
+		if t.ID != tableID {
+			floor.Tables = append(floor.Tables, t)
+		}
+	}
+
+	for _, g := range f.Groups {
+		if !g.hasTable(tableID) {
+			floor.Groups = append(floor.Groups, g)
+		}
+	}
+	return floor
+}
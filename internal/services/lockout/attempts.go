@@ -0,0 +1,37 @@
+package lockout
+
+import (
+	"errors"
+	"time"
+)
+
+// Kind is what failed logins are counted against
+type Kind string
+
+const (
+	// KindAccount counts the failed logins of a username, whether or not an account has it
+	KindAccount Kind = "account"
+	// KindIP counts the failed logins coming from an IP address
+	KindIP Kind = "ip"
+)
+
+var ErrAttemptsNotFound = errors.New("no failed login attempts found")
+
+// IsValid reports whether the kind is a known one
+func (k Kind) IsValid() bool {
+	return k == KindAccount || k == KindIP
+}
+
+// Attempts are the failed logins of an account or IP address since they were last cleared
+type Attempts struct {
+	Kind          Kind       `gorm:"type:varchar;primaryKey"`
+	Subject       string     `gorm:"type:varchar;primaryKey"`
+	Failures      int        `gorm:"type:int,NOT NULL"`
+	LastFailureAt time.Time  `gorm:"type:timestamptz,NOT NULL"`
+	LockedUntil   *time.Time `gorm:"type:timestamptz"`
+}
+
+// TableName returns the table name
+func (a Attempts) TableName() string {
+	return "login_attempts"
+}
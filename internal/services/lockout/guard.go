@@ -0,0 +1,137 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often the guard clears the attempts that have been forgotten
+const pruneInterval = time.Minute
+
+// ThrottledError is returned for a login that has to wait before it is attempted
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry after %s", e.RetryAfter)
+}
+
+// Guard throttles logins by the failed attempts of their account and of the IP address they come from. Failures
+// are counted for every username, whether or not an account has it, so throttling does not tell them apart.
+type Guard struct {
+	store    Store
+	policies map[Kind]Policy
+
+	mu           sync.Mutex
+	lastPrunedAt time.Time
+}
+
+// NewGuard creates a new Guard
+func NewGuard(store Store, accountPolicy Policy, ipPolicy Policy) *Guard {
+	return &Guard{
+		store:    store,
+		policies: map[Kind]Policy{KindAccount: accountPolicy, KindIP: ipPolicy},
+	}
+}
+
+// Check returns a ThrottledError when the account or the IP address has to wait before the next login attempt
+func (g *Guard) Check(ctx context.Context, username string, ip string) error {
+	now := time.Now()
+
+	var retryAfter time.Duration
+	for _, key := range loginKeys(username, ip) {
+		a, err := g.store.Get(ctx, key.kind, key.subject)
+		if err != nil {
+			return err
+		}
+		retryAfter = max(retryAfter, g.policies[key.kind].RetryAfter(a, now))
+	}
+
+	if retryAfter > 0 {
+		return &ThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login against the account and the IP address
+func (g *Guard) RecordFailure(ctx context.Context, username string, ip string) error {
+	now := time.Now()
+	for _, key := range loginKeys(username, ip) {
+		policy := g.policies[key.kind]
+		err := g.store.Update(ctx, key.kind, key.subject, func(a *Attempts) {
+			policy.RecordFailure(a, now)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return g.prune(ctx, now)
+}
+
+// RecordSuccess clears the failures of the account, the failures of the IP address are kept since whoever guesses
+// passwords could clear them by logging in to an account of their own
+func (g *Guard) RecordSuccess(ctx context.Context, username string) error {
+	err := g.store.Delete(ctx, KindAccount, username)
+	if err != nil && !errors.Is(err, ErrAttemptsNotFound) {
+		return err
+	}
+	return nil
+}
+
+// Throttled returns the accounts and IP addresses that currently have to wait before their next login attempt
+func (g *Guard) Throttled(ctx context.Context) ([]Attempts, error) {
+	all, err := g.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	throttled := make([]Attempts, 0)
+	for _, a := range all {
+		if g.RetryAfter(a, now) > 0 {
+			throttled = append(throttled, a)
+		}
+	}
+	return throttled, nil
+}
+
+// RetryAfter returns how long the next login attempt of the account or IP address has to wait from the given time
+func (g *Guard) RetryAfter(a Attempts, at time.Time) time.Duration {
+	return g.policies[a.Kind].RetryAfter(a, at)
+}
+
+// Clear forgets the failed logins of an account or IP address, ErrAttemptsNotFound when there are none
+func (g *Guard) Clear(ctx context.Context, kind Kind, subject string) error {
+	return g.store.Delete(ctx, kind, subject)
+}
+
+// prune clears the attempts every policy has forgotten, at most once every pruneInterval
+func (g *Guard) prune(ctx context.Context, now time.Time) error {
+	var horizon time.Duration
+	for _, policy := range g.policies {
+		// a policy that never forgets failures keeps every attempt
+		if policy.ResetAfter <= 0 {
+			return nil
+		}
+		horizon = max(horizon, policy.ResetAfter, policy.LockoutDuration)
+	}
+
+	g.mu.Lock()
+	if now.Sub(g.lastPrunedAt) < pruneInterval {
+		g.mu.Unlock()
+		return nil
+	}
+	g.lastPrunedAt = now
+	g.mu.Unlock()
+
+	return g.store.Prune(ctx, now.Add(-horizon))
+}
+
+func loginKeys(username string, ip string) []storeKey {
+	return []storeKey{{KindAccount, username}, {KindIP, ip}}
+}
@@ -0,0 +1,65 @@
+package lockout_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuard(t *testing.T) {
+	ctx := context.Background()
+	accountPolicy := lockout.Policy{MaxFailures: 3, LockoutDuration: time.Hour, ResetAfter: time.Hour}
+	ipPolicy := lockout.Policy{MaxFailures: 5, LockoutDuration: time.Hour, ResetAfter: time.Hour}
+	guard := lockout.NewGuard(lockout.NewMemoryStore(), accountPolicy, ipPolicy)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, guard.Check(ctx, "alice", "192.0.2.1"))
+		require.NoError(t, guard.RecordFailure(ctx, "alice", "192.0.2.1"))
+	}
+
+	// the account is locked out from every IP address
+	var throttled *lockout.ThrottledError
+	require.True(t, errors.As(guard.Check(ctx, "alice", "192.0.2.2"), &throttled))
+	require.InDelta(t, time.Hour, throttled.RetryAfter, float64(time.Second))
+
+	// the IP address is locked out for every account once it fails often enough
+	require.NoError(t, guard.Check(ctx, "bob", "192.0.2.1"))
+	require.NoError(t, guard.RecordFailure(ctx, "bob", "192.0.2.1"))
+	require.NoError(t, guard.RecordFailure(ctx, "bob", "192.0.2.1"))
+	require.True(t, errors.As(guard.Check(ctx, "carol", "192.0.2.1"), &throttled))
+	require.NoError(t, guard.Check(ctx, "carol", "192.0.2.3"))
+
+	locked, err := guard.Throttled(ctx)
+	require.NoError(t, err)
+	require.Len(t, locked, 2)
+	require.Equal(t, lockout.KindAccount, locked[0].Kind)
+	require.Equal(t, "alice", locked[0].Subject)
+	require.Equal(t, lockout.KindIP, locked[1].Kind)
+	require.Equal(t, "192.0.2.1", locked[1].Subject)
+
+	require.NoError(t, guard.Clear(ctx, lockout.KindAccount, "alice"))
+	require.NoError(t, guard.Check(ctx, "alice", "192.0.2.2"))
+	require.ErrorIs(t, guard.Clear(ctx, lockout.KindAccount, "alice"), lockout.ErrAttemptsNotFound)
+}
+
+func TestGuardRecordSuccess(t *testing.T) {
+	ctx := context.Background()
+	policy := lockout.Policy{MaxFailures: 2, LockoutDuration: time.Hour, ResetAfter: time.Hour}
+	guard := lockout.NewGuard(lockout.NewMemoryStore(), policy, lockout.Policy{MaxFailures: 2, LockoutDuration: time.Hour})
+
+	require.NoError(t, guard.RecordFailure(ctx, "alice", "192.0.2.1"))
+	require.NoError(t, guard.RecordSuccess(ctx, "alice"))
+
+	// the account starts over, the IP address does not
+	require.NoError(t, guard.RecordFailure(ctx, "alice", "192.0.2.2"))
+	require.NoError(t, guard.Check(ctx, "alice", "192.0.2.3"))
+	require.NoError(t, guard.RecordFailure(ctx, "bob", "192.0.2.1"))
+	require.Error(t, guard.Check(ctx, "carol", "192.0.2.1"))
+
+	// succeeding without any failure is fine
+	require.NoError(t, guard.RecordSuccess(ctx, "dave"))
+}
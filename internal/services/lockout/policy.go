@@ -0,0 +1,65 @@
+package lockout
+
+import "time"
+
+// Policy is how the failed logins of an account or IP address are throttled, the zero Policy never throttles
+type Policy struct {
+	// FreeFailures is how many failures are allowed before further attempts are delayed
+	FreeFailures int
+	// BaseDelay is the wait after the first failure past FreeFailures, doubled by every further failure up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxFailures locks out further attempts for LockoutDuration, 0 never locks out
+	MaxFailures     int
+	LockoutDuration time.Duration
+	// ResetAfter is how long after the last failure the failures are forgotten
+	ResetAfter time.Duration
+}
+
+// isStale reports whether the failures have been forgotten by the given time
+func (p Policy) isStale(a Attempts, at time.Time) bool {
+	return p.ResetAfter > 0 && !at.Before(a.LastFailureAt.Add(p.ResetAfter))
+}
+
+// RetryAfter returns how long the next attempt has to wait from the given time, 0 when it does not have to
+func (p Policy) RetryAfter(a Attempts, at time.Time) time.Duration {
+	if a.Failures == 0 || p.isStale(a, at) {
+		return 0
+	}
+
+	if a.LockedUntil != nil && a.LockedUntil.After(at) {
+		return a.LockedUntil.Sub(at)
+	}
+
+	if a.Failures <= p.FreeFailures || p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeFailures + 1; i < a.Failures && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if wait := a.LastFailureAt.Add(delay).Sub(at); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// RecordFailure counts a failure at the given time, locking out further attempts once there are MaxFailures
+func (p Policy) RecordFailure(a *Attempts, at time.Time) {
+	if p.isStale(*a, at) {
+		a.Failures = 0
+		a.LockedUntil = nil
+	}
+
+	a.Failures++
+	a.LastFailureAt = at
+	if p.MaxFailures > 0 && a.Failures >= p.MaxFailures {
+		lockedUntil := at.Add(p.LockoutDuration)
+		a.LockedUntil = &lockedUntil
+	}
+}
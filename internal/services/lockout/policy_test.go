@@ -0,0 +1,78 @@
+package lockout_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := lockout.Policy{
+		FreeFailures:    2,
+		BaseDelay:       time.Second,
+		MaxDelay:        4 * time.Second,
+		MaxFailures:     6,
+		LockoutDuration: time.Hour,
+		ResetAfter:      24 * time.Hour,
+	}
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		failures   int
+		retryAfter time.Duration
+	}{
+		{failures: 1, retryAfter: 0},
+		{failures: 2, retryAfter: 0},
+		{failures: 3, retryAfter: time.Second},
+		{failures: 4, retryAfter: 2 * time.Second},
+		{failures: 5, retryAfter: 4 * time.Second},
+		{failures: 6, retryAfter: time.Hour},
+	}
+
+	var a lockout.Attempts
+	for _, tc := range testCases {
+		policy.RecordFailure(&a, start)
+		require.Equal(t, tc.failures, a.Failures)
+		require.Equal(t, tc.retryAfter, policy.RetryAfter(a, start), "after %d failures", tc.failures)
+	}
+
+	// the delay runs from the last failure and the lockout ends on time
+	require.Equal(t, 30*time.Minute, policy.RetryAfter(a, start.Add(30*time.Minute)))
+	require.Zero(t, policy.RetryAfter(a, start.Add(time.Hour)))
+
+	// a failure after the lockout locks out again
+	policy.RecordFailure(&a, start.Add(time.Hour))
+	require.Equal(t, time.Hour, policy.RetryAfter(a, start.Add(time.Hour)))
+
+	// the failures are forgotten a while after the last one
+	later := start.Add(time.Hour + 24*time.Hour)
+	require.Zero(t, policy.RetryAfter(a, later))
+	policy.RecordFailure(&a, later)
+	require.Equal(t, 1, a.Failures)
+	require.Nil(t, a.LockedUntil)
+}
+
+func TestPolicyCapsDelay(t *testing.T) {
+	policy := lockout.Policy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	at := time.Now()
+
+	var a lockout.Attempts
+	for i := 0; i < 100; i++ {
+		policy.RecordFailure(&a, at)
+	}
+	require.Equal(t, 10*time.Second, policy.RetryAfter(a, at))
+	require.Nil(t, a.LockedUntil)
+}
+
+func TestZeroPolicy(t *testing.T) {
+	var policy lockout.Policy
+	at := time.Now()
+
+	var a lockout.Attempts
+	for i := 0; i < 100; i++ {
+		policy.RecordFailure(&a, at)
+	}
+	require.Zero(t, policy.RetryAfter(a, at))
+}
@@ -0,0 +1,113 @@
+package lockout
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store keeps the failed login attempts of every account and IP address
+type Store interface {
+	// Get returns the attempts of the subject, without any failure when there are none
+	Get(ctx context.Context, kind Kind, subject string) (Attempts, error)
+	// Update changes the attempts of the subject, concurrent updates of the same subject are applied one by one
+	Update(ctx context.Context, kind Kind, subject string, update func(a *Attempts)) error
+	// Delete clears the attempts of the subject, ErrAttemptsNotFound when there are none
+	Delete(ctx context.Context, kind Kind, subject string) error
+	// List returns the attempts of every subject ordered by kind and subject
+	List(ctx context.Context) ([]Attempts, error)
+	// Prune clears the attempts whose last failure is before the given time
+	Prune(ctx context.Context, before time.Time) error
+}
+
+type storeKey struct {
+	kind    Kind
+	subject string
+}
+
+// MemoryStore is a Store of a single process
+type MemoryStore struct {
+	mu       sync.Mutex
+	attempts map[storeKey]Attempts
+}
+
+// NewMemoryStore creates a new MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		attempts: make(map[storeKey]Attempts),
+	}
+}
+
+// Get returns the attempts of the subject
+func (s *MemoryStore) Get(_ context.Context, kind Kind, subject string) (Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a, ok := s.attempts[storeKey{kind, subject}]; ok {
+		return a, nil
+	}
+	return Attempts{Kind: kind, Subject: subject}, nil
+}
+
+// Update changes the attempts of the subject
+func (s *MemoryStore) Update(_ context.Context, kind Kind, subject string, update func(a *Attempts)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := storeKey{kind, subject}
+	a, ok := s.attempts[key]
+	if !ok {
+		a = Attempts{Kind: kind, Subject: subject}
+	}
+
+	update(&a)
+	s.attempts[key] = a
+	return nil
+}
+
+// Delete clears the attempts of the subject
+func (s *MemoryStore) Delete(_ context.Context, kind Kind, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := storeKey{kind, subject}
+	if _, ok := s.attempts[key]; !ok {
+		return ErrAttemptsNotFound
+	}
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// List returns the attempts of every subject
+func (s *MemoryStore) List(_ context.Context) ([]Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Attempts, 0, len(s.attempts))
+	for _, a := range s.attempts {
+		list = append(list, a)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+		return list[i].Subject < list[j].Subject
+	})
+	return list, nil
+}
+
+// Prune clears the attempts whose last failure is before the given time
+func (s *MemoryStore) Prune(_ context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, a := range s.attempts {
+		if a.LastFailureAt.Before(before) {
+			delete(s.attempts, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package lockout_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/lockout"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := lockout.NewMemoryStore()
+	now := time.Now()
+
+	a, err := store.Get(ctx, lockout.KindIP, "192.0.2.1")
+	require.NoError(t, err)
+	require.Zero(t, a.Failures)
+
+	for _, subject := range []string{"192.0.2.1", "192.0.2.2"} {
+		require.NoError(t, store.Update(ctx, lockout.KindIP, subject, func(a *lockout.Attempts) {
+			a.Failures++
+			a.LastFailureAt = now
+		}))
+	}
+	require.NoError(t, store.Update(ctx, lockout.KindAccount, "alice", func(a *lockout.Attempts) {
+		a.Failures++
+		a.LastFailureAt = now.Add(-time.Hour)
+	}))
+
+	list, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	require.Equal(t, "alice", list[0].Subject)
+
+	require.NoError(t, store.Prune(ctx, now.Add(-time.Minute)))
+	require.NoError(t, store.Delete(ctx, lockout.KindIP, "192.0.2.1"))
+	require.ErrorIs(t, store.Delete(ctx, lockout.KindAccount, "alice"), lockout.ErrAttemptsNotFound)
+
+	list, err = store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "192.0.2.2", list[0].Subject)
+	require.Equal(t, 1, list[0].Failures)
+}
@@ -0,0 +1,27 @@
+package notification
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
+
+// LogNotifier writes the messages to a log instead of delivering them, for local development
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier creates a new LogNotifier writing to w
+func NewLogNotifier(w io.Writer) *LogNotifier {
+	return &LogNotifier{
+		logger: log.New(w, "", log.LstdFlags),
+	}
+}
+
+// Notify writes the message to the log
+func (n *LogNotifier) Notify(_ context.Context, u user.User, message Message) error {
+	n.logger.Printf("notification to user %d (%s): %s\n%s\n", u.ID, u.Username, message.Subject, message.Body)
+	return nil
+}
@@ -0,0 +1,24 @@
+package notification_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/notification"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	notifier := notification.NewLogNotifier(&buf)
+
+	err := notifier.Notify(context.Background(), user.User{ID: 7, Username: "guest1"}, notification.Message{
+		Subject: "Reset your password",
+		Body:    "token: abc",
+	})
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "notification to user 7 (guest1): Reset your password")
+	require.Contains(t, buf.String(), "token: abc")
+}
@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
+
+// ErrNoAddress is returned by notifiers delivering to an address the user did not set
+var ErrNoAddress = errors.New("user has no address to notify")
+
+// Message is a notification to a user
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to users
+type Notifier interface {
+	Notify(ctx context.Context, u user.User, message Message) error
+}
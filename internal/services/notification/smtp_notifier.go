@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
+
+// SMTPNotifier emails the messages to the users, through a local SMTP stub like Mailpit in development
+type SMTPNotifier struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from mail.Address
+}
+
+// NewSMTPNotifier creates a new SMTPNotifier sending from the from address through the server at host:port, it
+// authenticates when the username is set
+func NewSMTPNotifier(host string, port int, username, password, from string) (*SMTPNotifier, error) {
+	fromAddress, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+
+	n := &SMTPNotifier{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		host: host,
+		from: *fromAddress,
+	}
+	if username != "" {
+		n.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return n, nil
+}
+
+// Notify emails the message to the email of the user
+func (n *SMTPNotifier) Notify(_ context.Context, u user.User, message Message) error {
+	if u.Email == "" {
+		return ErrNoAddress
+	}
+
+	to := mail.Address{Name: u.Name, Address: u.Email}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", to.String())
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(message.Body)
+	msg.WriteString("\r\n")
+
+	return smtp.SendMail(n.addr, n.auth, n.from.Address, []string{to.Address}, msg.Bytes())
+}
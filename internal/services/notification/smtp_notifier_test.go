@@ -0,0 +1,102 @@
+package notification_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/notification"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStub accepts a single mail and sends what it received on the returned channel
+func smtpStub(t *testing.T) (string, int, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		var transcript strings.Builder
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			transcript.WriteString(line)
+
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case command == "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					transcript.WriteString(line)
+				}
+				reply("250 queued")
+			case command == "QUIT":
+				reply("221 bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return host, portNumber, received
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	host, port, received := smtpStub(t)
+	notifier, err := notification.NewSMTPNotifier(host, port, "", "", "Restaurant <noreply@restaurant.test>")
+	require.NoError(t, err)
+
+	err = notifier.Notify(context.Background(), user.User{ID: 7, Name: "Guest", Email: "guest@example.com"}, notification.Message{
+		Subject: "Verify your email",
+		Body:    "token: abc",
+	})
+	require.NoError(t, err)
+
+	mail := <-received
+	require.Contains(t, mail, "MAIL FROM:<noreply@restaurant.test>")
+	require.Contains(t, mail, "RCPT TO:<guest@example.com>")
+	require.Contains(t, mail, "To: \"Guest\" <guest@example.com>")
+	require.Contains(t, mail, "Subject: Verify your email")
+	require.Contains(t, mail, "token: abc")
+}
+
+func TestSMTPNotifierNoAddress(t *testing.T) {
+	notifier, err := notification.NewSMTPNotifier("localhost", 1025, "", "", "noreply@restaurant.test")
+	require.NoError(t, err)
+
+	err = notifier.Notify(context.Background(), user.User{ID: 7}, notification.Message{Subject: "Verify your email"})
+	require.ErrorIs(t, err, notification.ErrNoAddress)
+}
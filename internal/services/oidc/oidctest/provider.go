@@ -0,0 +1,261 @@
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "oidctest"
+
+// User is who signs in at the fake provider
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// authorization is a code the fake provider gave out, waiting to be exchanged
+type authorization struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	user          User
+}
+
+// Provider is a local OpenID Connect provider for the tests, users sign in at it without being asked anything and
+// its ID tokens are signed with a key of its own
+type Provider struct {
+	ClientID     string
+	ClientSecret string
+	// TamperIDToken changes the claims of the ID tokens before they are signed, to test how invalid ones are refused
+	TamperIDToken func(claims jwt.MapClaims)
+
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu             sync.Mutex
+	user           User
+	authorizations map[string]authorization
+}
+
+// NewProvider starts a fake provider for the client, which is stopped with the test
+func NewProvider(t testing.TB, clientID, clientSecret string) *Provider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate the key of the fake provider: %v", err)
+	}
+
+	p := &Provider{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		key:            key,
+		user:           User{Subject: "oidctest-user"},
+		authorizations: make(map[string]authorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.handleDiscovery)
+	mux.HandleFunc("GET /authorize", p.handleAuthorize)
+	mux.HandleFunc("POST /token", p.handleToken)
+	mux.HandleFunc("GET /jwks", p.handleJWKS)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+// Issuer returns the issuer of the provider, where its discovery document is found
+func (p *Provider) Issuer() string {
+	return p.server.URL
+}
+
+// SignIn sets the user who signs in at the provider from now on
+func (p *Provider) SignIn(user User) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.user = user
+}
+
+// Authorize follows the authorization URL like the browser of the user would and returns the callback URL the
+// provider sends the user back to
+func (p *Provider) Authorize(t testing.TB, authorizationURL string) *url.URL {
+	t.Helper()
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	response, err := client.Get(authorizationURL)
+	if err != nil {
+		t.Fatalf("could not authorize: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		t.Fatalf("authorization answered %d", response.StatusCode)
+	}
+	callbackURL, err := url.Parse(response.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid callback URL: %v", err)
+	}
+
+	return callbackURL
+}
+
+func (p *Provider) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.Issuer(),
+		"authorization_endpoint":                p.Issuer() + "/authorize",
+		"token_endpoint":                        p.Issuer() + "/token",
+		"jwks_uri":                              p.Issuer() + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *Provider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || query.Get("redirect_uri") == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("client_id") != p.ClientID {
+		http.Error(w, "unknown client", http.StatusBadRequest)
+		return
+	}
+
+	callbackQuery := redirectURI.Query()
+	callbackQuery.Set("state", query.Get("state"))
+	switch {
+	case query.Get("response_type") != "code":
+		callbackQuery.Set("error", "unsupported_response_type")
+	case !strings.Contains(" "+query.Get("scope")+" ", " openid "):
+		callbackQuery.Set("error", "invalid_scope")
+	case query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "":
+		callbackQuery.Set("error", "invalid_request")
+	default:
+		code := randomString()
+		p.mu.Lock()
+		p.authorizations[code] = authorization{
+			clientID:      query.Get("client_id"),
+			redirectURI:   query.Get("redirect_uri"),
+			nonce:         query.Get("nonce"),
+			codeChallenge: query.Get("code_challenge"),
+			user:          p.user,
+		}
+		p.mu.Unlock()
+		callbackQuery.Set("code", code)
+	}
+
+	redirectURI.RawQuery = callbackQuery.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *Provider) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+	if clientID != p.ClientID || clientSecret != p.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostFormValue("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	// codes are used once
+	code := r.PostFormValue("code")
+	p.mu.Lock()
+	auth, ok := p.authorizations[code]
+	delete(p.authorizations, code)
+	p.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	switch {
+	case !ok, auth.clientID != clientID, auth.redirectURI != r.PostFormValue("redirect_uri"),
+		base64.RawURLEncoding.EncodeToString(challenge[:]) != auth.codeChallenge:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            p.Issuer(),
+		"sub":            auth.user.Subject,
+		"aud":            auth.clientID,
+		"exp":            now.Add(time.Hour).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          auth.nonce,
+		"email":          auth.user.Email,
+		"email_verified": auth.user.EmailVerified,
+		"name":           auth.user.Name,
+	}
+	if p.TamperIDToken != nil {
+		p.TamperIDToken(claims)
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = keyID
+	signedIDToken, err := idToken.SignedString(p.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signedIDToken,
+	})
+}
+
+func (p *Provider) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,36 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// randomValueSize is the number of random bytes of code verifiers and nonces, 43 characters once encoded
+const randomValueSize = 32
+
+// NewCodeVerifier creates a PKCE code verifier (RFC 7636), only its challenge is sent to the provider along with the
+// user until the code is exchanged
+func NewCodeVerifier() (string, error) {
+	return randomValue()
+}
+
+// CodeChallenge returns the S256 challenge of a PKCE code verifier
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// NewNonce creates the nonce an ID token has to carry to be accepted for a sign-in, so that a token issued for
+// another sign-in can not be replayed
+func NewNonce() (string, error) {
+	return randomValue()
+}
+
+func randomValue() (string, error) {
+	b := make([]byte, randomValueSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
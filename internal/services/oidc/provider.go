@@ -0,0 +1,317 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// maxResponseSize is the most read of a response of a provider
+	maxResponseSize = 1 << 20
+	// clockSkew is how far the clock of a provider may be off when checking the times of its ID tokens
+	clockSkew = time.Minute
+)
+
+var (
+	ErrProviderUnavailable = errors.New("identity provider is unavailable")
+	ErrCodeRefused         = errors.New("authorization code was refused by the identity provider")
+	ErrInvalidIDToken      = errors.New("ID token of the identity provider is invalid")
+)
+
+// ProviderConfig is how an OpenID Connect provider is reached and how the restaurant is registered at it
+type ProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback of the restaurant the provider sends the user back to
+	RedirectURL string
+	// Scopes are asked for along with openid
+	Scopes []string
+}
+
+// Claims is who signed in at a provider, as told by its ID token
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider signs users in at an OpenID Connect provider with the authorization code flow and PKCE. The discovery
+// document and the keys of the provider are fetched when first needed, the keys again when a token is signed with
+// a key that is not known yet.
+type Provider struct {
+	config ProviderConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]crypto.PublicKey
+}
+
+// discoveryDocument is the part of the OpenID Provider Metadata the sign-ins need
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// idTokenClaims are the claims of an ID token
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce           string `json:"nonce"`
+	AuthorizedParty string `json:"azp"`
+	Email           string `json:"email"`
+	EmailVerified   bool   `json:"email_verified"`
+	Name            string `json:"name"`
+}
+
+// NewProvider creates a new Provider reached with the client
+func NewProvider(config ProviderConfig, client *http.Client) *Provider {
+	return &Provider{
+		config: config,
+		client: client,
+	}
+}
+
+// Name returns the name of the provider
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// RedirectURL returns the callback of the restaurant the provider sends the user back to
+func (p *Provider) RedirectURL() string {
+	return p.config.RedirectURL
+}
+
+// AuthCodeURL returns the URL of the provider the user signs in at, the provider sends the user back to the redirect
+// URL with the state and a code
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid authorization endpoint: %v", ErrProviderUnavailable, err)
+	}
+
+	scopes := []string{"openid"}
+	for _, scope := range p.config.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange exchanges the code the provider sent the user back with for an ID token, which has to be issued by the
+// provider for the restaurant and carry the nonce of the sign-in
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (Claims, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.config.ClientID)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	var tokenResponse struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxResponseSize)).Decode(&tokenResponse); err != nil {
+		return Claims{}, fmt.Errorf("%w: invalid token response: %v", ErrProviderUnavailable, err)
+	}
+	// a code that was used, expired or does not match the verifier is refused as invalid_grant
+	if response.StatusCode == http.StatusBadRequest && tokenResponse.Error == "invalid_grant" {
+		return Claims{}, fmt.Errorf("%w: %s", ErrCodeRefused, tokenResponse.ErrorDescription)
+	}
+	if response.StatusCode != http.StatusOK {
+		return Claims{}, fmt.Errorf("%w: token endpoint answered %d %s", ErrProviderUnavailable, response.StatusCode, tokenResponse.Error)
+	}
+
+	return p.verifyIDToken(ctx, discovery, tokenResponse.IDToken, nonce)
+}
+
+// verifyIDToken checks the signature, issuer, audience, times and nonce of an ID token
+func (p *Provider) verifyIDToken(ctx context.Context, discovery *discoveryDocument, idToken, nonce string) (Claims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(idToken, &claims, func(t *jwt.Token) (interface{}, error) {
+		keyID, _ := t.Header["kid"].(string)
+		return p.publicKey(ctx, discovery, keyID)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		if errors.Is(err, ErrProviderUnavailable) {
+			return Claims{}, err
+		}
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+	if claims.Nonce != nonce {
+		return Claims{}, fmt.Errorf("%w: nonce does not match the sign-in", ErrInvalidIDToken)
+	}
+	// a token for several audiences has to be issued to the restaurant
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return Claims{}, fmt.Errorf("%w: authorized party is not the client", ErrInvalidIDToken)
+	}
+
+	return Claims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+// discover returns the discovery document of the provider, which has to be of the configured issuer
+func (p *Provider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery discoveryDocument
+	discoveryURL := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, discoveryURL, &discovery); err != nil {
+		return nil, err
+	}
+	if discovery.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("%w: discovery document is of issuer %q", ErrProviderUnavailable, discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: discovery document misses endpoints", ErrProviderUnavailable)
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// publicKey returns the key of the provider with the key ID, or its only key when the token names none
+func (p *Provider) publicKey(ctx context.Context, discovery *discoveryDocument, keyID string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.findKey(keyID); ok {
+		return key, nil
+	}
+
+	// the provider may have rotated its keys since they were fetched
+	var keySet jsonWebKeySet
+	if err := p.getJSON(ctx, discovery.JWKSURI, &keySet); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(keySet.Keys))
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// keys of unsupported types are left out rather than failing the others
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	p.keys = keys
+
+	if key, ok := p.findKey(keyID); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", keyID)
+}
+
+func (p *Provider) findKey(keyID string) (crypto.PublicKey, bool) {
+	if keyID == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[keyID]
+	return key, ok
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s answered %d", ErrProviderUnavailable, url, response.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid response of %s: %v", ErrProviderUnavailable, url, err)
+	}
+
+	return nil
+}
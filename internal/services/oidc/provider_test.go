@@ -0,0 +1,149 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/oidc/oidctest"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "http://localhost:8080/users/oidc/fake/callback"
+
+// signIn signs the user in at the fake provider and returns the code it sends back
+func signIn(t *testing.T, fake *oidctest.Provider, provider *oidc.Provider, state, nonce, codeVerifier string) string {
+	authURL, err := provider.AuthCodeURL(context.Background(), state, nonce, oidc.CodeChallenge(codeVerifier))
+	require.NoError(t, err)
+
+	callbackURL := fake.Authorize(t, authURL)
+	require.Equal(t, state, callbackURL.Query().Get("state"))
+	require.Empty(t, callbackURL.Query().Get("error"))
+	require.NotEmpty(t, callbackURL.Query().Get("code"))
+	return callbackURL.Query().Get("code")
+}
+
+func TestProvider(t *testing.T) {
+	fake := oidctest.NewProvider(t, "restaurant", "secret")
+	fake.SignIn(oidctest.User{Subject: "248289761001", Email: "jane@example.com", EmailVerified: true, Name: "Jane Doe"})
+
+	provider := oidc.NewProvider(oidc.ProviderConfig{
+		Name:         "fake",
+		Issuer:       fake.Issuer(),
+		ClientID:     "restaurant",
+		ClientSecret: "secret",
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"email", "openid", "profile"},
+	}, http.DefaultClient)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+	require.NoError(t, err)
+	parsedAuthURL, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsedAuthURL.Query()
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, "restaurant", query.Get("client_id"))
+	require.Equal(t, redirectURL, query.Get("redirect_uri"))
+	require.Equal(t, "openid email profile", query.Get("scope"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	t.Run("ok", func(t *testing.T) {
+		codeVerifier, err := oidc.NewCodeVerifier()
+		require.NoError(t, err)
+		nonce, err := oidc.NewNonce()
+		require.NoError(t, err)
+
+		code := signIn(t, fake, provider, "state", nonce, codeVerifier)
+		claims, err := provider.Exchange(context.Background(), code, codeVerifier, nonce)
+		require.NoError(t, err)
+		require.Equal(t, oidc.Claims{Subject: "248289761001", Email: "jane@example.com", EmailVerified: true, Name: "Jane Doe"}, claims)
+
+		// a code is exchanged once
+		_, err = provider.Exchange(context.Background(), code, codeVerifier, nonce)
+		require.ErrorIs(t, err, oidc.ErrCodeRefused)
+	})
+
+	t.Run("wrong code verifier", func(t *testing.T) {
+		code := signIn(t, fake, provider, "state", "nonce", "code verifier of the sign-in")
+
+		_, err := provider.Exchange(context.Background(), code, "another code verifier", "nonce")
+		require.ErrorIs(t, err, oidc.ErrCodeRefused)
+	})
+
+	t.Run("wrong nonce", func(t *testing.T) {
+		code := signIn(t, fake, provider, "state", "nonce", "code verifier")
+
+		_, err := provider.Exchange(context.Background(), code, "code verifier", "nonce of another sign-in")
+		require.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	invalidIDTokens := []struct {
+		name   string
+		tamper func(claims jwt.MapClaims)
+	}{
+		{"another audience", func(claims jwt.MapClaims) { claims["aud"] = "another client" }},
+		{"another issuer", func(claims jwt.MapClaims) { claims["iss"] = "https://issuer.example.com" }},
+		{"expired", func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{"without subject", func(claims jwt.MapClaims) { delete(claims, "sub") }},
+		{"several audiences without authorized party", func(claims jwt.MapClaims) {
+			claims["aud"] = []string{"restaurant", "another client"}
+		}},
+	}
+	for _, tc := range invalidIDTokens {
+		t.Run(tc.name, func(t *testing.T) {
+			fake.TamperIDToken = tc.tamper
+			defer func() { fake.TamperIDToken = nil }()
+
+			code := signIn(t, fake, provider, "state", "nonce", "code verifier")
+			_, err := provider.Exchange(context.Background(), code, "code verifier", "nonce")
+			require.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestProviderUnavailable(t *testing.T) {
+	fake := oidctest.NewProvider(t, "restaurant", "secret")
+
+	testCases := []struct {
+		name   string
+		config oidc.ProviderConfig
+	}{
+		{
+			name:   "unreachable",
+			config: oidc.ProviderConfig{Issuer: "http://127.0.0.1:1", ClientID: "restaurant", RedirectURL: redirectURL},
+		},
+		{
+			// the discovery document has to be of the configured issuer
+			name:   "issuer mismatch",
+			config: oidc.ProviderConfig{Issuer: fake.Issuer() + "/", ClientID: "restaurant", RedirectURL: redirectURL},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := oidc.NewProvider(tc.config, http.DefaultClient)
+
+			_, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+			require.ErrorIs(t, err, oidc.ErrProviderUnavailable)
+		})
+	}
+
+	t.Run("wrong client secret", func(t *testing.T) {
+		provider := oidc.NewProvider(oidc.ProviderConfig{
+			Issuer: fake.Issuer(), ClientID: "restaurant", ClientSecret: "wrong", RedirectURL: redirectURL,
+		}, http.DefaultClient)
+
+		code := signIn(t, fake, provider, "state", "nonce", "code verifier")
+		_, err := provider.Exchange(context.Background(), code, "code verifier", "nonce")
+		require.ErrorIs(t, err, oidc.ErrProviderUnavailable)
+	})
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// the example of RFC 7636 appendix B
+	require.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", oidc.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
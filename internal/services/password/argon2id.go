@@ -0,0 +1,119 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+var errInvalidArgon2idHash = errors.New("invalid argon2id hash")
+
+// Argon2idParams are the costs of an Argon2id hash
+type Argon2idParams struct {
+	// Memory is the memory used in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Argon2id hashes passwords with Argon2id, encoded in the PHC string format, e.g.
+// $argon2id$v=19$m=65536,t=3,p=2$c2FsdA$aGFzaA
+type Argon2id struct {
+	params Argon2idParams
+}
+
+// NewArgon2id creates an Argon2id hashing with the given parameters
+func NewArgon2id(params Argon2idParams) (*Argon2id, error) {
+	if params.Iterations < 1 || params.Parallelism < 1 {
+		return nil, errors.New("argon2id iterations and parallelism must be at least 1")
+	}
+	if params.Memory < 8*uint32(params.Parallelism) {
+		return nil, fmt.Errorf("argon2id memory must be at least %d KiB", 8*uint32(params.Parallelism))
+	}
+	if params.SaltLength < 8 || params.KeyLength < 16 {
+		return nil, errors.New("argon2id salt must be at least 8 bytes and key at least 16 bytes long")
+	}
+
+	return &Argon2id{params: params}, nil
+}
+
+// Identifies reports whether the encoded hash is an Argon2id hash
+func (a *Argon2id) Identifies(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, argon2idPrefix)
+}
+
+// Hash hashes the password with a random salt
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("could not hash the provided password: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.params.Iterations, a.params.Memory, a.params.Parallelism, a.params.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		a.params.Memory,
+		a.params.Iterations,
+		a.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Compare reports whether the password matches the encoded hash, with the parameters the hash was made with
+func (a *Argon2id) Compare(encodedHash, password string) bool {
+	params, salt, key, err := decodeArgon2id(encodedHash)
+	if err != nil {
+		return false
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+// NeedsRehash reports whether the encoded hash was made with other parameters
+func (a *Argon2id) NeedsRehash(encodedHash string) bool {
+	params, _, _, err := decodeArgon2id(encodedHash)
+	return err != nil || params != a.params
+}
+
+func decodeArgon2id(encodedHash string) (Argon2idParams, []byte, []byte, error) {
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2idParams{}, nil, nil, errInvalidArgon2idHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2idParams{}, nil, nil, errInvalidArgon2idHash
+	}
+
+	var params Argon2idParams
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil || params.Iterations < 1 || params.Parallelism < 1 {
+		return Argon2idParams{}, nil, nil, errInvalidArgon2idHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, errInvalidArgon2idHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2idParams{}, nil, nil, errInvalidArgon2idHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,53 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Bcrypt hashes passwords with bcrypt, only the first 72 bytes of a password count
+type Bcrypt struct {
+	cost int
+}
+
+// NewBcrypt creates a Bcrypt hashing with the given cost
+func NewBcrypt(cost int) (*Bcrypt, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	return &Bcrypt{cost: cost}, nil
+}
+
+// Identifies reports whether the encoded hash is a bcrypt hash
+func (b *Bcrypt) Identifies(encodedHash string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(encodedHash, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hash hashes the password
+func (b *Bcrypt) Hash(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", fmt.Errorf("could not hash the provided password: %w", err)
+	}
+
+	return string(hashedPassword), nil
+}
+
+// Compare reports whether the password matches the encoded hash
+func (b *Bcrypt) Compare(encodedHash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password)) == nil
+}
+
+// NeedsRehash reports whether the encoded hash was made with another cost
+func (b *Bcrypt) NeedsRehash(encodedHash string) bool {
+	cost, err := bcrypt.Cost([]byte(encodedHash))
+	return err != nil || cost != b.cost
+}
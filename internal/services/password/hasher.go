@@ -0,0 +1,49 @@
+package password
+
+// Algorithm is a way of hashing passwords, its hashes are encoded with the parameters they were made with and a
+// prefix telling the algorithm
+type Algorithm interface {
+	// Identifies reports whether the encoded hash was made by the algorithm
+	Identifies(encodedHash string) bool
+	Hash(password string) (string, error)
+	Compare(encodedHash, password string) bool
+	// NeedsRehash reports whether the encoded hash was made with other parameters than the current ones
+	NeedsRehash(encodedHash string) bool
+}
+
+// Hasher hashes new passwords with the current algorithm and verifies the hashes of every algorithm it knows, so
+// that the hashes of an earlier algorithm keep working until they are replaced
+type Hasher struct {
+	current    Algorithm
+	algorithms []Algorithm
+}
+
+// NewHasher creates a Hasher hashing with the current algorithm, the other algorithms are only used to verify the
+// hashes they made
+func NewHasher(current Algorithm, others ...Algorithm) *Hasher {
+	return &Hasher{
+		current:    current,
+		algorithms: append([]Algorithm{current}, others...),
+	}
+}
+
+// Hash hashes the password with the current algorithm
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify reports whether the password matches the encoded hash, and if it does, whether the hash should be replaced
+// by a hash of the current algorithm and parameters. Hashes of an unknown algorithm match no password.
+func (h *Hasher) Verify(encodedHash, password string) (valid bool, needsRehash bool) {
+	for _, algorithm := range h.algorithms {
+		if !algorithm.Identifies(encodedHash) {
+			continue
+		}
+		if !algorithm.Compare(encodedHash, password) {
+			return false, false
+		}
+		return true, algorithm != h.current || algorithm.NeedsRehash(encodedHash)
+	}
+
+	return false, false
+}
@@ -0,0 +1,102 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheapArgon2id are parameters cheap enough for the tests
+var cheapArgon2id = password.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestArgon2id(t *testing.T) {
+	argon2id, err := password.NewArgon2id(cheapArgon2id)
+	require.NoError(t, err)
+
+	hash, err := argon2id.Hash("the password")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+	require.True(t, argon2id.Identifies(hash))
+	require.True(t, argon2id.Compare(hash, "the password"))
+	require.False(t, argon2id.Compare(hash, "another password"))
+	require.False(t, argon2id.NeedsRehash(hash))
+
+	// every hash has its own salt
+	other, err := argon2id.Hash("the password")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+
+	stronger := cheapArgon2id
+	stronger.Iterations = 2
+	strongerArgon2id, err := password.NewArgon2id(stronger)
+	require.NoError(t, err)
+	require.True(t, strongerArgon2id.Compare(hash, "the password"))
+	require.True(t, strongerArgon2id.NeedsRehash(hash))
+
+	for _, malformed := range []string{"$argon2id$", "$argon2id$v=19$m=64,t=1,p=0$c2FsdHNhbHQ$a2V5", "$argon2id$v=19$m=64,t=1,p=1$!$a2V5"} {
+		require.False(t, argon2id.Compare(malformed, "the password"))
+	}
+
+	_, err = password.NewArgon2id(password.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1})
+	require.Error(t, err)
+}
+
+func TestBcrypt(t *testing.T) {
+	b, err := password.NewBcrypt(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	hash, err := b.Hash("the password")
+	require.NoError(t, err)
+	require.True(t, b.Identifies(hash))
+	require.True(t, b.Compare(hash, "the password"))
+	require.False(t, b.Compare(hash, "another password"))
+	require.False(t, b.NeedsRehash(hash))
+
+	costlier, err := password.NewBcrypt(bcrypt.MinCost + 1)
+	require.NoError(t, err)
+	require.True(t, costlier.NeedsRehash(hash))
+
+	_, err = password.NewBcrypt(bcrypt.MaxCost + 1)
+	require.Error(t, err)
+}
+
+func TestHasher(t *testing.T) {
+	argon2id, err := password.NewArgon2id(cheapArgon2id)
+	require.NoError(t, err)
+	b, err := password.NewBcrypt(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	bcryptHash, err := b.Hash("the password")
+	require.NoError(t, err)
+
+	hasher := password.NewHasher(argon2id, b)
+	hash, err := hasher.Hash("the password")
+	require.NoError(t, err)
+	require.True(t, argon2id.Identifies(hash))
+
+	testCases := []struct {
+		name        string
+		hash        string
+		password    string
+		valid       bool
+		needsRehash bool
+	}{
+		{"current algorithm", hash, "the password", true, false},
+		{"wrong password", hash, "another password", false, false},
+		{"earlier algorithm", bcryptHash, "the password", true, true},
+		{"wrong password of earlier algorithm", bcryptHash, "another password", false, false},
+		{"unknown algorithm", "$1$salt$hash", "the password", false, false},
+		{"empty hash", "", "", false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			valid, needsRehash := hasher.Verify(tc.hash, tc.password)
+			require.Equal(t, tc.valid, valid)
+			require.Equal(t, tc.needsRehash, needsRehash)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrTooShort = errors.New("password is too short")
+	ErrTooLong  = errors.New("password is too long")
+	ErrBreached = errors.New("password is too common, it appears in lists of breached passwords")
+)
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// BreachedList is a list of passwords known from breaches, kept as SHA-1 hashes so that lists of hashes like the
+// ones of Have I Been Pwned can be loaded as well as lists of passwords
+type BreachedList struct {
+	hashes map[string]struct{}
+}
+
+// NewBreachedList creates a BreachedList of the built-in common passwords
+func NewBreachedList() *BreachedList {
+	l := &BreachedList{hashes: make(map[string]struct{})}
+	// the built-in list is known to be valid
+	_ = l.Load(strings.NewReader(commonPasswords))
+	return l
+}
+
+// Load adds the passwords of a list of one password or SHA-1 hash per line, a hash may be followed by ":" and the
+// number of times it was seen
+func (l *BreachedList) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if hash, _, _ := strings.Cut(line, ":"); isSHA1Hex(hash) {
+			l.hashes[strings.ToUpper(hash)] = struct{}{}
+			continue
+		}
+		l.hashes[sha1Hex(line)] = struct{}{}
+	}
+
+	return scanner.Err()
+}
+
+// Contains reports whether the password is on the list
+func (l *BreachedList) Contains(password string) bool {
+	_, ok := l.hashes[sha1Hex(password)]
+	return ok
+}
+
+// Policy is what a new password has to be like
+type Policy struct {
+	// MinLength is the least number of characters of a password
+	MinLength int
+	// MaxLength is the most bytes of a password, bcrypt ignores all but the first 72
+	MaxLength int
+	// Breached are the passwords that are refused, none when nil
+	Breached *BreachedList
+}
+
+// Validate returns why the password does not comply with the policy, if it does not
+func (p Policy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("%w, it must be at least %d characters long", ErrTooShort, p.MinLength)
+	}
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return fmt.Errorf("%w, it must be at most %d bytes long", ErrTooLong, p.MaxLength)
+	}
+	if p.Breached != nil && p.Breached.Contains(password) {
+		return ErrBreached
+	}
+
+	return nil
+}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func isSHA1Hex(s string) bool {
+	if len(s) != 2*sha1.Size {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
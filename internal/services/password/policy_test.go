@@ -0,0 +1,32 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/password"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreachedList(t *testing.T) {
+	list := password.NewBreachedList()
+	require.True(t, list.Contains("password123"))
+	require.False(t, list.Contains("correct horse battery staple"))
+
+	// a list may hold passwords or SHA-1 hashes, with or without the number of times they were seen
+	require.NoError(t, list.Load(strings.NewReader("correct horse battery staple\r\n\n29defbab9929a94fd5a06f193dcb8ba716727a66:42\n")))
+	require.True(t, list.Contains("correct horse battery staple"))
+	require.True(t, list.Contains("summer2024!"))
+}
+
+func TestPolicy(t *testing.T) {
+	policy := password.Policy{MinLength: 8, MaxLength: 72, Breached: password.NewBreachedList()}
+
+	require.NoError(t, policy.Validate("a long enough passphrase"))
+	require.NoError(t, policy.Validate("äöüßäöüß"))
+	require.ErrorIs(t, policy.Validate("short"), password.ErrTooShort)
+	require.ErrorIs(t, policy.Validate(strings.Repeat("a", 73)), password.ErrTooLong)
+	require.ErrorIs(t, policy.Validate("qwerty123"), password.ErrBreached)
+
+	require.NoError(t, password.Policy{MinLength: 8}.Validate("qwerty123"))
+}
@@ -0,0 +1,38 @@
+package pricing
+
+import (
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+// Charges are the tax and the service charge added to the price of reservations. TaxRate and ServiceChargeRate
+// are fractions, e.g. 0.09 for 9%. With TaxInclusive the prices of the rules already include the tax, which is
+// only itemized, otherwise it is added on top. The service charge is a share of the net price and is taxed too.
+type Charges struct {
+	TaxRate           float64
+	TaxInclusive      bool
+	ServiceChargeRate float64
+}
+
+// Totals itemizes the price of the rules into the net price, the service charge, the tax and the gross price.
+// Every item is rounded to the minor unit on its own and the gross is their exact sum, so totals reconcile.
+func (c Charges) Totals(price money.Money) reservation.Totals {
+	net, tax := price, price.Multiply(c.TaxRate)
+	if c.TaxInclusive {
+		net = price.Divide(1 + c.TaxRate)
+		tax = price.Sub(net)
+	}
+
+	serviceCharge := net.Multiply(c.ServiceChargeRate)
+	tax = tax.Add(serviceCharge.Multiply(c.TaxRate))
+
+	return reservation.Totals{
+		Net:               net,
+		ServiceCharge:     serviceCharge,
+		Tax:               tax,
+		Gross:             net.Add(serviceCharge).Add(tax),
+		TaxRate:           c.TaxRate,
+		TaxInclusive:      c.TaxInclusive,
+		ServiceChargeRate: c.ServiceChargeRate,
+	}
+}
@@ -0,0 +1,72 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+// Table is a table taken by a reservation
+type Table struct {
+	// TotalSeats is the number of seats of the table
+	TotalSeats int
+	// SeatsTaken is the number of seats of the table the reservation takes
+	SeatsTaken int
+}
+
+// Quote is a reservation to price
+type Quote struct {
+	SeatsCount int
+	Tables     []Table
+	StartAt    time.Time
+	// SeatPrice is the price of a seat when no rule sets another one
+	SeatPrice money.Money
+}
+
+// TotalSeats returns the seats of all the tables of the quote
+func (q Quote) TotalSeats() int {
+	seats := 0
+	for _, t := range q.Tables {
+		seats += t.TotalSeats
+	}
+	return seats
+}
+
+// Rule adds its lines to the price breakdown of a quote, rules are applied in order,
+// so a rule sees the lines of the rules applied before it
+type Rule interface {
+	Apply(quote Quote, breakdown reservation.PriceBreakdown) reservation.PriceBreakdown
+}
+
+// ErrCurrencyMismatch is returned when a quote is in another currency than the amounts of the rules
+var ErrCurrencyMismatch = errors.New("price is in another currency than the pricing rules")
+
+// Engine prices reservations in its currency by applying its rules in order and then its charges
+type Engine struct {
+	currency string
+	charges  Charges
+	rules    []Rule
+}
+
+// NewEngine creates a new pricing engine for the currency the amounts of its rules are in
+func NewEngine(currency string, charges Charges, rules ...Rule) *Engine {
+	return &Engine{currency: currency, charges: charges, rules: rules}
+}
+
+// Price returns the price breakdown of the quote and the totals of its total with the charges,
+// the price of the reservation is the gross of the totals. A seat price of another currency than the engine is
+// refused rather than mixed with the amounts of the rules.
+func (e *Engine) Price(quote Quote) (reservation.PriceBreakdown, reservation.Totals, error) {
+	if quote.SeatPrice.Currency != e.currency {
+		return nil, reservation.Totals{}, fmt.Errorf("%w: seat price is in %s, pricing rules in %s", ErrCurrencyMismatch, quote.SeatPrice.Currency, e.currency)
+	}
+
+	breakdown := reservation.PriceBreakdown{}
+	for _, rule := range e.rules {
+		breakdown = rule.Apply(quote, breakdown)
+	}
+	return breakdown, e.charges.Totals(breakdown.Total()), nil
+}
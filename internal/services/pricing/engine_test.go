@@ -0,0 +1,285 @@
+package pricing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/pricing"
+	"github.com/stretchr/testify/require"
+)
+
+// a Wednesday and a Saturday evening
+var (
+	wednesday = time.Date(2025, 1, 1, 19, 30, 0, 0, time.UTC)
+	saturday  = time.Date(2025, 1, 4, 12, 0, 0, 0, time.UTC)
+)
+
+func usd(amount string) money.Money {
+	return money.MustParse(amount, "USD")
+}
+
+func TestEngine(t *testing.T) {
+	seatPrices := pricing.SeatPrices{BySize: map[int]money.Money{8: usd("12")}}
+
+	testCases := []struct {
+		name         string
+		rules        []pricing.Rule
+		quote        pricing.Quote
+		appliedRules []string
+		total        money.Money
+	}{
+		{
+			name:         "SeatPrice",
+			rules:        []pricing.Rule{seatPrices},
+			quote:        pricing.Quote{SeatsCount: 2, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 2}}, StartAt: wednesday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats},
+			total:        usd("20"),
+		},
+		{
+			name:         "TableSizeSeatPrice",
+			rules:        []pricing.Rule{seatPrices},
+			quote:        pricing.Quote{SeatsCount: 6, Tables: []pricing.Table{{TotalSeats: 8, SeatsTaken: 6}}, StartAt: wednesday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats},
+			total:        usd("72"),
+		},
+		{
+			name:         "FullTableDiscount",
+			rules:        []pricing.Rule{seatPrices, pricing.FullTableDiscount{SeatPrices: seatPrices}},
+			quote:        pricing.Quote{SeatsCount: 4, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 4}}, StartAt: wednesday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats, pricing.RuleFullTableDiscount},
+			total:        usd("30"),
+		},
+		{
+			name:         "NoFullTableDiscountForPartlyTakenTable",
+			rules:        []pricing.Rule{seatPrices, pricing.FullTableDiscount{SeatPrices: seatPrices}},
+			quote:        pricing.Quote{SeatsCount: 2, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 2}}, StartAt: wednesday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats},
+			total:        usd("20"),
+		},
+		{
+			name:  "GroupOfTablesChargesThePartySeatsOnly",
+			rules: []pricing.Rule{seatPrices, pricing.FullTableDiscount{SeatPrices: seatPrices}},
+			quote: pricing.Quote{
+				SeatsCount: 10,
+				Tables:     []pricing.Table{{TotalSeats: 8, SeatsTaken: 8}, {TotalSeats: 4, SeatsTaken: 4}},
+				StartAt:    wednesday,
+				SeatPrice:  usd("10"),
+			},
+			appliedRules: []string{pricing.RuleSeats, pricing.RuleSeats},
+			total:        usd("116"), // 8 x 12 + 2 x 10
+		},
+		{
+			name:  "FullGroupOfTablesGetsTheCheapestSeatOff",
+			rules: []pricing.Rule{seatPrices, pricing.FullTableDiscount{SeatPrices: seatPrices}},
+			quote: pricing.Quote{
+				SeatsCount: 12,
+				Tables:     []pricing.Table{{TotalSeats: 8, SeatsTaken: 8}, {TotalSeats: 4, SeatsTaken: 4}},
+				StartAt:    wednesday,
+				SeatPrice:  usd("10"),
+			},
+			appliedRules: []string{pricing.RuleSeats, pricing.RuleSeats, pricing.RuleFullTableDiscount},
+			total:        usd("126"), // 8 x 12 + 4 x 10 - 10
+		},
+		{
+			name:         "WeekendMultiplier",
+			rules:        []pricing.Rule{seatPrices, pricing.DayOfWeekMultiplier{Weekday: 1, Weekend: 1.5}},
+			quote:        pricing.Quote{SeatsCount: 2, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 2}}, StartAt: saturday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats, pricing.RuleWeekend},
+			total:        usd("30"),
+		},
+		{
+			name:         "WeekdayMultiplier",
+			rules:        []pricing.Rule{seatPrices, pricing.DayOfWeekMultiplier{Weekday: 0.9, Weekend: 1.5}},
+			quote:        pricing.Quote{SeatsCount: 2, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 2}}, StartAt: wednesday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats, pricing.RuleWeekday},
+			total:        usd("18"),
+		},
+		{
+			name: "DayOfWeekInLocation",
+			rules: []pricing.Rule{seatPrices, pricing.DayOfWeekMultiplier{
+				Weekday:  1,
+				Weekend:  1.5,
+				Location: time.FixedZone("UTC-14", -14*60*60),
+			}},
+			// Saturday noon in UTC is still Friday in UTC-14
+			quote:        pricing.Quote{SeatsCount: 2, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 2}}, StartAt: saturday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats},
+			total:        usd("20"),
+		},
+		{
+			name:         "PeakHours",
+			rules:        []pricing.Rule{seatPrices, pricing.PeakHours{From: 19 * time.Hour, To: 21 * time.Hour, Multiplier: 1.25}},
+			quote:        pricing.Quote{SeatsCount: 2, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 2}}, StartAt: wednesday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats, pricing.RulePeakHours},
+			total:        usd("25"),
+		},
+		{
+			name:         "OffPeakHours",
+			rules:        []pricing.Rule{seatPrices, pricing.PeakHours{From: 19 * time.Hour, To: 21 * time.Hour, Multiplier: 1.25}},
+			quote:        pricing.Quote{SeatsCount: 2, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 2}}, StartAt: saturday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats},
+			total:        usd("20"),
+		},
+		{
+			name: "PartyMinimum",
+			rules: []pricing.Rule{seatPrices, pricing.PartyMinimums{
+				{SeatsCount: 1, Amount: usd("15")},
+				{SeatsCount: 6, Amount: usd("100")},
+			}},
+			quote:        pricing.Quote{SeatsCount: 6, Tables: []pricing.Table{{TotalSeats: 8, SeatsTaken: 6}}, StartAt: wednesday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats, pricing.RulePartyMinimum},
+			total:        usd("100"),
+		},
+		{
+			name:         "PartyAboveMinimum",
+			rules:        []pricing.Rule{seatPrices, pricing.PartyMinimums{{SeatsCount: 1, Amount: usd("15")}}},
+			quote:        pricing.Quote{SeatsCount: 2, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 2}}, StartAt: wednesday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats},
+			total:        usd("20"),
+		},
+		{
+			name:         "MultiplierRoundsToMinorUnits",
+			rules:        []pricing.Rule{seatPrices, pricing.PeakHours{From: 19 * time.Hour, To: 21 * time.Hour, Multiplier: 1.15}},
+			quote:        pricing.Quote{SeatsCount: 3, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 3}}, StartAt: wednesday, SeatPrice: usd("3.33")},
+			appliedRules: []string{pricing.RuleSeats, pricing.RulePeakHours},
+			total:        usd("11.49"), // 9.99 x 1.15 = 11.4885
+		},
+		{
+			name: "RulesCompose",
+			rules: []pricing.Rule{
+				seatPrices,
+				pricing.FullTableDiscount{SeatPrices: seatPrices},
+				pricing.DayOfWeekMultiplier{Weekday: 1, Weekend: 1.5},
+				pricing.PeakHours{From: 12 * time.Hour, To: 14 * time.Hour, Multiplier: 2},
+			},
+			quote:        pricing.Quote{SeatsCount: 4, Tables: []pricing.Table{{TotalSeats: 4, SeatsTaken: 4}}, StartAt: saturday, SeatPrice: usd("10")},
+			appliedRules: []string{pricing.RuleSeats, pricing.RuleFullTableDiscount, pricing.RuleWeekend, pricing.RulePeakHours},
+			// (40 - 10) * 1.5 * 2
+			total: usd("90"),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			breakdown, totals, err := pricing.NewEngine("USD", pricing.Charges{}, tc.rules...).Price(tc.quote)
+			require.NoError(t, err)
+
+			rules := make([]string, 0, len(breakdown))
+			for _, line := range breakdown {
+				rules = append(rules, line.Rule)
+				require.NotEmpty(t, line.Description)
+			}
+			require.Equal(t, tc.appliedRules, rules)
+			require.Equal(t, tc.total, breakdown.Total())
+			require.Equal(t, tc.total, totals.Gross)
+		})
+	}
+}
+
+func TestEngineWithoutRules(t *testing.T) {
+	breakdown, totals, err := pricing.NewEngine("USD", pricing.Charges{}).Price(pricing.Quote{SeatsCount: 2, StartAt: wednesday, SeatPrice: usd("10")})
+	require.NoError(t, err)
+	require.Equal(t, reservation.PriceBreakdown{}, breakdown)
+	require.True(t, breakdown.Total().IsZero())
+	require.True(t, totals.Gross.IsZero())
+}
+
+func TestEngineCurrencyMismatch(t *testing.T) {
+	// seat prices stored in another currency than the one the rules are configured in are not mixed with them
+	engine := pricing.NewEngine("EUR", pricing.Charges{}, pricing.SeatPrices{}, pricing.PartyMinimums{{SeatsCount: 2, Amount: money.MustParse("50", "EUR")}})
+	_, _, err := engine.Price(pricing.Quote{SeatsCount: 2, Tables: []pricing.Table{{TotalSeats: 2, SeatsTaken: 2}}, StartAt: wednesday, SeatPrice: usd("10")})
+	require.ErrorIs(t, err, pricing.ErrCurrencyMismatch)
+}
+
+func TestCharges(t *testing.T) {
+	testCases := []struct {
+		name          string
+		charges       pricing.Charges
+		price         money.Money
+		net           money.Money
+		serviceCharge money.Money
+		tax           money.Money
+		gross         money.Money
+	}{
+		{
+			name:          "NoCharges",
+			charges:       pricing.Charges{},
+			price:         usd("40"),
+			net:           usd("40"),
+			serviceCharge: usd("0"),
+			tax:           usd("0"),
+			gross:         usd("40"),
+		},
+		{
+			name:          "ExclusiveTax",
+			charges:       pricing.Charges{TaxRate: 0.09},
+			price:         usd("40"),
+			net:           usd("40"),
+			serviceCharge: usd("0"),
+			tax:           usd("3.60"),
+			gross:         usd("43.60"),
+		},
+		{
+			name:          "InclusiveTax",
+			charges:       pricing.Charges{TaxRate: 0.09, TaxInclusive: true},
+			price:         usd("40"),
+			net:           usd("36.70"), // 40 / 1.09 = 36.697...
+			serviceCharge: usd("0"),
+			tax:           usd("3.30"),
+			gross:         usd("40"),
+		},
+		{
+			name:          "ServiceChargeWithoutTax",
+			charges:       pricing.Charges{ServiceChargeRate: 0.125},
+			price:         usd("40"),
+			net:           usd("40"),
+			serviceCharge: usd("5"),
+			tax:           usd("0"),
+			gross:         usd("45"),
+		},
+		{
+			name:          "ServiceChargeIsTaxed",
+			charges:       pricing.Charges{TaxRate: 0.2, ServiceChargeRate: 0.1},
+			price:         usd("40"),
+			net:           usd("40"),
+			serviceCharge: usd("4"),
+			tax:           usd("8.80"), // 40 x 0.2 + 4 x 0.2
+			gross:         usd("52.80"),
+		},
+		{
+			name:          "InclusiveTaxWithServiceCharge",
+			charges:       pricing.Charges{TaxRate: 0.09, TaxInclusive: true, ServiceChargeRate: 0.1},
+			price:         usd("40"),
+			net:           usd("36.70"),
+			serviceCharge: usd("3.67"),
+			tax:           usd("3.63"), // 3.30 + 3.67 x 0.09 = 3.30 + 0.33
+			gross:         usd("44"),
+		},
+		{
+			name:          "ItemsRoundToMinorUnitsAndReconcile",
+			charges:       pricing.Charges{TaxRate: 0.0725, ServiceChargeRate: 0.18},
+			price:         usd("33.33"),
+			net:           usd("33.33"),
+			serviceCharge: usd("6.00"), // 5.9994
+			tax:           usd("2.86"), // 2.416425 rounds to 2.42 and 6.00 x 0.0725 = 0.435 to 0.44, ties to even
+			gross:         usd("42.19"),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			totals := tc.charges.Totals(tc.price)
+			require.Equal(t, tc.net, totals.Net)
+			require.Equal(t, tc.serviceCharge, totals.ServiceCharge)
+			require.Equal(t, tc.tax, totals.Tax)
+			require.Equal(t, tc.gross, totals.Gross)
+			require.Equal(t, totals.Gross, totals.Net.Add(totals.ServiceCharge).Add(totals.Tax))
+		})
+	}
+}
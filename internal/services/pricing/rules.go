@@ -0,0 +1,171 @@
+package pricing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/money"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/reservation"
+)
+
+const (
+	RuleSeats             = "seats"
+	RuleFullTableDiscount = "full_table_discount"
+	RuleWeekday           = "weekday"
+	RuleWeekend           = "weekend"
+	RulePeakHours         = "peak_hours"
+	RulePartyMinimum      = "party_minimum"
+)
+
+// SeatPrices charges every seat of the party at the table it sits at, BySize sets the price of a seat for tables
+// of a number of seats and the seat price of the quote is charged for tables of any other size. A party taking a
+// group of tables as a whole only pays for its own seats, filling the tables in order.
+type SeatPrices struct {
+	BySize map[int]money.Money
+}
+
+// seatPrice returns the price of a seat at a table of totalSeats seats
+func (r SeatPrices) seatPrice(quote Quote, totalSeats int) money.Money {
+	if price, ok := r.BySize[totalSeats]; ok {
+		return price
+	}
+	return quote.SeatPrice
+}
+
+// Apply adds a line for every table the party sits at
+func (r SeatPrices) Apply(quote Quote, breakdown reservation.PriceBreakdown) reservation.PriceBreakdown {
+	remaining := quote.SeatsCount
+	for _, t := range quote.Tables {
+		seats := min(remaining, t.SeatsTaken)
+		if seats <= 0 {
+			break
+		}
+		remaining -= seats
+
+		seatPrice := r.seatPrice(quote, t.TotalSeats)
+		breakdown = append(breakdown, reservation.PriceLine{
+			Rule:        RuleSeats,
+			Description: fmt.Sprintf("%d seats at a table of %d seats, %s each", seats, t.TotalSeats, seatPrice),
+			Amount:      money.New(int64(seats)*seatPrice.Amount, seatPrice.Currency),
+		})
+	}
+	return breakdown
+}
+
+// FullTableDiscount takes the price of one seat off a party that takes all the seats of its tables,
+// the cheapest seat of the tables when seats of the tables are priced differently
+type FullTableDiscount struct {
+	SeatPrices SeatPrices
+}
+
+// Apply adds the discount line when the party fills its tables
+func (r FullTableDiscount) Apply(quote Quote, breakdown reservation.PriceBreakdown) reservation.PriceBreakdown {
+	if len(quote.Tables) == 0 || quote.SeatsCount != quote.TotalSeats() {
+		return breakdown
+	}
+
+	cheapest := r.SeatPrices.seatPrice(quote, quote.Tables[0].TotalSeats)
+	for _, t := range quote.Tables[1:] {
+		if price := r.SeatPrices.seatPrice(quote, t.TotalSeats); price.Cmp(cheapest) < 0 {
+			cheapest = price
+		}
+	}
+
+	return append(breakdown, reservation.PriceLine{
+		Rule:        RuleFullTableDiscount,
+		Description: "one seat free for a party filling its tables",
+		Amount:      cheapest.Neg(),
+	})
+}
+
+// DayOfWeekMultiplier multiplies the price of reservations starting on a weekday or on the weekend, in
+// Location or in the location of the start time when nil. A zero or one multiplier leaves the price as is.
+type DayOfWeekMultiplier struct {
+	Weekday  float64
+	Weekend  float64
+	Location *time.Location
+}
+
+// Apply adds the line of the multiplier of the day the reservation starts
+func (r DayOfWeekMultiplier) Apply(quote Quote, breakdown reservation.PriceBreakdown) reservation.PriceBreakdown {
+	startAt := inLocation(quote.StartAt, r.Location)
+
+	rule, multiplier := RuleWeekday, r.Weekday
+	if startAt.Weekday() == time.Saturday || startAt.Weekday() == time.Sunday {
+		rule, multiplier = RuleWeekend, r.Weekend
+	}
+
+	return applyMultiplier(breakdown, rule, fmt.Sprintf("%s price, x%.2f", startAt.Weekday(), multiplier), multiplier)
+}
+
+// PeakHours multiplies the price of reservations starting from From up to To, both durations since midnight in
+// Location or in the location of the start time when nil
+type PeakHours struct {
+	From       time.Duration
+	To         time.Duration
+	Multiplier float64
+	Location   *time.Location
+}
+
+// Apply adds the line of the multiplier when the reservation starts in the peak hours
+func (r PeakHours) Apply(quote Quote, breakdown reservation.PriceBreakdown) reservation.PriceBreakdown {
+	startAt := inLocation(quote.StartAt, r.Location)
+	sinceMidnight := time.Duration(startAt.Hour())*time.Hour + time.Duration(startAt.Minute())*time.Minute
+	if sinceMidnight < r.From || sinceMidnight >= r.To {
+		return breakdown
+	}
+
+	return applyMultiplier(breakdown, RulePeakHours, fmt.Sprintf("peak hours price, x%.2f", r.Multiplier), r.Multiplier)
+}
+
+// PartyMinimum is the minimum price of a reservation of at least SeatsCount seats
+type PartyMinimum struct {
+	SeatsCount int
+	Amount     money.Money
+}
+
+// PartyMinimums raises the price of a party up to the highest minimum of the party size
+type PartyMinimums []PartyMinimum
+
+// Apply adds the line that raises the price up to the minimum when the price is below it
+func (r PartyMinimums) Apply(quote Quote, breakdown reservation.PriceBreakdown) reservation.PriceBreakdown {
+	var minimum money.Money
+	for _, m := range r {
+		if quote.SeatsCount >= m.SeatsCount && m.Amount.Cmp(minimum) > 0 {
+			minimum = m.Amount
+		}
+	}
+
+	total := breakdown.Total()
+	if total.Cmp(minimum) >= 0 {
+		return breakdown
+	}
+
+	return append(breakdown, reservation.PriceLine{
+		Rule:        RulePartyMinimum,
+		Description: fmt.Sprintf("minimum price of %s for a party of %d", minimum, quote.SeatsCount),
+		Amount:      minimum.Sub(total),
+	})
+}
+
+// applyMultiplier adds a line with the amount multiplier adds to the total of the breakdown, the multiplied
+// total is rounded to the minor unit and the line is the difference, so the lines always add up to it
+func applyMultiplier(breakdown reservation.PriceBreakdown, rule, description string, multiplier float64) reservation.PriceBreakdown {
+	if multiplier == 0 || multiplier == 1 {
+		return breakdown
+	}
+
+	total := breakdown.Total()
+	return append(breakdown, reservation.PriceLine{
+		Rule:        rule,
+		Description: description,
+		Amount:      total.Multiply(multiplier).Sub(total),
+	})
+}
+
+func inLocation(t time.Time, location *time.Location) time.Time {
+	if location == nil {
+		return t
+	}
+	return t.In(location)
+}
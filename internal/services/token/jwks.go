@@ -0,0 +1,48 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+// KeyPublisher is a Manager whose tokens can be verified by other services with its public keys
+type KeyPublisher interface {
+	PublicKeys() JWKS
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517)
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a public key in the JSON Web Key format, RSA keys carry n and e and Ed25519 keys carry crv and x
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// newJWK converts a key of a KeyManager, which only holds RSA and Ed25519 keys, to a JWK
+func newJWK(key Key) JWK {
+	jwk := JWK{KeyID: key.ID, Use: "sig"}
+	switch publicKey := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.Algorithm = "RS256"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Algorithm = "EdDSA"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+	return jwk
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
 )
 
 const minSecretLength = 32
@@ -25,13 +26,18 @@ func NewJWTManger(secretKey string) (Manager, error) {
 	}, nil
 }
 
-// GenerateToken generates a new JWT token
-func (m *JWTManager) GenerateToken(userID int, duration time.Duration) (string, error) {
-	payload, err := NewPayload(userID, duration)
+// GenerateToken generates a new JWT token for a user with the given role
+func (m *JWTManager) GenerateToken(userID int, role user.Role, duration time.Duration) (string, error) {
+	payload, err := NewPayload(userID, role, duration)
 	if err != nil {
 		return "", err
 	}
 
+	return m.SignToken(payload)
+}
+
+// SignToken signs a JWT token carrying the payload
+func (m *JWTManager) SignToken(payload *Payload) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
 	return token.SignedString([]byte(m.secretKey))
 }
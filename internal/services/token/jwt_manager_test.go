@@ -7,6 +7,7 @@ import (
 
 	"github.com/bxcodec/faker/v3"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
 	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
 	"github.com/stretchr/testify/require"
 )
@@ -18,7 +19,7 @@ func TestJWTManager(t *testing.T) {
 
 	userID := 1
 	duration := time.Minute
-	tokenString, err := jwtManager.GenerateToken(userID, duration)
+	tokenString, err := jwtManager.GenerateToken(userID, user.RoleHost, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, tokenString)
 
@@ -26,6 +27,7 @@ func TestJWTManager(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 	require.Equal(t, userID, payload.UserID)
+	require.Equal(t, user.RoleHost, payload.Role)
 	require.WithinDuration(t, time.Now(), payload.IssuedAt.Time, time.Second)
 	require.WithinDuration(t, time.Now().Add(duration), payload.ExpiresAt.Time, time.Second)
 }
@@ -35,7 +37,7 @@ func TestJWTManagerWithExpiredToken(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, jwtManager)
 
-	tokenString, err := jwtManager.GenerateToken(1, -time.Minute)
+	tokenString, err := jwtManager.GenerateToken(1, user.RoleGuest, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, tokenString)
 
@@ -50,7 +52,7 @@ func TestJWTManagerInvalidToken(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, jwtManager)
 
-	payload, err := token.NewPayload(1, time.Minute)
+	payload, err := token.NewPayload(1, user.RoleGuest, time.Minute)
 	require.NoError(t, err)
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
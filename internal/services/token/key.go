@@ -0,0 +1,115 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSAKeyBits = 2048
+
+// Key is a key tokens are verified with, along with the private key signing them while it is the active key
+type Key struct {
+	ID string
+	// PrivateKey is nil for the keys only kept to verify the tokens they signed before being rotated out
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// NewKey creates a Key of an RSA or Ed25519 private key
+func NewKey(id string, privateKey crypto.Signer) (Key, error) {
+	key := Key{ID: id, PrivateKey: privateKey, PublicKey: privateKey.Public()}
+	if _, err := key.signingMethod(); err != nil {
+		return Key{}, err
+	}
+	return key, nil
+}
+
+// LoadKey loads a key from PEM files, the private key file takes precedence and the public key file is only read
+// when it is empty
+func LoadKey(id string, privateKeyFile string, publicKeyFile string) (Key, error) {
+	if privateKeyFile != "" {
+		block, err := readPEM(privateKeyFile)
+		if err != nil {
+			return Key{}, err
+		}
+
+		privateKey, err := parsePrivateKey(block)
+		if err != nil {
+			return Key{}, fmt.Errorf("could not parse private key %s: %w", id, err)
+		}
+		return NewKey(id, privateKey)
+	}
+
+	if publicKeyFile == "" {
+		return Key{}, fmt.Errorf("key %s has neither a private nor a public key file", id)
+	}
+
+	block, err := readPEM(publicKeyFile)
+	if err != nil {
+		return Key{}, err
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return Key{}, fmt.Errorf("could not parse public key %s: %w", id, err)
+	}
+
+	key := Key{ID: id, PublicKey: publicKey}
+	if _, err := key.signingMethod(); err != nil {
+		return Key{}, err
+	}
+	return key, nil
+}
+
+// signingMethod returns the JWT signing method of the key type, RS256 for RSA keys and EdDSA for Ed25519 keys
+func (k Key) signingMethod() (jwt.SigningMethod, error) {
+	switch publicKey := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if publicKey.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key %s must be at least %d bits", k.ID, minRSAKeyBits)
+		}
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("key %s is of unsupported type %T, only RSA and Ed25519 keys are supported", k.ID, k.PublicKey)
+	}
+}
+
+func readPEM(file string) (*pem.Block, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", file)
+	}
+	return block, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key can not sign")
+	}
+	return signer, nil
+}
@@ -0,0 +1,108 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
+
+// KeyManager is a Manager signing tokens with RS256 or EdDSA keys. Tokens name the key that signed them in their
+// kid header, so the active key can be rotated while the tokens signed by the previous keys remain valid as long
+// as those keys are kept.
+type KeyManager struct {
+	keys      map[string]Key
+	keyIDs    []string
+	activeKey Key
+}
+
+// NewKeyManager creates a new KeyManager signing tokens with the key of activeKeyID
+func NewKeyManager(keys []Key, activeKeyID string) (Manager, error) {
+	m := &KeyManager{keys: make(map[string]Key, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("key id is required")
+		}
+		if _, ok := m.keys[key.ID]; ok {
+			return nil, fmt.Errorf("key id %s is used twice", key.ID)
+		}
+		if _, err := key.signingMethod(); err != nil {
+			return nil, err
+		}
+		m.keys[key.ID] = key
+		m.keyIDs = append(m.keyIDs, key.ID)
+	}
+
+	activeKey, ok := m.keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active key %s is not one of the keys", activeKeyID)
+	}
+	if activeKey.PrivateKey == nil {
+		return nil, fmt.Errorf("active key %s has no private key to sign with", activeKeyID)
+	}
+	m.activeKey = activeKey
+
+	return m, nil
+}
+
+// GenerateToken generates a new JWT token for a user with the given role
+func (m *KeyManager) GenerateToken(userID int, role user.Role, duration time.Duration) (string, error) {
+	payload, err := NewPayload(userID, role, duration)
+	if err != nil {
+		return "", err
+	}
+
+	return m.SignToken(payload)
+}
+
+// SignToken signs a JWT token carrying the payload with the active key
+func (m *KeyManager) SignToken(payload *Payload) (string, error) {
+	method, err := m.activeKey.signingMethod()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, payload)
+	token.Header["kid"] = m.activeKey.ID
+	return token.SignedString(m.activeKey.PrivateKey)
+}
+
+// VerifyToken verifies a JWT token with the key named by its kid header
+func (m *KeyManager) VerifyToken(tokenString string) (*Payload, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Payload{}, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		key, ok := m.keys[keyID]
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		// the algorithm is bound to the key, so a token can not pick a weaker one
+		method, err := key.signingMethod()
+		if err != nil || token.Method.Alg() != method.Alg() {
+			return nil, ErrInvalidToken
+		}
+		return key.PublicKey, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	payload, ok := token.Claims.(*Payload)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	return payload, nil
+}
+
+// PublicKeys returns the keys tokens are verified with, in the order they were given
+func (m *KeyManager) PublicKeys() JWKS {
+	jwks := JWKS{Keys: make([]JWK, 0, len(m.keyIDs))}
+	for _, id := range m.keyIDs {
+		jwks.Keys = append(jwks.Keys, newJWK(m.keys[id]))
+	}
+	return jwks
+}
@@ -0,0 +1,162 @@
+package token_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+)
+
+func newRSAKey(t *testing.T, id string) token.Key {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key, err := token.NewKey(id, privateKey)
+	require.NoError(t, err)
+	return key
+}
+
+func newEd25519Key(t *testing.T, id string) token.Key {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := token.NewKey(id, privateKey)
+	require.NoError(t, err)
+	return key
+}
+
+func TestKeyManager(t *testing.T) {
+	for _, key := range []token.Key{newRSAKey(t, "rsa"), newEd25519Key(t, "ed25519")} {
+		t.Run(key.ID, func(t *testing.T) {
+			keyManager, err := token.NewKeyManager([]token.Key{key}, key.ID)
+			require.NoError(t, err)
+
+			tokenString, err := keyManager.GenerateToken(1, user.RoleHost, time.Minute)
+			require.NoError(t, err)
+
+			payload, err := keyManager.VerifyToken(tokenString)
+			require.NoError(t, err)
+			require.Equal(t, 1, payload.UserID)
+			require.Equal(t, user.RoleHost, payload.Role)
+
+			expired, err := keyManager.GenerateToken(1, user.RoleHost, -time.Minute)
+			require.NoError(t, err)
+			_, err = keyManager.VerifyToken(expired)
+			require.True(t, errors.Is(err, jwt.ErrTokenExpired))
+		})
+	}
+}
+
+func TestKeyManagerRotation(t *testing.T) {
+	oldKey := newRSAKey(t, "2024-04")
+	newKey := newEd25519Key(t, "2024-10")
+
+	before, err := token.NewKeyManager([]token.Key{oldKey}, oldKey.ID)
+	require.NoError(t, err)
+	oldToken, err := before.GenerateToken(1, user.RoleGuest, time.Minute)
+	require.NoError(t, err)
+
+	// the old key is kept without its private key, only to verify the tokens it signed
+	retiredKey := token.Key{ID: oldKey.ID, PublicKey: oldKey.PublicKey}
+	after, err := token.NewKeyManager([]token.Key{newKey, retiredKey}, newKey.ID)
+	require.NoError(t, err)
+
+	_, err = after.VerifyToken(oldToken)
+	require.NoError(t, err)
+
+	newToken, err := after.GenerateToken(1, user.RoleGuest, time.Minute)
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &token.Payload{})
+	require.NoError(t, err)
+	require.Equal(t, newKey.ID, parsed.Header["kid"])
+	require.Equal(t, "EdDSA", parsed.Method.Alg())
+
+	// once the old key is dropped its tokens are rejected
+	dropped, err := token.NewKeyManager([]token.Key{newKey}, newKey.ID)
+	require.NoError(t, err)
+	_, err = dropped.VerifyToken(oldToken)
+	require.True(t, errors.Is(err, token.ErrInvalidToken))
+}
+
+func TestKeyManagerInvalidKeys(t *testing.T) {
+	key := newRSAKey(t, "a")
+
+	_, err := token.NewKeyManager([]token.Key{key}, "b")
+	require.Error(t, err)
+
+	_, err = token.NewKeyManager([]token.Key{key, key}, "a")
+	require.Error(t, err)
+
+	_, err = token.NewKeyManager([]token.Key{{ID: "a", PublicKey: key.PublicKey}}, "a")
+	require.Error(t, err)
+
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, err = token.NewKey("weak", weakKey)
+	require.Error(t, err)
+}
+
+func TestKeyManagerRejectsForgedTokens(t *testing.T) {
+	key := newRSAKey(t, "rsa")
+	keyManager, err := token.NewKeyManager([]token.Key{key}, key.ID)
+	require.NoError(t, err)
+
+	payload, err := token.NewPayload(1, user.RoleAdmin, time.Minute)
+	require.NoError(t, err)
+
+	// an HS256 token using the public key of the kid as its secret
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	forged.Header["kid"] = key.ID
+	publicKey := key.PublicKey.(*rsa.PublicKey)
+	tokenString, err := forged.SignedString(publicKey.N.Bytes())
+	require.NoError(t, err)
+	_, err = keyManager.VerifyToken(tokenString)
+	require.True(t, errors.Is(err, token.ErrInvalidToken))
+
+	// a token without a kid
+	unnamed := jwt.NewWithClaims(jwt.SigningMethodRS256, payload)
+	tokenString, err = unnamed.SignedString(key.PrivateKey)
+	require.NoError(t, err)
+	_, err = keyManager.VerifyToken(tokenString)
+	require.True(t, errors.Is(err, token.ErrInvalidToken))
+}
+
+func TestKeyManagerPublicKeys(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa")
+	edKey := newEd25519Key(t, "ed25519")
+	keyManager, err := token.NewKeyManager([]token.Key{rsaKey, edKey}, rsaKey.ID)
+	require.NoError(t, err)
+
+	publisher, ok := keyManager.(token.KeyPublisher)
+	require.True(t, ok)
+
+	jwks := publisher.PublicKeys()
+	require.Len(t, jwks.Keys, 2)
+
+	rsaJWK := jwks.Keys[0]
+	require.Equal(t, "RSA", rsaJWK.KeyType)
+	require.Equal(t, "rsa", rsaJWK.KeyID)
+	require.Equal(t, "RS256", rsaJWK.Algorithm)
+	n, err := base64.RawURLEncoding.DecodeString(rsaJWK.N)
+	require.NoError(t, err)
+	e, err := base64.RawURLEncoding.DecodeString(rsaJWK.E)
+	require.NoError(t, err)
+	publicKey := rsaKey.PublicKey.(*rsa.PublicKey)
+	require.Equal(t, publicKey.N, new(big.Int).SetBytes(n))
+	require.Equal(t, publicKey.E, int(new(big.Int).SetBytes(e).Int64()))
+
+	edJWK := jwks.Keys[1]
+	require.Equal(t, "OKP", edJWK.KeyType)
+	require.Equal(t, "Ed25519", edJWK.Curve)
+	require.Equal(t, "EdDSA", edJWK.Algorithm)
+	x, err := base64.RawURLEncoding.DecodeString(edJWK.X)
+	require.NoError(t, err)
+	require.Equal(t, []byte(edKey.PublicKey.(ed25519.PublicKey)), x)
+}
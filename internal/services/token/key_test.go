@@ -0,0 +1,62 @@
+package token_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	return writeFile(t, string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})))
+}
+
+func TestLoadKey(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	signingKey, err := token.LoadKey("signing", writePEM(t, "PRIVATE KEY", privateDER), "")
+	require.NoError(t, err)
+	require.NotNil(t, signingKey.PrivateKey)
+
+	verifyingKey, err := token.LoadKey("verifying", "", writePEM(t, "PUBLIC KEY", publicDER))
+	require.NoError(t, err)
+	require.Nil(t, verifyingKey.PrivateKey)
+
+	// a token signed with the private key file verifies with the public key file
+	signer, err := token.NewKeyManager([]token.Key{signingKey}, "signing")
+	require.NoError(t, err)
+	tokenString, err := signer.GenerateToken(1, user.RoleGuest, time.Minute)
+	require.NoError(t, err)
+
+	verifyingKey.ID = "signing"
+	verifier, err := token.NewKeyManager([]token.Key{verifyingKey, {ID: "other", PrivateKey: privateKey, PublicKey: publicKey}}, "other")
+	require.NoError(t, err)
+	_, err = verifier.VerifyToken(tokenString)
+	require.NoError(t, err)
+
+	_, err = token.LoadKey("missing", "", "")
+	require.Error(t, err)
+
+	_, err = token.LoadKey("not a pem", writeFile(t, "not a pem"), "")
+	require.Error(t, err)
+}
+
+func writeFile(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+	return file
+}
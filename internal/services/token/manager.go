@@ -1,8 +1,13 @@
 package token
 
-import "time"
+import (
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
+)
 
 type Manager interface {
-	GenerateToken(userID int, duration time.Duration) (string, error)
+	GenerateToken(userID int, role user.Role, duration time.Duration) (string, error)
+	SignToken(payload *Payload) (string, error)
 	VerifyToken(token string) (*Payload, error)
 }
@@ -0,0 +1,29 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const opaqueTokenSize = 32
+
+// NewOpaqueToken generates a random opaque token, e.g. a refresh or password reset token, along with the hash it
+// is stored by
+func NewOpaqueToken() (opaqueToken string, hash string, err error) {
+	b := make([]byte, opaqueTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	opaqueToken = base64.RawURLEncoding.EncodeToString(b)
+	return opaqueToken, HashOpaqueToken(opaqueToken), nil
+}
+
+// HashOpaqueToken returns the hash an opaque token is stored by, opaque tokens are random enough for a plain
+// SHA-256 to be safe
+func HashOpaqueToken(opaqueToken string) string {
+	sum := sha256.Sum256([]byte(opaqueToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,20 @@
+package token_test
+
+import (
+	"testing"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOpaqueToken(t *testing.T) {
+	opaqueToken, hash, err := token.NewOpaqueToken()
+	require.NoError(t, err)
+	require.NotEmpty(t, opaqueToken)
+	require.Equal(t, token.HashOpaqueToken(opaqueToken), hash)
+	require.NotEqual(t, opaqueToken, hash)
+
+	other, _, err := token.NewOpaqueToken()
+	require.NoError(t, err)
+	require.NotEqual(t, opaqueToken, other)
+}
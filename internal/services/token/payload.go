@@ -6,6 +6,7 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/domains/user"
 )
 
 var (
@@ -13,14 +14,27 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// PurposeTwoFactorChallenge is the purpose of the tokens a login with a correct password is given in exchange for
+// a code of the second factor
+const PurposeTwoFactorChallenge = "two_factor_challenge"
+
 // Payload is a struct that holds the claims for JWT
 type Payload struct {
-	UserID int `json:"username"`
+	UserID int       `json:"username"`
+	Role   user.Role `json:"role"`
+	// TwoFactor tells whether the login the token was issued for was confirmed with a second factor
+	TwoFactor bool `json:"two_factor,omitempty"`
+	// SessionID is the session an access token was issued for
+	SessionID int `json:"sid,omitempty"`
+	// AuthTime is when the user logged in to start the session an access token was issued for
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	// Purpose is what a token other than an access token is for, access tokens have none
+	Purpose string `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // NewPayload creates a new Payload
-func NewPayload(userID int, duration time.Duration) (*Payload, error) {
+func NewPayload(userID int, role user.Role, duration time.Duration) (*Payload, error) {
 	id, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -28,6 +42,7 @@ func NewPayload(userID int, duration time.Duration) (*Payload, error) {
 
 	payload := &Payload{
 		UserID: userID,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        id.String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
@@ -36,3 +51,8 @@ func NewPayload(userID int, duration time.Duration) (*Payload, error) {
 	}
 	return payload, nil
 }
+
+// IsAccessToken reports whether the token is an access token, rather than a token for a single purpose
+func (p *Payload) IsAccessToken() bool {
+	return p.Purpose == ""
+}
@@ -0,0 +1,52 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RevocationList keeps the IDs of the access tokens revoked before they expire
+type RevocationList interface {
+	Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// MemoryRevocationList is a RevocationList of a single process
+type MemoryRevocationList struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+// NewMemoryRevocationList creates a new MemoryRevocationList
+func NewMemoryRevocationList() *MemoryRevocationList {
+	return &MemoryRevocationList{
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// Revoke adds a token to the list until it expires
+func (l *MemoryRevocationList) Revoke(_ context.Context, tokenID string, expiresAt time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// expired tokens are rejected anyway, so they are dropped to keep the list small
+	now := time.Now()
+	for id, at := range l.revoked {
+		if !at.After(now) {
+			delete(l.revoked, id)
+		}
+	}
+
+	l.revoked[tokenID] = expiresAt
+	return nil
+}
+
+// IsRevoked reports whether the token was revoked
+func (l *MemoryRevocationList) IsRevoked(_ context.Context, tokenID string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, ok := l.revoked[tokenID]
+	return ok, nil
+}
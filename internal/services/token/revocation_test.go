@@ -0,0 +1,32 @@
+package token_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRevocationList(t *testing.T) {
+	ctx := context.Background()
+	list := token.NewMemoryRevocationList()
+
+	revoked, err := list.IsRevoked(ctx, "a")
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	require.NoError(t, list.Revoke(ctx, "a", time.Now().Add(time.Minute)))
+	require.NoError(t, list.Revoke(ctx, "b", time.Now().Add(-time.Minute)))
+
+	revoked, err = list.IsRevoked(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	// revoking another token drops the expired ones
+	require.NoError(t, list.Revoke(ctx, "c", time.Now().Add(time.Minute)))
+	revoked, err = list.IsRevoked(ctx, "b")
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
@@ -0,0 +1,75 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/mohammad19khodaei/restaurant_reservation/internal/services/totp"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 secret of the test vectors of RFC 6238
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// the 8 digit codes of RFC 6238 appendix B, of which 6 digit codes are the last 6 digits
+	testCases := []struct {
+		at   int64
+		code string
+	}{
+		{at: 59, code: "287082"},
+		{at: 1111111109, code: "081804"},
+		{at: 1111111111, code: "050471"},
+		{at: 1234567890, code: "005924"},
+		{at: 2000000000, code: "279037"},
+		{at: 20000000000, code: "353130"},
+	}
+
+	for _, tc := range testCases {
+		code, err := totp.Code(rfcSecret, totp.Step(time.Unix(tc.at, 0)))
+		require.NoError(t, err)
+		require.Equal(t, tc.code, code, tc.at)
+	}
+
+	_, err := totp.Code("not base32!", 1)
+	require.ErrorIs(t, err, totp.ErrInvalidSecret)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	previous, err := totp.Code(secret, totp.Step(now)-1)
+	require.NoError(t, err)
+	stale, err := totp.Code(secret, totp.Step(now)-2)
+	require.NoError(t, err)
+
+	// the code of the previous period is still accepted, the one before is not
+	step, ok := totp.Validate(secret, previous, now, 1)
+	require.True(t, ok)
+	require.Equal(t, totp.Step(now)-1, step)
+
+	if stale != previous {
+		_, ok = totp.Validate(secret, stale, now, 1)
+		require.False(t, ok)
+	}
+
+	_, ok = totp.Validate(secret, "12345", now, 1)
+	require.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(totp.URI("Restaurant Reservation", "guest1", "JBSWY3DPEHPK3PXP"))
+	require.NoError(t, err)
+
+	require.Equal(t, "otpauth", uri.Scheme)
+	require.Equal(t, "totp", uri.Host)
+	require.Equal(t, "/Restaurant Reservation:guest1", uri.Path)
+	require.Equal(t, "JBSWY3DPEHPK3PXP", uri.Query().Get("secret"))
+	require.Equal(t, "Restaurant Reservation", uri.Query().Get("issuer"))
+	require.Equal(t, "6", uri.Query().Get("digits"))
+	require.Equal(t, "30", uri.Query().Get("period"))
+}
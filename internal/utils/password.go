@@ -1,21 +0,0 @@
-package utils
-
-import (
-	"errors"
-
-	"golang.org/x/crypto/bcrypt"
-)
-
-func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", errors.New("could not hash the provided password")
-	}
-
-	return string(hashedPassword), nil
-}
-
-func IsHashPasswordValid(hashedPassword string, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil
-}